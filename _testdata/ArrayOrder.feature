@@ -0,0 +1,23 @@
+Feature: Unordered JSON arrays
+
+  Scenario: Array order matters by default
+    When I request HTTP endpoint with method "GET" and URI "/items"
+    Then I should have response with body, that matches JSON
+    """
+    {"items":[{"id":1},{"id":2},{"id":3}]}
+    """
+
+  Scenario: Array elements are matched in any order
+    When I request HTTP endpoint with method "GET" and URI "/items"
+    Then I should have response with body, that matches JSON ignoring array order
+    """
+    {"items":[{"id":1,"tags":["b","a"]},{"id":2},{"id":3}],"total":"<ignore-diff>"}
+    """
+
+  Scenario: Missing element is reported
+    When I request HTTP endpoint with method "GET" and URI "/items"
+    Then I should have response with body, that matches JSON ignoring array order
+    """
+    {"items":[{"id":1},{"id":2},{"id":4}]}
+    """
+
@@ -29,3 +29,33 @@ Feature: Attachment file in steps
     """json
     {"content":"a b c"}
     """
+
+  Scenario: POST with body attachment with content type
+    When I request HTTP endpoint with method "POST" and URI "/file-content-type"
+
+    And I request HTTP endpoint with attachment as field "file" and file name "a.svg" and content type "image/svg+xml"
+    """
+    <svg/>
+    """
+
+    Then I should have response with status "OK"
+
+    And I should have response with body
+    """json
+    {"fileName":"a.svg","contentType":"image/svg+xml"}
+    """
+
+  Scenario: POST with body attachment from file with content type
+    When I request HTTP endpoint with method "POST" and URI "/file-content-type"
+
+    And I request HTTP endpoint with attachment as field "file" and content type "text/plain" from file
+    """
+    _testdata/sample.txt
+    """
+
+    Then I should have response with status "OK"
+
+    And I should have response with body
+    """json
+    {"fileName":"sample.txt","contentType":"text/plain"}
+    """
@@ -0,0 +1,17 @@
+Feature: Attachments of requests and responses
+
+  Scenario: Failed step has redacted request and response
+    Given "backend" receives "POST" request "/login"
+    And "backend" responds with status "OK" and body
+    """
+    {"user":"john","token":"abc"}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/login"
+    And I request HTTP endpoint with bearer token "t0ps3cret"
+    And I request HTTP endpoint with body
+    """
+    {"user":"john","password":"pass"}
+    """
+
+    Then I should have response with status "Created"
@@ -0,0 +1,15 @@
+Feature: Retry backoff
+
+  Scenario: Constant backoff
+    When I request HTTP endpoint with method "GET" and URI "/flaky/constant"
+    And I retry HTTP request up to 5 times with constant backoff "20ms"
+    Then I should have response with status "OK"
+
+  Scenario: Jittered exponential backoff on unavailable service
+    When I request HTTP endpoint with method "GET" and URI "/flaky/exponential"
+    And I retry HTTP request up to 1s with jittered exponential backoff "10ms" while response status is "503"
+    Then I should have response with status "OK"
+
+  Scenario: Invalid interval
+    When I request HTTP endpoint with method "GET" and URI "/flaky/invalid"
+    And I retry HTTP request up to 5 times with constant backoff "soon"
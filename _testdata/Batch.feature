@@ -0,0 +1,23 @@
+Feature: Batch responses
+
+  Scenario: JSON batch response
+    When I request HTTP endpoint with method "POST" and URI "/batch"
+    Then I should have response with status "Multi-Status"
+    And I should have response with parts
+      | id | status    | body                         |
+      | a  | OK        | {"name":"Alice","id":"$id"}  |
+      | b  | Not Found | {"error":"<ignore-diff>"}    |
+
+  Scenario: WebDAV multi-status response
+    When I request HTTP endpoint with method "PROPFIND" and URI "/dav"
+    Then I should have response with status "207"
+    And I should have response with parts
+      | id         | status    | body                                 |
+      | /dav/a.txt | 200       | <d:getcontentlength>12</d:getcontentlength> |
+      | /dav/b.txt | Forbidden |                                      |
+
+  Scenario: Unexpected status of a part
+    When I request HTTP endpoint with method "POST" and URI "/batch"
+    Then I should have response with parts
+      | id | status |
+      | b  | OK     |
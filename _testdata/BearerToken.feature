@@ -0,0 +1,26 @@
+Feature: Bearer token
+
+  Scenario: Token is added to a single request
+    Given variable $token is set to "abc"
+    When I request HTTP endpoint with method "GET" and URI "/auth"
+    And I request HTTP endpoint with bearer token "$token"
+    Then I should have response with body
+    """
+    {"auth":"Bearer abc"}
+    """
+
+  Scenario: Token is added to all requests
+    Given all requests use bearer token "$token"
+    And variable $token is set to "def"
+
+    When I request HTTP endpoint with method "GET" and URI "/auth"
+    Then I should have response with body
+    """
+    {"auth":"Bearer def"}
+    """
+
+    When I request "another" HTTP endpoint with method "GET" and URI "/auth"
+    Then I should have "another" response with body
+    """
+    {"auth":"Bearer def"}
+    """
@@ -0,0 +1,17 @@
+Feature: Binary body
+
+  Scenario: Binary body matches file
+    When I request HTTP endpoint with method "GET" and URI "/binary"
+    Then I should have response with status "OK"
+    And I should have response with binary body from file
+    """
+    _testdata/sample.bin
+    """
+
+  Scenario: Corrupted binary body
+    When I request HTTP endpoint with method "GET" and URI "/corrupted"
+    Then I should have response with status "OK"
+    And I should have response with binary body from file
+    """
+    _testdata/sample.bin
+    """
@@ -0,0 +1,10 @@
+Feature: Body containing text
+
+  Scenario: HTML page contains text
+    When I request HTTP endpoint with method "GET" and URI "/page"
+    Then I should have response with status "OK"
+    And I should have response with body containing "<h1>Welcome, "Jane"!</h1>"
+
+  Scenario: HTML page does not contain text
+    When I request HTTP endpoint with method "GET" and URI "/page"
+    Then I should have response with body containing "Goodbye"
@@ -0,0 +1,14 @@
+Feature: Body diff
+
+  Scenario: Differing paths are reported
+    When I request HTTP endpoint with method "GET" and URI "/user"
+    Then I should have response with body
+    """json
+    {
+      "id": "$id",
+      "name": "John",
+      "tags": ["user", "admin"],
+      "address": {"city": "Berlin", "zip": "<ignore-diff>"},
+      "score": 10
+    }
+    """
@@ -0,0 +1,8 @@
+Feature: Body mismatch
+
+  Scenario: Received body is attached on failure
+    When I request HTTP endpoint with method "GET" and URI "/large"
+    Then I should have response with body
+    """json
+    {"items":[]}
+    """
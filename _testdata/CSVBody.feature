@@ -0,0 +1,28 @@
+Feature: CSV body
+
+  Scenario: CSV body is compared with a subset of columns
+    When I request HTTP endpoint with method "GET" and URI "/export.csv"
+    Then I should have response with CSV body
+      | id | name | created_at    |
+      | 1  | foo  | <ignore-diff> |
+      | 2  | $bar | <ignore-diff> |
+
+    When I request HTTP endpoint with method "GET" and URI "/export.csv?name=$bar"
+    Then I should have response with CSV body
+      | name |
+      | foo  |
+      | bar  |
+
+  Scenario: Unexpected cell is reported
+    When I request HTTP endpoint with method "GET" and URI "/export.csv"
+    Then I should have response with CSV body
+      | id | name |
+      | 1  | foo  |
+      | 2  | baz  |
+
+  Scenario: Missing column is reported
+    When I request HTTP endpoint with method "GET" and URI "/export.csv"
+    Then I should have response with CSV body
+      | id | email |
+      | 1  | a@b.c |
+      | 2  | c@d.e |
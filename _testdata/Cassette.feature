@@ -0,0 +1,7 @@
+Feature: Replay of recorded requests of external service
+
+  Scenario: Recorded responses are served
+    Given "some-service" replays cassette "_testdata/Cassette.yaml"
+
+    Then I call "POST" "/users" with body "{"name":"John"}" and receive "{"id":2,"name":"John"}"
+    And I call "GET" "/users/1" with body "" and receive "{"id":1,"name":"Jane"}"
@@ -0,0 +1,27 @@
+Feature: Response to request chaining
+
+  Scenario: Value of previous response is used in URI
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    Then I should have response with status "Created"
+    And I use "$.id" from previous response as $orderID
+
+    When I request HTTP endpoint with method "GET" and URI "/orders/$orderID"
+    Then I should have response with body
+    """json
+    {"id":42,"path":"/orders/42"}
+    """
+
+  Scenario: Value of previous response is used in configured request
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    Then I should have response with status "Created"
+
+    When I request HTTP endpoint with method "GET" and URI "/orders"
+    And I use "$.id" from previous response as $orderID
+    And I request HTTP endpoint with header "X-Order: $orderID"
+    Then I should have response with body
+    """json
+    {"id":42,"path":"/orders","order":"42"}
+    """
+
+  Scenario: No previous response
+    When I use "$.id" from previous response as $orderID
@@ -0,0 +1,12 @@
+Feature: Chunked response body of external service
+
+  Scenario: Response body is streamed in chunks
+    Given "some-service" receives "GET" request "/stream"
+    And "some-service" responds with chunked body every "50ms"
+    """
+    {"n":1}
+    {"n":2}
+    {"n":3}
+    """
+
+    Then I call "/stream" and receive 3 lines with pauses of "50ms"
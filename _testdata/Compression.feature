@@ -0,0 +1,31 @@
+Feature: Compressed responses
+
+  Scenario Outline: Compressed body is decoded for assertions
+    When I request HTTP endpoint with method "GET" and URI "/data"
+    And I request HTTP endpoint with header "Accept-Encoding: <encoding>"
+    Then I should have response with status "OK"
+    And I should have response compressed with "<encoding>"
+    And I should have response with header "Content-Encoding: <encoding>"
+    And I should have response with body
+    """json
+    {"items":[1,2,3]}
+    """
+
+    Examples:
+      | encoding |
+      | gzip     |
+      | br       |
+      | deflate  |
+
+  Scenario: Transparently decompressed gzip body
+    When I request HTTP endpoint with method "GET" and URI "/data"
+    Then I should have response compressed with "gzip"
+    And I should have response with body
+    """json
+    {"items":[1,2,3]}
+    """
+
+  Scenario: Uncompressed body
+    When I request HTTP endpoint with method "GET" and URI "/data"
+    And I request HTTP endpoint with header "Accept-Encoding: identity"
+    Then I should have response compressed with "identity"
@@ -0,0 +1,10 @@
+Feature: Concurrency level
+
+  Scenario: Number of concurrent requests is defined in step
+    When I request HTTP endpoint with method "GET" and URI "/custom"
+    And I concurrently request idempotent HTTP endpoint with 25 requests
+    Then I should have response with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/default"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have response with status "OK"
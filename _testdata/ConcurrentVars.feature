@@ -0,0 +1,20 @@
+Feature: Variables captured from concurrent responses
+
+  Scenario: Same values are captured once
+    When I request HTTP endpoint with method "GET" and URI "/same"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"id":"$same"}
+    """
+
+  Scenario: Different values are merged by policy
+    When I request HTTP endpoint with method "GET" and URI "/unique"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"id":"$unique"}
+    """
+    And I store "$.id" from response body as $stored
@@ -0,0 +1,46 @@
+Feature: Conditional responses of external service
+
+  Scenario: Response depends on request header and body
+    Given "backend" receives "POST" request "/admin"
+    And "backend" request is received several times
+    And "backend" responds with status "Forbidden" when request header "X-Role" is "guest"
+    And "backend" responds with status "Payment Required" and body when request body field "$.plan" is "free"
+    """
+    {"error":"upgrade"}
+    """
+    And "backend" responds with status "OK" and body
+    """
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/admin"
+    And I request HTTP endpoint with header "X-Role: guest"
+    And I request HTTP endpoint with body
+    """
+    {"plan":"pro"}
+    """
+    Then I should have response with status "Forbidden"
+
+    When I request HTTP endpoint with method "POST" and URI "/admin"
+    And I request HTTP endpoint with header "X-Role: admin"
+    And I request HTTP endpoint with body
+    """
+    {"plan":"free"}
+    """
+    Then I should have response with status "Payment Required"
+    And I should have response with body
+    """
+    {"error":"upgrade"}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/admin"
+    And I request HTTP endpoint with header "X-Role: admin"
+    And I request HTTP endpoint with body
+    """
+    {"plan":"pro"}
+    """
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"ok":true}
+    """
@@ -0,0 +1,13 @@
+Feature: Connection reset
+
+  Scenario: GET request is retried after connection reset
+    When I request HTTP endpoint with method "GET" and URI "/get"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    attempt 2
+    """
+
+  Scenario: POST request is not retried after connection reset
+    When I request HTTP endpoint with method "POST" and URI "/post"
+    Then I should have response with status "OK"
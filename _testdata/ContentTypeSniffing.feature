@@ -0,0 +1,17 @@
+Feature: Content type sniffing
+
+  Scenario: JSON response
+    When I request HTTP endpoint with method "GET" and URI "/json"
+    Then I should have response whose body matches its Content-Type
+
+  Scenario: HTML error page served as JSON
+    When I request HTTP endpoint with method "GET" and URI "/html-as-json"
+    Then I should have response whose body matches its Content-Type
+
+  Scenario: PNG image
+    When I request HTTP endpoint with method "GET" and URI "/png"
+    Then I should have response whose body matches its Content-Type
+
+  Scenario: Empty response
+    When I request HTTP endpoint with method "GET" and URI "/empty"
+    Then I should have response whose body matches its Content-Type
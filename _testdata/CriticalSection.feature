@@ -0,0 +1,34 @@
+Feature: Critical section of external service requests
+
+  Background:
+    Given "backend" receives "POST" request "/start"
+    And "backend" responds with status "OK"
+
+    And "backend" receives "POST" request "/audit"
+    And "backend" request is async
+    And "backend" responds with status "OK"
+
+    And "backend" receives "POST" request "/commit?tx=1"
+    And "backend" responds with status "OK"
+
+    And "backend" receives no other requests between "/start" and "/commit"
+
+  Scenario: Requests of critical section are not interleaved
+    When I request HTTP endpoint with method "POST" and URI "/start"
+    Then I should have response with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/commit?tx=1"
+    Then I should have response with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/audit"
+    Then I should have response with status "OK"
+
+  Scenario: Requests of critical section are interleaved
+    When I request HTTP endpoint with method "POST" and URI "/start"
+    Then I should have response with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/audit"
+    Then I should have response with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/commit?tx=1"
+    Then I should have response with status "OK"
@@ -0,0 +1,15 @@
+Feature: Curl commands of requests
+
+  Scenario: Requests are logged as curl commands
+    Given "backend" receives "POST" request "/login?api_key=abc&lang=en"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/login?api_key=abc&lang=en"
+    And I request HTTP endpoint with bearer token "t0ps3cret"
+    And I request HTTP endpoint with header "X-Name: it's me"
+    And I request HTTP endpoint with body
+    """
+    {"user":"john","password":"pass"}
+    """
+
+    Then I should have response with status "OK"
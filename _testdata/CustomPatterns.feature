@@ -0,0 +1,15 @@
+Feature: Custom step patterns
+
+  Scenario: Overridden and aliased steps
+    Given "backend" is expected to receive "GET" request "/users/1"
+    And "backend" responds with status "OK" and body
+    """json
+    {"id":1}
+    """
+
+    When I call "GET" "/users/1"
+    Then I should have response with status "OK"
+    And the response body should be
+    """json
+    {"id":1}
+    """
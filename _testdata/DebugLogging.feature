@@ -0,0 +1,21 @@
+Feature: HTTP debug logging
+
+  Scenario: Requests are not logged by default
+    When I request HTTP endpoint with method "GET" and URI "/quiet"
+    Then I should have response with status "OK"
+
+  Scenario: Requests are logged for the rest of scenario
+    Given I enable HTTP debug logging
+
+    When I request HTTP endpoint with method "POST" and URI "/loud"
+    And I request HTTP endpoint with header "Authorization: Bearer t0ps3cret"
+    And I request HTTP endpoint with body
+    """
+    {"user":"john"}
+    """
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"status":"ok"}
+    """
@@ -0,0 +1,17 @@
+Feature: Deprecated endpoints
+
+  Scenario: Endpoint is deprecated with sunset
+    When I request HTTP endpoint with method "GET" and URI "/v1/users"
+    Then I should have response with status "OK"
+    And I should have response marked deprecated
+    And I should have response marked deprecated with sunset "2025-01-01"
+    And I should have response marked deprecated with sunset "Wed, 01 Jan 2025 00:00:00 GMT"
+
+  Scenario: Endpoint has another sunset
+    When I request HTTP endpoint with method "GET" and URI "/v1/users"
+    Then I should have response marked deprecated with sunset "2026-01-01"
+
+  Scenario: Endpoint is not deprecated
+    When I request HTTP endpoint with method "GET" and URI "/v2/users"
+    Then I should have response with status "OK"
+    And I should have response marked deprecated
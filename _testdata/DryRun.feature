@@ -0,0 +1,24 @@
+Feature: Dry run
+
+  Scenario: Valid steps pass without requests
+    Given "backend" receives "GET" request "/users/1"
+    And "backend" responds with status "OK" and body
+    """json5
+    {id: 1, name: "John"}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/users/1"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """json5
+    {
+      // Comments are allowed in JSON5.
+      "id": "$id",
+      "name": "John",
+    }
+    """
+    And I should have response with body from file
+    """
+    _testdata/sample.json
+    """
+    And "backend" should have received 1 request to "GET /users/1"
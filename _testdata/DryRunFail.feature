@@ -0,0 +1,26 @@
+Feature: Dry run failures
+
+  Scenario: Unknown status
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have response with status "Fine"
+
+  Scenario: Unknown service
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have "billing" response with status "OK"
+
+  Scenario: Invalid JSON
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have response with body
+    """
+    {"id": 1,,}
+    """
+
+  Scenario: Missing file
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have response with body from file
+    """
+    _testdata/missing.json
+    """
+
+  Scenario: Undefined response of external service
+    Given "backend" receives "GET" request "/users/1"
@@ -48,3 +48,28 @@ Feature: Dynamic data is used in steps
      "prefixed_user": "static_prefix::$user"
     }
     """
+
+  Scenario: Storing response values in variables
+    When I request HTTP endpoint with method "POST" and URI "/user"
+    Then I should have response with status "OK"
+    And I store "$.id" from response body as $uid
+    And I store "$.user" from response body as $uname
+
+    When I request HTTP endpoint with method "POST" and URI "/order/$uid/?user_id=$uid"
+    And I request HTTP endpoint with header "X-UserId: $uid"
+    And I request HTTP endpoint with cookie "user_id: $uid"
+    And I request HTTP endpoint with body
+    """json5
+    {
+      "user_id": "$uid",
+      "item_name": "Watermelon"
+    }
+    """
+
+    Then I should have response with body, that matches JSON
+    """json5
+    {
+     "user_id":"$uid",
+     "prefixed_user": "static_prefix::$uname"
+    }
+    """
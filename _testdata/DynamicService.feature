@@ -0,0 +1,32 @@
+Feature: Dynamic service registration
+
+  Background:
+    When I request HTTP endpoint with method "GET" and URI "/discovery"
+    Then I should have response with body
+    """json
+    {"inventory":"$inventoryURL"}
+    """
+
+    Given HTTP service "inventory" is available at "$inventoryURL"
+
+  Scenario: Service from discovery
+    When I request "inventory" HTTP endpoint with method "GET" and URI "/items"
+    Then I should have "inventory" response with body
+    """json
+    ["apple"]
+    """
+
+  Scenario: Default service is shadowed in scenario
+    Given HTTP service "default" is available at "$inventoryURL"
+    When I request HTTP endpoint with method "GET" and URI "/items"
+    Then I should have response with body
+    """json
+    ["apple"]
+    """
+
+  Scenario: Service is registered again
+    When I request "inventory" HTTP endpoint with method "GET" and URI "/items"
+    Then I should have "inventory" response with status "OK"
+
+  Scenario: Invalid base URL
+    Given HTTP service "warehouse" is available at "warehouse"
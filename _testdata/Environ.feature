@@ -0,0 +1,11 @@
+Feature: Variables in environment of subprocesses
+
+  Scenario: Values captured from response are passed to a command
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """json
+    {"id":"$orderId","total":"$total","items":"$items"}
+    """
+
+    And command output is "id=abc-1 total=12.5 items=[1,2]"
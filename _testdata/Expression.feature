@@ -0,0 +1,16 @@
+Feature: Response expressions
+
+  Scenario: Cross-field invariants of response
+    Given variable $customer is set to "c1"
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with status "OK"
+    And I should have response satisfying expression "body.total == sum(body.items.map(i, i.price))"
+    And I should have response satisfying expression "body.items.all(i, i.price > 0) && size(body.items) == 3"
+    And I should have response satisfying expression "status == 200 && headers['Content-Type'] == 'application/json'"
+    And I should have response satisfying expression "body.customer == "$customer""
+
+  Scenario: Other responses and empty body
+    When I request HTTP endpoint with method "DELETE" and URI "/order"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have response satisfying expression "status == 204"
+    And I should have other responses satisfying expression "status == 404 && body.error == 'not found'"
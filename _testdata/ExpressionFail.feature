@@ -0,0 +1,5 @@
+Feature: Response expressions
+
+  Scenario: Unsatisfied expression
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response satisfying expression "body.total > 100"
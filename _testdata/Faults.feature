@@ -0,0 +1,23 @@
+Feature: Faults of external service
+
+  Scenario: Connection is reset
+    Given "some-service" receives "GET" request "/reset"
+    And "some-service" resets the connection
+
+    Then I call "/reset" and receive error
+
+  Scenario: Response body is truncated
+    Given "some-service" receives "GET" request "/truncated"
+    And "some-service" responds with truncated body after 5 bytes
+    And "some-service" responds with status "OK" and body
+    """
+    hello world
+    """
+
+    Then I call "/truncated" and receive "hello" of 11 bytes before error
+
+  Scenario: Response never comes
+    Given "some-service" receives "GET" request "/hang"
+    And "some-service" never responds
+
+    Then I call "/hang" and receive error
@@ -0,0 +1,24 @@
+Feature: Generated fixtures
+
+  Scenario: Fixture is generated once per scenario
+    Given variable $id is set to 7
+    And "backend" receives "POST" request "/orders" with body from fixture "signedOrder"
+    And "backend" responds with status "OK" and body from fixture "receipt"
+
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with body from fixture "signedOrder"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """json
+    {"receipt":7}
+    """
+
+  Scenario: Fixture is generated again in another scenario
+    Given "backend" receives "POST" request "/orders" with body from fixture "signedOrder"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with body from fixture "signedOrder"
+
+    Then I should have response with status "OK"
@@ -0,0 +1,35 @@
+Feature: Form data with files
+
+  Scenario: Multipart form has files alongside plain fields
+    Given variable $owner is set to "u1"
+    When I request HTTP endpoint with method "POST" and URI "/upload"
+    And I request HTTP endpoint with multipart form data
+      | title  | Samples                    |
+      | owner  | $owner                     |
+      | notes  | @file:_testdata/sample.txt |
+      | binary | @file:_testdata/sample.bin |
+    Then I should have response with body
+    """
+    {
+      "fields":{"title":["Samples"],"owner":["u1"]},
+      "files":{
+        "notes":{"name":"sample.txt","type":"text/plain; charset=utf-8","size":5},
+        "binary":{"name":"sample.bin","type":"application/octet-stream","size":49}
+      }
+    }
+    """
+
+  Scenario: Urlencoded form has file contents
+    When I request HTTP endpoint with method "POST" and URI "/form"
+    And I request HTTP endpoint with urlencoded form data
+      | title | Samples                    |
+      | notes | @file:_testdata/sample.txt |
+    Then I should have response with body
+    """
+    {"fields":{"title":["Samples"],"notes":["a b c"]}}
+    """
+
+  Scenario: Missing file is reported
+    When I request HTTP endpoint with method "POST" and URI "/upload"
+    And I request HTTP endpoint with multipart form data
+      | avatar | @file:_testdata/missing.png |
@@ -0,0 +1,45 @@
+Feature: Generated values
+
+  Scenario: Generator expressions are replaced in URI, headers and body
+    When I request HTTP endpoint with method "POST" and URI "/echo?id=$uuid()"
+    And I request HTTP endpoint with header "X-Year: $now(2006)"
+    And I request HTTP endpoint with body
+    """json5
+    {
+      "num": "$randInt(5,5)",
+      "str": "n-$randInt(7,7)",
+      "min": "n$randInt(-9223372036854775808,-9223372036854775808)",
+      "max": "n$randInt(9223372036854775807,9223372036854775807)",
+      "wide": "$randInt(0,9223372036854775807)",
+      "full": "$randInt(-9223372036854775808,9223372036854775807)",
+      "ts": "$now(Unix)",
+      "date": "$now(Jan 2, 2006)"
+    }
+    """
+
+    Then I should have response with status "OK"
+    And I should have response with body, that matches JSON paths
+      | $.body.num | 5                       |
+      | $.body.str | "n-7"                   |
+      | $.body.min | "n-9223372036854775808" |
+      | $.body.max | "n9223372036854775807"  |
+    And I store "$.query.id" from response body as $id
+    And I store "$.body.ts" from response body as $ts
+    And I store "$.body.date" from response body as $date
+    And I store "$.year" from response body as $year
+
+  Scenario: Fake data is seeded in variables
+    Given $email is a fake email
+    And $name is a fake full name
+    And $phone is a fake phone number
+
+    When I request HTTP endpoint with method "POST" and URI "/echo"
+    And I request HTTP endpoint with body
+    """json5
+    {"email": "$email", "name": "$name", "phone": "$phone"}
+    """
+
+    Then I should have response with body, that matches JSON
+    """json5
+    {"body": {"email": "$email", "name": "$name", "phone": "$phone"}}
+    """
@@ -0,0 +1,13 @@
+Feature: Health checks
+
+  Scenario: Service is healthy
+    When I request HTTP endpoint with method "GET" and URI "/health"
+    Then I should have a healthy response
+
+  Scenario: Service is not ready
+    When I request HTTP endpoint with method "GET" and URI "/ready"
+    Then I should have a healthy response
+
+  Scenario: Service is slow
+    When I request HTTP endpoint with method "GET" and URI "/slow"
+    Then I should have a healthy response
@@ -0,0 +1,22 @@
+Feature: Idempotency grouping
+
+  Scenario: Winner and losers have the same status
+    When I request HTTP endpoint with method "POST" and URI "/dedup?winners=1"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"created":true}
+    """
+    And I should have other responses with body
+    """
+    {"created":false}
+    """
+
+  Scenario: Several winners
+    When I request HTTP endpoint with method "POST" and URI "/dedup?winners=2"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have response with body
+    """
+    {"created":true}
+    """
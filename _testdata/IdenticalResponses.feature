@@ -0,0 +1,16 @@
+Feature: Identical concurrent responses
+
+  Scenario: All responses are identical
+    When I request HTTP endpoint with method "GET" and URI "/stable"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have all responses identical including headers
+
+  Scenario: Responses have different headers
+    When I request HTTP endpoint with method "GET" and URI "/request-id"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have all responses identical including headers
+
+  Scenario: Responses have different bodies
+    When I request HTTP endpoint with method "GET" and URI "/counter"
+    And I concurrently request idempotent HTTP endpoint
+    Then I should have all responses identical including headers
@@ -0,0 +1,56 @@
+Feature: Including JSON
+
+  Scenario: Additional fields and array elements are allowed
+    Given "backend" receives "POST" request "/items" with body including JSON
+    """json5
+    {"items": [{"id": 2}]}
+    """
+    And "backend" responds with status "OK" and body
+    """json
+    {"total": 3, "items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3}]}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/items"
+    And I request HTTP endpoint with body
+    """json
+    {"name": "x", "items": [{"id": 1}, {"id": 2}]}
+    """
+
+    Then I should have response with status "OK"
+    And I should have response with body including JSON
+    """json5
+    // Elements are matched regardless of order.
+    {"items": [{"id": 3}, {"id": 2, "name": "$name"}]}
+    """
+    And variable $name equals to "b"
+
+  Scenario: Mocked request does not include JSON
+    Given "backend" receives "POST" request "/items" with body including JSON
+    """json5
+    {"items": [{"id": 5}]}
+    """
+    And "backend" responds with status "OK" and body
+    """json
+    {}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/items"
+    And I request HTTP endpoint with body
+    """json
+    {"items": [{"id": 1}]}
+    """
+
+    Then I should have response with status "OK"
+
+  Scenario: Response does not include JSON
+    Given "backend" receives "GET" request "/items"
+    And "backend" responds with status "OK" and body
+    """json
+    {"items": [{"id": 1}, {"id": 2}]}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/items"
+    Then I should have response with body including JSON
+    """json
+    {"items": [{"id": 4}]}
+    """
@@ -0,0 +1,28 @@
+Feature: JSON comparison options
+
+  Scenario: Numbers are compared exactly by default
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body
+    """
+    {"id":"ord-1","total":10.33,"tags":["a","b","c"],"createdAt":"<any-time>"}
+    """
+
+  Scenario: Numbers are compared with tolerance
+    Given JSON numbers are compared with tolerance "0.01"
+    And JSON arrays are compared ignoring order
+    And JSON value "<any-time>" matches any value
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body
+    """
+    {"id":"ord-1","total":10.33,"tags":["a","b","c"],"createdAt":"<any-time>"}
+    """
+
+  Scenario: Difference beyond tolerance is reported
+    Given JSON numbers are compared with tolerance "0.001"
+    And JSON arrays are compared ignoring order
+    And JSON value "<any-time>" matches any value
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body, that matches JSON
+    """
+    {"total":10.33,"tags":["c","a","b"],"createdAt":"<any-time>"}
+    """
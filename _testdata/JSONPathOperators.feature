@@ -0,0 +1,30 @@
+Feature: JSON path operators
+
+  Scenario: Conditions are met
+    Given variable $maxDiscount is set to 5
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body, that matches JSON paths
+      | $.total    | >= 10           |
+      | $.total    | < 100           |
+      | $.id       | ~= ^ord-\d+$    |
+      | $.items    | len == 3        |
+      | $.id       | len >= 5        |
+      | $.status   | != "cancelled"  |
+      | $.status   | == "paid"       |
+      | $.discount | <= $maxDiscount |
+      | $.items[0] | {"sku":"a"}     |
+
+  Scenario: Numeric condition is not met
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body, that matches JSON paths
+      | $.total | > 50 |
+
+  Scenario: Length condition is not met
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body, that matches JSON paths
+      | $.items | len < 3 |
+
+  Scenario: Regular expression is not matched
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body, that matches JSON paths
+      | $.id | ~= ^inv- |
@@ -0,0 +1,5 @@
+Feature: Leftover expectations
+
+  Scenario: Expectation is not met
+    Given "some-service" receives "GET" request "/never-called"
+    And "some-service" responds with status "OK"
@@ -27,6 +27,10 @@ Feature: HTTP Service
     """
     _testdata/sample.json
     """
+    And I should have response with body from file, exactly
+    """
+    _testdata/sample.json
+    """
 
   Scenario: POST with body
     When I request HTTP endpoint with method "POST" and URI "/with-body"
@@ -67,6 +71,18 @@ Feature: HTTP Service
     """
     "status":"failed"
     """
+    And I should have other responses with body, that matches JSON from file
+    """
+    _testdata/failed.json5
+    """
+    And I should have other responses with body from file, ignoring extra fields
+    """
+    _testdata/failed.json5
+    """
+    And I should have other responses with body including JSON
+    """json
+    {"status":"failed"}
+    """
 
     And I should have other responses with header "Content-Type: application/json"
 
@@ -130,6 +146,10 @@ Feature: HTTP Service
     """
     _testdata/match.json
     """
+    And I should have "some-service" response with body from file, ignoring extra fields
+    """
+    _testdata/match.json
+    """
     And I should have "some-service" response with header "Content-Type: application/json"
 
     And I should have "some-service" response with body, that contains
@@ -0,0 +1,16 @@
+# language: de
+Funktionalität: Lokalisierte Schritte
+
+  Szenario: Schritte auf Deutsch
+    Angenommen "backend" erhält "GET" Anfrage "/users/1"
+    Und "backend" antwortet mit Status "OK" und Inhalt
+    """json
+    {"id":1}
+    """
+
+    Wenn ich eine "GET" Anfrage an "/users/1" sende
+    Dann sollte ich eine Antwort mit Status "OK" erhalten
+    Und sollte ich eine Antwort mit Inhalt erhalten
+    """json
+    {"id":1}
+    """
@@ -0,0 +1,27 @@
+Feature: NDJSON bodies
+
+  Scenario: Lines are compared as JSON
+    When I request HTTP endpoint with method "GET" and URI "/export"
+    Then I should have response with NDJSON lines
+    """
+    {"id":1,"status":"$status","createdAt":"<ignore-diff>"}
+    {"id":2,"status":"$status","createdAt":"<ignore-diff>"}
+
+    {"id":3,"status":"failed","createdAt":"<ignore-diff>"}
+    """
+
+  Scenario: Unexpected line is reported
+    When I request HTTP endpoint with method "GET" and URI "/export"
+    Then I should have response with NDJSON lines
+    """
+    {"id":1,"status":"done","createdAt":"<ignore-diff>"}
+    {"id":2,"status":"done","createdAt":"<ignore-diff>"}
+    {"id":3,"status":"done","createdAt":"<ignore-diff>"}
+    """
+
+  Scenario: Unexpected number of lines is reported
+    When I request HTTP endpoint with method "GET" and URI "/export"
+    Then I should have response with NDJSON lines
+    """
+    {"id":1,"status":"done","createdAt":"<ignore-diff>"}
+    """
@@ -0,0 +1,29 @@
+Feature: Named requests
+
+  Scenario: Responses of earlier requests are asserted
+    When I request HTTP endpoint "login" with method "POST" and URI "/login"
+    Then I should have response with status "OK"
+
+    When I request HTTP endpoint "profile" with method "GET" and URI "/profile"
+    Then I should have response with status "OK"
+
+    And response of "login" should have status "OK"
+    And response of "login" should have header "X-Session: s1"
+    And response of "login" should have body
+    """json
+    {"path":"/login","user":"$user"}
+    """
+    And response of "profile" should have body
+    """json
+    {"path":"/profile","user":"$user"}
+    """
+
+  Scenario: Response of pending named request is received on assertion
+    When I request HTTP endpoint "profile" with method "GET" and URI "/profile"
+    Then response of "profile" should have status "OK"
+
+  Scenario: Named request was not sent
+    When I request HTTP endpoint "login" with method "POST" and URI "/login"
+    And I request HTTP endpoint with method "GET" and URI "/profile"
+    Then I should have response with status "OK"
+    And response of "login" should have status "OK"
@@ -0,0 +1,26 @@
+Feature: Multiple local clients
+
+  Scenario: Clients with same variable names
+    When I request HTTP endpoint with method "GET" and URI "/order"
+    Then I should have response with body
+    """json
+    {"id":"$id"}
+    """
+
+    When billing: I request HTTP endpoint with method "GET" and URI "/invoice"
+    Then billing: I should have response with body
+    """json
+    {"id":"$id"}
+    """
+
+    When billing: I request HTTP endpoint with method "GET" and URI "/echo?id=$id"
+    Then billing: I should have response with body
+    """
+    inv-1
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/echo?id=$id"
+    Then I should have response with body
+    """
+    ord-1
+    """
@@ -0,0 +1,32 @@
+Feature: Absent body content
+
+  Scenario: Response does not leak secrets
+    When I request HTTP endpoint with method "GET" and URI "/user"
+    Then I should have response with status "OK"
+    And I should have response with body not containing
+    """
+    s3cr3t
+    """
+    And I should have response with body not containing JSON paths
+      | $.password |         |
+      | $.items[5] |         |
+      | $..token   |         |
+      | $.role     | "admin" |
+      | $.groups   | "root"  |
+
+  Scenario: Response leaks password hash
+    When I request HTTP endpoint with method "GET" and URI "/user?debug=1"
+    Then I should have response with body not containing JSON paths
+      | $.passwordHash |
+
+  Scenario: Response leaks role
+    When I request HTTP endpoint with method "GET" and URI "/user?debug=1"
+    Then I should have response with body not containing JSON paths
+      | $.groups | "root" |
+
+  Scenario: Response leaks text
+    When I request HTTP endpoint with method "GET" and URI "/user?debug=1"
+    Then I should have response with body not containing
+    """
+    $2a$10$
+    """
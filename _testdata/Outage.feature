@@ -0,0 +1,34 @@
+Feature: Outage of external service
+
+  Scenario: Application retries until service recovers
+    Given "backend" is unavailable for "200ms" then recovers
+    And "backend" receives "GET" request "/status"
+    And "backend" responds with status "OK" and body
+    """
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/status"
+    And I retry HTTP request up to 5s with constant backoff "50ms"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"ok":true}
+    """
+    And "backend" receives request within "100ms" after recovery
+    And "backend" receives at most 10 requests during outage
+
+  Scenario: Application hammers unavailable service
+    Given "backend" is unavailable for "200ms" then recovers
+    And "backend" receives "GET" request "/status"
+    And "backend" responds with status "OK" and body
+    """
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/status"
+    And I retry HTTP request up to 5s with constant backoff "10ms"
+
+    Then I should have response with status "OK"
+    And "backend" receives at most 3 requests during outage
@@ -0,0 +1,33 @@
+Feature: Scenario outline examples
+
+  Scenario Outline: Expected body file is selected by example
+    Given variable $name is set to "Jane"
+    And variable $case is set to "<case>"
+
+    When I request HTTP endpoint with method "GET" and URI "/<case>?name=Jane"
+
+    Then I should have response with status "OK"
+
+    # Outline placeholder is substituted by godog.
+    And I should have response with body from file
+    """
+    _testdata/outline/<case>.json
+    """
+
+    # Variable is replaced at assertion time.
+    And I should have response with body, that matches JSON from file
+    """
+    _testdata/outline/$case.json
+    """
+
+    Examples:
+      | case  |
+      | hello |
+      | bye   |
+
+  Scenario: Missing expected body file
+    When I request HTTP endpoint with method "GET" and URI "/hello?name=Jane"
+    Then I should have response with body from file
+    """
+    _testdata/outline/missing.json
+    """
@@ -0,0 +1,11 @@
+Feature: Passthrough of unmatched requests of external service
+
+  Scenario: Unmatched requests are forwarded to upstream
+    Given "some-service" receives "GET" request "/mocked"
+    And "some-service" responds with status "OK" and body
+    """
+    {"mocked":true}
+    """
+
+    Then I call "POST" "/real?x=1" with body "hello" and receive "upstream POST /real?x=1: hello"
+    And I call "GET" "/mocked" with body "" and receive "{"mocked":true}"
@@ -0,0 +1,36 @@
+Feature: Path parameters of external service requests
+
+  Scenario: Named path parameter is available in response body
+    Given "backend" receives "GET" request "/users/{id}/orders?status=open"
+    And "backend" responds with status "OK" and body
+    """
+    {"userId":"$id","status":"open"}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/users/42/orders?status=open"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"userId":"42","status":"open"}
+    """
+
+  Scenario: Positional path parameters are available in response body
+    Given "backend" receives "GET" request "/users/*/orders/*"
+    And "backend" responds with status "OK" and body
+    """
+    {"userId":"$1","orderId":"$2"}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/users/42/orders/abc%20def"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"userId":"42","orderId":"abc def"}
+    """
+
+  Scenario: Request with a different path is not matched
+    Given "backend" receives "GET" request "/users/{id}/orders"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/users/42/invoices"
+    Then I should have response with status "OK"
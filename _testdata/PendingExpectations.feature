@@ -0,0 +1,24 @@
+Feature: Pending expectations
+
+  Scenario: Pending expectations are printed
+    Given "some-service" receives "GET" request "/first"
+    And "some-service" responds with status "OK"
+
+    And "some-service" receives "POST" request "/second" with body
+    """json
+    {"foo":"bar"}
+    """
+    And "some-service" request is received 2 times
+    And "some-service" responds with status "Created"
+
+    And "some-service" receives "GET" request "/async"
+    And "some-service" request is async
+    And "some-service" responds with status "OK"
+
+    When I call "GET" "/first"
+    And I call "POST" "/second"
+    And I print pending expectations for "some-service"
+
+    Then I call "POST" "/second"
+    And I call "GET" "/async"
+    And I print pending expectations for "some-service"
@@ -0,0 +1,11 @@
+Feature: Polling
+
+  Scenario: Job is done eventually
+    When I poll HTTP endpoint with method "GET" and URI "/job/1" every "10ms" for up to "1s" until response has status "OK"
+    Then I should have response with body
+    """json
+    {"status":"done"}
+    """
+
+  Scenario: Job is not done in time
+    When I poll HTTP endpoint with method "GET" and URI "/job/2" every "10ms" for up to "50ms" until response has status "OK"
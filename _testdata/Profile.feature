@@ -0,0 +1,18 @@
+Feature: Response profiles
+
+  Scenario: Error envelope
+    Given variable $requestId is set to "abc"
+    When I request HTTP endpoint with method "GET" and URI "/error"
+    And I request HTTP endpoint with header "X-Request-Id: abc"
+    Then I should have a "standard-error" response
+
+  Scenario: Profiles defined in Go and YAML
+    When I request HTTP endpoint with method "GET" and URI "/list"
+    Then I should have a "paginated-list" response
+    And I should have an "ok-json" response
+
+  Scenario: Response does not match schema
+    Given variable $requestId is set to "abc"
+    When I request HTTP endpoint with method "GET" and URI "/bad-error"
+    And I request HTTP endpoint with header "X-Request-Id: abc"
+    Then I should have a "standard-error" response
@@ -0,0 +1,32 @@
+Feature: Protobuf bodies
+
+  Scenario: Protobuf body is sent and received
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with protobuf "shop.Order" body
+    """
+    {"id":"ord-1","total":21,"tags":["a","b"]}
+    """
+    Then I should have response with status "OK"
+    And I should have response with header "Content-Type: application/x-protobuf"
+    And I should have response with protobuf "shop.Order" body
+    """
+    {"id":"ord-1","total":"42","tags":"<ignore-diff>"}
+    """
+
+  Scenario: Unexpected protobuf body
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with protobuf "shop.Order" body
+    """
+    {"id":"ord-2","total":1}
+    """
+    Then I should have response with protobuf "shop.Order" body
+    """
+    {"id":"ord-2","total":"1"}
+    """
+
+  Scenario: Unknown message type
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with protobuf "shop.Invoice" body
+    """
+    {"id":"inv-1"}
+    """
@@ -0,0 +1,8 @@
+Feature: Body providers
+
+  Scenario: Response includes values supplied by providers
+    Given variable $id is set to 2
+    When I request HTTP endpoint with method "GET" and URI "/orders"
+    Then I should have response with status "OK"
+    And I should have response with body matching provider "ordersFromDB"
+    And I should have response with body matching provider "lastOrder"
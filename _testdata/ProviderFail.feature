@@ -0,0 +1,9 @@
+Feature: Body providers
+
+  Scenario: Response does not include provided value
+    When I request HTTP endpoint with method "GET" and URI "/orders"
+    Then I should have response with body matching provider "missingOrder"
+
+  Scenario: Unknown provider
+    When I request HTTP endpoint with method "GET" and URI "/orders"
+    Then I should have response with body matching provider "unknown"
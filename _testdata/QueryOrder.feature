@@ -0,0 +1,22 @@
+Feature: Order of query parameters of external service requests
+
+  Scenario: Query parameters are matched in any order
+    Given "backend" receives "GET" request "/things?a=1&b=2&tag=x&tag=y"
+    And "backend" responds with status "OK" and body
+    """
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/things?tag=y&b=2&tag=x&a=1"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"ok":true}
+    """
+
+  Scenario: Query parameters with different values are not matched
+    Given "backend" receives "GET" request "/things?a=1&b=2"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/things?b=2&a=3"
+    Then I should have response with status "OK"
@@ -0,0 +1,18 @@
+Feature: Concurrency ramp
+
+  Scenario: Rate limit appears above threshold
+    When I request HTTP endpoint with method "GET" and URI "/limited"
+    And I concurrently request idempotent HTTP endpoint with ramp from 5 to 50 requests in 4 steps
+    Then I should have ramp responses with status "OK" up to 20 concurrent requests
+    And I should have first failed ramp response with status "Too Many Requests" at 35 or more concurrent requests
+
+  Scenario: Rate limit appears earlier than expected
+    When I request HTTP endpoint with method "GET" and URI "/limited"
+    And I concurrently request idempotent HTTP endpoint with ramp from 5 to 50 requests in 4 steps
+    Then I should have ramp responses with status "OK" up to 35 concurrent requests
+
+  Scenario: Last wave is checked by regular expectations
+    When I request HTTP endpoint with method "GET" and URI "/ok"
+    And I concurrently request idempotent HTTP endpoint with ramp from 2 to 4 requests in 3 steps
+    Then I should have response with status "OK"
+    And I should have ramp responses with status "OK" up to 4 concurrent requests
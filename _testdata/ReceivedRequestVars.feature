@@ -0,0 +1,34 @@
+Feature: Variables from received request of external service
+
+  Scenario: Field of received request body is stored in variable
+    Given "backend" receives "POST" request "/charges" with body, that matches JSON
+    """
+    {"amount":100}
+    """
+    And "backend" responds with status "Created"
+    And "backend" receives "GET" request "/charges/ch_42"
+    And "backend" responds with status "OK" and body
+    """
+    {"status":"paid"}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/charges"
+    And I request HTTP endpoint with body
+    """
+    {"chargeId":"ch_42","amount":100}
+    """
+    Then I should have response with status "Created"
+
+    And "backend" received request body field "$.chargeId" stored as $chargeID
+    And variable $chargeID equals to "ch_42"
+
+    When I request HTTP endpoint with method "GET" and URI "/charges/$chargeID"
+    Then I should have response with status "OK"
+
+  Scenario: No received request with body
+    Given "backend" receives "GET" request "/charges"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/charges"
+    Then I should have response with status "OK"
+    And "backend" received request body field "$.chargeId" stored as $chargeID
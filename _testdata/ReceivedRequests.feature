@@ -0,0 +1,28 @@
+Feature: Number of received requests of external service
+
+  Scenario: Received requests are counted
+    Given "backend" receives "GET" request "/things?page=1"
+    And "backend" responds with status "OK"
+    And "backend" responds with status "OK"
+    And "backend" receives "DELETE" request "/things/1"
+    And "backend" responds with status "No Content"
+
+    When I request HTTP endpoint with method "GET" and URI "/things?page=1"
+    Then I should have response with status "OK"
+    When I request HTTP endpoint with method "GET" and URI "/things?page=1"
+    Then I should have response with status "OK"
+    When I request HTTP endpoint with method "DELETE" and URI "/things/1"
+    Then I should have response with status "No Content"
+
+    And "backend" should have received 2 requests to "GET /things"
+    And "backend" should have received 2 requests to "/things?page=1"
+    And "backend" should have received 1 request to "DELETE /things/{id}"
+    And "backend" should have received 0 requests to "POST /things"
+
+  Scenario: Unexpected number of received requests
+    Given "backend" receives "GET" request "/things"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/things"
+    Then I should have response with status "OK"
+    And "backend" should have received 3 requests to "GET /things"
@@ -0,0 +1,31 @@
+Feature: Request urlencoded form
+
+  Scenario: Form parameters are matched in any order
+    Given variable $name is set to "John"
+    And "backend" receives "POST" request "/submit" with urlencoded form data
+      | name  | $name         |
+      | tag   | a             |
+      | tag   | b             |
+      | token | <ignore-diff> |
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/submit"
+    And I request HTTP endpoint with header "Content-Type: application/x-www-form-urlencoded"
+    And I request HTTP endpoint with body
+    """
+    token=abc123&tag=a&name=John&tag=b
+    """
+    Then I should have response with status "OK"
+
+  Scenario: Mocked request does not match form
+    Given "backend" receives "POST" request "/submit" with urlencoded form data
+      | name | Jane |
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/submit"
+    And I request HTTP endpoint with header "Content-Type: application/x-www-form-urlencoded"
+    And I request HTTP endpoint with body
+    """
+    name=John
+    """
+    Then I should have response with status "OK"
@@ -0,0 +1,13 @@
+Feature: Request handler of external service
+
+  Scenario: Response is served by registered handler
+    Given "some-service" receives "POST" request "/quote/{id}"
+    And "some-service" request is received 2 times
+    And "some-service" responds with registered handler
+
+    Then I call "POST" "/quote/1" with body "10" and receive "quote /quote/1: 20"
+    And I call "POST" "/quote/2" with body "7" and receive "quote /quote/2: 14"
+
+  Scenario: Handler is not registered
+    Given "some-service" receives "GET" request "/unknown"
+    And "some-service" responds with registered handler
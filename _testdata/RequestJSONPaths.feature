@@ -0,0 +1,49 @@
+Feature: Request body JSON paths
+
+  Scenario: Only some values of request body are expected
+    Given variable $token is set to "t1"
+    And "backend" receives "POST" request "/orders" with body, that matches JSON paths
+      | $.customer.id | 123             |
+      | $.items[0]    | {"sku":"a"}     |
+      | $.createdAt   | "<ignore-diff>" |
+      | $.token       | "$token"        |
+    And "backend" responds with status "OK" and body
+    """json
+    {"token": "$token"}
+    """
+
+    And "backend" receives "POST" request "/audit" with body, that matches JSON paths
+      | $.action | "created" |
+    And "backend" request is async
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with body
+    """json
+    {"customer": {"id": 123, "name": "John"}, "items": [{"sku": "a", "qty": 2}], "createdAt": "2024-01-01", "token": "t1"}
+    """
+    Then I should have response with status "OK"
+    And I should have response with body
+    """json
+    {"token": "t1"}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/audit"
+    And I request HTTP endpoint with body
+    """json
+    {"action": "created", "details": {"by": "John"}}
+    """
+    Then I should have response with status "OK"
+
+  Scenario: Mocked request does not match JSON paths
+    Given "backend" receives "POST" request "/orders" with body, that matches JSON paths
+      | $.customer.id | 456 |
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with body
+    """json
+    {"customer": {"id": 123}}
+    """
+
+    Then I should have response with status "OK"
@@ -0,0 +1,29 @@
+Feature: External service request body that matches JSON
+
+  Scenario: Request body with extra fields matches JSON
+    Given "backend" receives "POST" request "/orders" with body, that matches JSON
+    """
+    {"id":"<ignore-diff>","items":[{"sku":"a"},{"sku":"b"}]}
+    """
+    And "backend" responds with status "Created"
+
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with body
+    """
+    {"id":"0f8fad5b-d9cb-469f-a165-70867728950e","createdAt":"2024-05-01T10:00:00Z","items":[{"sku":"a","qty":1},{"sku":"b","qty":2}]}
+    """
+    Then I should have response with status "Created"
+
+  Scenario: Request body with different order of array elements does not match JSON
+    Given "backend" receives "POST" request "/orders" with body, that matches JSON
+    """
+    {"items":[{"sku":"a"},{"sku":"b"}]}
+    """
+    And "backend" responds with status "Created"
+
+    When I request HTTP endpoint with method "POST" and URI "/orders"
+    And I request HTTP endpoint with body
+    """
+    {"items":[{"sku":"b"},{"sku":"a"}]}
+    """
+    Then I should have response with status "Created"
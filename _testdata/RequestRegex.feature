@@ -0,0 +1,30 @@
+Feature: Regular expression of external service request URI
+
+  Scenario: Request URI is matched with regular expression
+    Given "backend" receives "GET" request matching "^/reports/(?P<month>\d{4}-\d{2})(\?.*)?$"
+    And "backend" request is received 2 times
+    And "backend" responds with status "OK" and body
+    """
+    {"month":"$month"}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/reports/2024-05?nonce=abc"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"month":"2024-05"}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/reports/2023-12"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"month":"2023-12"}
+    """
+
+  Scenario: Request URI does not match regular expression
+    Given "backend" receives "GET" request matching "^/reports/\d{4}-\d{2}$"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/reports/latest"
+    Then I should have response with status "OK"
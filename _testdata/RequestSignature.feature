@@ -0,0 +1,30 @@
+Feature: Signature of requests to external service
+
+  Scenario: Request is signed with expected key
+    Given "webhooks" receives "POST" request "/hook" with body
+    """json
+    {"event":"created"}
+    """
+    And "webhooks" request is signed with key "webhook-secret"
+    And "webhooks" responds with status "No Content"
+
+    When I request HTTP endpoint with method "POST" and URI "/hook"
+    And I request HTTP endpoint with body
+    """json
+    {"event":"created"}
+    """
+    And I request HTTP endpoint signed with HMAC-SHA256 using key "topsecret" in header "X-Hub-Signature"
+    Then I should have response with status "No Content"
+
+  Scenario: Request is signed with another key
+    Given "webhooks" receives "POST" request "/hook"
+    And "webhooks" request is signed with key "webhook-secret"
+    And "webhooks" responds with status "No Content"
+
+    When I request HTTP endpoint with method "POST" and URI "/hook"
+    And I request HTTP endpoint with body
+    """json
+    {"event":"created"}
+    """
+    And I request HTTP endpoint signed with HMAC-SHA256 using key "wrong" in header "X-Hub-Signature"
+    Then I should have response with status "No Content"
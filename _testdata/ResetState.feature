@@ -0,0 +1,26 @@
+Feature: Reset client state
+
+  Scenario: Scenario acts as two different callers
+    Given all requests use bearer token "t1"
+    When I request HTTP endpoint with method "GET" and URI "/whoami"
+    Then I should have response with body
+    """json
+    {"auth":"Bearer t1","caller":"admin-console","cookie":"session=abc"}
+    """
+
+    When I reset HTTP client state
+    And I request HTTP endpoint with method "GET" and URI "/whoami"
+    Then I should have response with body
+    """json
+    {"auth":"","caller":"","cookie":""}
+    """
+
+  Scenario: Pending request options are discarded
+    When I request HTTP endpoint with method "GET" and URI "/whoami"
+    And I request HTTP endpoint with header "X-Caller: partner"
+    And I reset HTTP client state
+    And I request HTTP endpoint with method "GET" and URI "/whoami"
+    Then I should have response with body
+    """json
+    {"auth":"","caller":"","cookie":""}
+    """
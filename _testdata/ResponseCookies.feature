@@ -0,0 +1,23 @@
+Feature: Response cookies
+
+  Scenario: Session cookie is secure
+    When I request HTTP endpoint with method "POST" and URI "/login"
+    Then I should have response with status "OK"
+    And I should have response with cookie "session"
+    And I should have response with cookie "session" having "HttpOnly", "Secure" and "SameSite=Strict"
+    And I should have response with cookie "session: $sid" having "Path=/" and "Max-Age=3600"
+
+    When I request HTTP endpoint with method "GET" and URI "/me"
+    And I request HTTP endpoint with cookie "session: $sid"
+    Then I should have response with body
+    """
+    {"session":"$sid"}
+    """
+
+  Scenario: Theme cookie is not HttpOnly
+    When I request HTTP endpoint with method "POST" and URI "/login"
+    Then I should have response with cookie "theme: dark" having "HttpOnly"
+
+  Scenario: Token cookie is missing
+    When I request HTTP endpoint with method "POST" and URI "/login"
+    Then I should have response with cookie "token"
@@ -0,0 +1,23 @@
+Feature: Delayed responses of external service
+
+  Scenario: Slow service exceeds timeout of application
+    Given "backend" receives "GET" request "/slow"
+    And "backend" responds with delay "1s"
+    And "backend" responds with status "OK"
+
+    When I request "limited" HTTP endpoint with method "GET" and URI "/slow"
+    Then I should have "limited" response with status "OK"
+
+  Scenario: Slow service responds within timeout
+    Given "backend" receives "GET" request "/slow"
+    And "backend" responds with delay "200ms"
+    And "backend" responds with status "OK" and body
+    """
+    {"slow":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/slow"
+    Then I should have response with body
+    """
+    {"slow":true}
+    """
@@ -0,0 +1,13 @@
+Feature: Delayed response does not block other requests
+
+  Scenario: Fast request is served during delay of slow request
+    Given "backend" receives "GET" request "/slow"
+    And "backend" request is async
+    And "backend" responds with delay "1s"
+    And "backend" responds with status "OK"
+
+    And "backend" receives "GET" request "/fast"
+    And "backend" request is async
+    And "backend" responds with status "OK"
+
+    Then fast request is served during slow request
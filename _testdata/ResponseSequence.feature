@@ -0,0 +1,29 @@
+Feature: Sequence of responses of external service
+
+  Scenario: Repeated request is served with a sequence of responses
+    Given "backend" receives "GET" request "/flaky"
+    And "backend" request includes header "X-Attempt: yes"
+    And "backend" responds with status "Service Unavailable"
+    And "backend" response includes header "Retry-After: 0"
+    And "backend" responds with status "Bad Gateway"
+    And "backend" responds with status "OK" and body
+    """
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/flaky"
+    And I request HTTP endpoint with header "X-Attempt: yes"
+    And I retry HTTP request up to 5 times while response status is "502, Service Unavailable"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"ok":true}
+    """
+
+  Scenario: Sequence is not finished
+    Given "backend" receives "GET" request "/flaky"
+    And "backend" responds with status "Service Unavailable"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "GET" and URI "/flaky"
+    Then I should have response with status "Service Unavailable"
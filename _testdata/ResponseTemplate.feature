@@ -0,0 +1,44 @@
+Feature: Response of external service with values of request
+
+  Scenario: Response body references request
+    Given "backend" receives "POST" request "/users/{id}/echo?id=abc" with body, that matches JSON
+    """
+    {"name":"<ignore-diff>"}
+    """
+    And "backend" request is received 2 times
+    And "backend" responds with status "OK" and body
+    """
+    {
+      "echoId":"{{request.query.id}}",
+      "userId":"{{request.params.id}}",
+      "method":"{{request.method}}",
+      "path":"{{request.path}}",
+      "trace":"{{request.header.X-Trace}}",
+      "name":"{{request.body.$.name}}",
+      "qty":"{{request.body.$.qty}}"
+    }
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/users/1/echo?id=abc"
+    And I request HTTP endpoint with header "X-Trace: t-1"
+    And I request HTTP endpoint with body
+    """
+    {"name":"Alice","qty":3}
+    """
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"echoId":"abc","userId":"1","method":"POST","path":"/users/1/echo","trace":"t-1","name":"Alice","qty":3}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/users/2/echo?id=abc"
+    And I request HTTP endpoint with header "X-Trace: t-2"
+    And I request HTTP endpoint with body
+    """
+    {"name":"Bob","qty":5}
+    """
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"echoId":"abc","userId":"2","method":"POST","path":"/users/2/echo","trace":"t-2","name":"Bob","qty":5}
+    """
@@ -0,0 +1,11 @@
+Feature: Retry on status
+
+  Scenario: Unavailable service is retried
+    When I request HTTP endpoint with method "GET" and URI "/flaky"
+    And I retry HTTP request up to 5 times while response status is "502, Service Unavailable"
+    Then I should have response with status "OK"
+
+  Scenario: Other failures are not retried
+    When I request HTTP endpoint with method "GET" and URI "/broken"
+    And I retry HTTP request up to 5 times while response status is "503"
+    Then I should have response with status "OK"
@@ -0,0 +1,15 @@
+Feature: Round trip
+
+  Scenario: Application proxies requests to external service
+    When the app proxies "GET" "/template/hello" to "template-service" responding "OK" with body
+    """json5
+    // JSON5 is allowed.
+    {"key":"value"}
+    """
+
+    And the "gateway" app proxies "DELETE" "/template/hello" to "template-service" responding "No Content"
+
+    And the app proxies "POST" "/template/hello" to "template-service" responding "Bad Request" with body
+    """
+    bad request
+    """
@@ -0,0 +1,7 @@
+Feature: Round trip with cache
+
+  Scenario: Application revalidates cached response of external service
+    When the app revalidates cached "GET" "/template/hello" from "template-service" with body
+    """json
+    {"key":"value"}
+    """
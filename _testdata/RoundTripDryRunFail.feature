@@ -0,0 +1,13 @@
+Feature: Round trip dry run failures
+
+  Scenario: Unknown status
+    When the app proxies "GET" "/template/hello" to "template-service" responding "Fine"
+
+  Scenario: Unknown external service
+    When the app proxies "GET" "/template/hello" to "billing" responding "OK"
+
+  Scenario: Invalid JSON
+    When the app revalidates cached "GET" "/template/hello" from "template-service" with body
+    """json
+    {"key":,}
+    """
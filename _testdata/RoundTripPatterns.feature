@@ -0,0 +1,5 @@
+Feature: Round trip with custom patterns
+
+  Scenario: Application forwards requests to external service
+    When the app forwards "GET" "/template/hello" to "template-service" with "OK"
+    And the app proxy of "DELETE" "/template/hello" to "template-service" responds "No Content"
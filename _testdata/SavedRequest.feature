@@ -0,0 +1,43 @@
+Feature: Saved requests
+
+  Scenario: Requests of Postman collection are sent with variables
+    Given "backend" receives "POST" request "/users" with body
+    """
+    {"name":"John"}
+    """
+    And "backend" request includes header "Content-Type: application/json"
+    And "backend" responds with status "Created" and body
+    """
+    {"id":7,"token":"secret"}
+    """
+
+    When I send saved request "Create user"
+
+    Then I should have response with status "Created"
+    And I should have response with body
+    """
+    {"id":"$id","token":"$token"}
+    """
+
+    Given "backend" receives "GET" request "/users/7?full=1"
+    And "backend" request includes header "X-Token: secret"
+    And "backend" responds with status "OK" and body
+    """
+    {"id":7,"name":"John"}
+    """
+
+    When I send saved request "Get user"
+
+    Then I should have response with status "OK"
+
+    Given "backend" receives "POST" request "/login" with urlencoded form data
+      | user     | john   |
+      | password | secret |
+    And "backend" responds with status "No Content"
+
+    When I send saved request "Login"
+
+    Then I should have response with status "No Content"
+
+  Scenario: Unknown saved request
+    When I send saved request "Delete user"
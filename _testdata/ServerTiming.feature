@@ -0,0 +1,18 @@
+Feature: Server timing
+
+  Scenario: Durations are under limits
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have response with status "OK"
+    And I should have server timing "cache"
+    And I should have server timing "db" under "50ms"
+    And I should have server timing "total" under "100ms"
+    And I should have response with header "X-Response-Time" under "20ms"
+    And I should have response with header "X-Runtime-Ms" under "20ms"
+
+  Scenario: Duration exceeds limit
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have server timing "total" under "50ms"
+
+  Scenario: Missing metric
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have server timing "queue"
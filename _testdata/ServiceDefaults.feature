@@ -0,0 +1,33 @@
+Feature: Service defaults
+
+  Scenario: Configured service follows redirects by default
+    When I request "configured" HTTP endpoint with method "GET" and URI "/redirect"
+    Then I should have "configured" response with status "OK"
+    And I should have "configured" response with body
+    """
+    1
+    """
+
+  Scenario: Default service does not follow redirects
+    When I request HTTP endpoint with method "GET" and URI "/redirect"
+    Then I should have response with status "Found"
+
+  Scenario: Configured service retries failed and timed out requests by default
+    When I request "configured" HTTP endpoint with method "GET" and URI "/flaky?id=a"
+    Then I should have "configured" response with status "OK"
+    And I should have "configured" response with body
+    """
+    3
+    """
+
+    When I request "configured" HTTP endpoint with method "GET" and URI "/slow?id=a"
+    Then I should have "configured" response with status "OK"
+    And I should have "configured" response with body
+    """
+    2
+    """
+
+  Scenario: Step overrides default retry limit
+    When I request "configured" HTTP endpoint with method "GET" and URI "/flaky?id=b"
+    And I retry "configured" HTTP request up to 1 time
+    Then I should have "configured" response with status "Service Unavailable"
@@ -0,0 +1,21 @@
+Feature: Request signature
+
+  Scenario: Request is signed with HMAC-SHA256 of final body
+    Given variable $secret is set to "top-secret"
+    When I request HTTP endpoint with method "POST" and URI "/webhook"
+    And I request HTTP endpoint signed with HMAC-SHA256 using key "$secret" in header "X-Signature"
+    And I request HTTP endpoint with body
+    """json5
+    // Body is signed after it is finalized.
+    {"event": "created"}
+    """
+    Then I should have response with status "OK"
+    And I should have response with body
+    """json
+    {"valid": true, "body": {"event": "created"}}
+    """
+
+  Scenario: Request without body is signed
+    When I request HTTP endpoint with method "GET" and URI "/webhook"
+    And I request HTTP endpoint signed with HMAC-SHA256 using key "top-secret" in header "X-Signature"
+    Then I should have response with status "OK"
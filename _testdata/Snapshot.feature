@@ -0,0 +1,10 @@
+Feature: Response snapshots
+
+  Scenario: Profile matches snapshot
+    When I request HTTP endpoint with method "GET" and URI "/profile"
+    Then I should have response with status "OK"
+    And I should have response matching snapshot "profile"
+
+  Scenario: Page matches snapshot
+    When I request HTTP endpoint with method "GET" and URI "/page"
+    Then I should have response matching snapshot "page"
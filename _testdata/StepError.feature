@@ -0,0 +1,11 @@
+Feature: Step errors
+
+  Scenario: Unknown service
+    When I request "missing-service" HTTP endpoint with method "GET" and URI "/"
+
+  Scenario: Body does not contain expected value
+    When I request "another" HTTP endpoint with method "GET" and URI "/"
+    Then I should have "another" response with body, that contains
+    """
+    bar
+    """
@@ -0,0 +1,19 @@
+Feature: Step prefix
+
+  Scenario: Prefixed steps do not collide with steps of other packages
+    Given api "backend" receives "GET" request "/users/1"
+    And api "backend" responds with status "OK" and body
+    """json
+    {"id":1}
+    """
+
+    When api I request HTTP endpoint with method "GET" and URI "/users/1"
+    Then api I should have response with status "OK"
+    And api I should have response with body
+    """json
+    {"id":1}
+    """
+
+    When api the app proxies "GET" "/users/2" to "backend" responding "OK"
+
+    And I should have response with status "legacy"
@@ -0,0 +1,28 @@
+Feature: Streaming request body from file
+
+  Scenario: Large file is streamed as is
+    Given variable $id is set to 123
+    When I request HTTP endpoint with method "POST" and URI "/echo"
+    And I request HTTP endpoint with body from file
+    """
+    _testdata/stream.txt
+    """
+    Then I should have response with status "OK"
+    # Variables are not replaced in streamed body.
+    And I should have response with binary body from file
+    """
+    _testdata/stream.txt
+    """
+
+  Scenario: Small file is read into memory
+    Given variable $id is set to 123
+    When I request HTTP endpoint with method "POST" and URI "/echo"
+    And I request HTTP endpoint with body from file
+    """
+    _testdata/sample.txt
+    """
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    a b c
+    """
@@ -0,0 +1,27 @@
+Feature: Streamed request body
+
+  Scenario: Generated body is streamed to external service
+    Given "backend" receives request body as stream
+    And "backend" receives "POST" request "/upload"
+    And "backend" responds with status "OK" and body
+    """
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/upload"
+    And I request HTTP endpoint with body streamed from "10MB"
+
+    Then I should have response with status "OK"
+    And "backend" receives request body of 10485760 bytes
+    And "backend" receives 1048576 bytes of request body within "5s"
+
+  Scenario: Streamed body has unexpected size
+    Given "backend" receives request body as stream
+    And "backend" receives "POST" request "/upload"
+    And "backend" responds with status "OK"
+
+    When I request HTTP endpoint with method "POST" and URI "/upload"
+    And I request HTTP endpoint with body streamed from "10MB"
+
+    Then I should have response with status "OK"
+    And "backend" receives request body of 100 bytes
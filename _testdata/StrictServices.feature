@@ -0,0 +1,14 @@
+Feature: Strict service names
+
+  Scenario: Registered services
+    When I request HTTP endpoint with method "GET" and URI "/"
+    Then I should have response with status "OK"
+
+    When I request "users" HTTP endpoint with method "GET" and URI "/"
+    Then I should have "users" response with status "OK"
+
+  Scenario: Malformed service
+    When I request "users HTTP endpoint with method "GET" and URI "/"
+
+  Scenario: Unknown service
+    When I request "user" HTTP endpoint with method "GET" and URI "/"
@@ -0,0 +1,16 @@
+Feature: Suite
+
+  Scenario: Variable from database is used in HTTP request
+    Given there is a row with id "123" in table "users"
+    And "users-cache" receives "GET" request "/users/123"
+    And "users-cache" responds with status "OK" and body
+    """
+    {"id":123}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/users/$id"
+    Then I should have response with body
+    """
+    {"path":"/users/123"}
+    """
+    And variable $id equals to 123
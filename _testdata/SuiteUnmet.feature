@@ -0,0 +1,8 @@
+Feature: Suite with unmet expectations
+
+  Scenario: Expected request is not received
+    Given "users-cache" receives "GET" request "/users/123"
+    And "users-cache" responds with status "OK" and body
+    """
+    {"id":123}
+    """
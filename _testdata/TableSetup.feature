@@ -27,3 +27,20 @@ Feature: Table Setup
     And I should have "some-service" response with headers
       | Content-Type | application/json |
       | X-Baz        | abc              |
+
+  Scenario: JSON request body can be configured with table of paths and values
+    Given variable $id is set to 123
+
+    When I request "some-service" HTTP endpoint with method "POST" and URI "/json-table"
+    And I request "some-service" HTTP endpoint with JSON body from table
+      | user.name       | John Doe |
+      | user.zip:string | 01234    |
+      | user.active     | true     |
+      | user.tags:json  | ["a"]    |
+      | items[0].id     | $id      |
+      | items[1].id     | 2        |
+      | items[1].note   | "quoted" |
+      | $.comment:null  |          |
+      | links.urn:self  | /users/1 |
+
+    Then I should have "some-service" response with status "OK"
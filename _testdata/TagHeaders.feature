@@ -0,0 +1,40 @@
+@variant(A)
+Feature: Tag headers
+
+  @variant(B)
+  Scenario: Scenario tag overrides feature tag
+    Given "backend" receives "GET" request "/variant"
+    And "backend" request includes header "X-Experiment: B"
+    And "backend" responds with status "OK" and body
+    """json
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/variant"
+    Then I should have response with status "OK"
+
+  Scenario: Feature tag is inherited
+    Given "backend" receives "GET" request "/variant"
+    And "backend" responds with status "OK" and body
+    """json
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/variant"
+    And I request HTTP endpoint with header "X-Other: foo"
+    Then I should have response with status "OK"
+
+  Scenario: Mocks assert received variant header
+    Given "backend" receives "GET" request "/variant"
+    And "backend" responds with status "OK" and body
+    """json
+    {"ok":true}
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/variant"
+    And I request HTTP endpoint with header "X-Experiment: C"
+    Then I should have response with status "Internal Server Error"
+
+    # Mismatched request does not consume expectation.
+    When I request HTTP endpoint with method "GET" and URI "/variant"
+    And I should have response with status "OK"
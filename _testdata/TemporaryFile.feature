@@ -0,0 +1,28 @@
+Feature: Temporary file
+
+  Scenario: Uploading temporary file
+    Given variable $name is set to "foo"
+    And a temporary file "data.csv" with content
+    """
+    id,name
+    1,$name
+    """
+
+    When I request HTTP endpoint with method "POST" and URI "/upload"
+    And I request HTTP endpoint with attachment as field "file" from file
+    """
+    $data.csv
+    """
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    data.csv: id,name
+    1,foo
+    """
+
+  Scenario: Invalid file name
+    Given a temporary file "../data.csv" with content
+    """
+    id
+    """
@@ -0,0 +1,26 @@
+Feature: Text bodies
+
+  Scenario: Body with different line endings is compared exactly
+    When I request HTTP endpoint with method "GET" and URI "/report.csv"
+    Then I should have response with body
+    """
+    id,name
+    1,foo
+    2,bar
+    """
+
+  Scenario: Body is compared ignoring line endings and trailing whitespace
+    When I request HTTP endpoint with method "GET" and URI "/report.csv"
+    Then I should have response with body, ignoring line endings and trailing whitespace
+    """
+    id,name
+    1,foo
+    2,bar
+    """
+
+  Scenario: Body is compared with file ignoring line endings and trailing whitespace
+    When I request HTTP endpoint with method "GET" and URI "/report-lf.csv"
+    Then I should have response with body from file, ignoring line endings and trailing whitespace
+    """
+    _testdata/crlf.csv
+    """
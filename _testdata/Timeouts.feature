@@ -0,0 +1,9 @@
+Feature: Transport timeouts
+
+  Scenario: Service with response header timeout
+    When I request "limited" HTTP endpoint with method "GET" and URI "/slow"
+    Then I should have "limited" response with status "OK"
+
+  Scenario: Default service without timeouts
+    When I request HTTP endpoint with method "GET" and URI "/slow"
+    Then I should have response with status "OK"
@@ -0,0 +1,25 @@
+Feature: Trailers
+
+  Scenario: Request and response trailers
+    Given variable $checksum is set to "abc"
+    When I request HTTP endpoint with method "POST" and URI "/stream"
+    And I request HTTP endpoint with body
+    """
+    hello
+    """
+    And I request HTTP endpoint with trailer "X-Checksum: $checksum"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    hello abc
+    """
+    And I should have response with trailer "Grpc-Status: 0"
+
+  Scenario: Request trailer without body
+    When I request HTTP endpoint with method "GET" and URI "/stream"
+    And I request HTTP endpoint with trailer "X-Checksum: def"
+    Then I should have response with body
+    """
+     def
+    """
+    And I should have response with trailer "Grpc-Status: 1"
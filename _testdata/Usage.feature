@@ -0,0 +1,13 @@
+Feature: Step usage
+
+  Scenario: Successful request
+    When I request HTTP endpoint with method "GET" and URI "/ok"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    ok
+    """
+
+  Scenario: Another request
+    When I request HTTP endpoint with method "GET" and URI "/ok"
+    Then I should have response with status "OK"
@@ -0,0 +1,23 @@
+Feature: Warnings
+
+  @warnings
+  Scenario: Failed assertions of tagged scenario are warnings
+    When I request HTTP endpoint with method "GET" and URI "/warn"
+    Then I should have response with status "Created"
+    And I should have response with header "X-Foo: bar"
+    And I should have response with body
+    """json
+    {"strict":true}
+    """
+
+  Scenario: Severity is switched with steps
+    When I request HTTP endpoint with method "GET" and URI "/warn"
+    Given failed response assertions are warnings
+    Then I should have response with body, that matches JSON paths
+      | $.strict | true |
+    And failed response assertions are errors
+    And I should have response with status "OK"
+    And I should have response with body
+    """json
+    {"strict":false}
+    """
@@ -0,0 +1,21 @@
+Feature: Export of expectations as WireMock mappings
+
+  Scenario: Expectations are collected
+    Given "some-service" receives "GET" request "/users/{id}"
+    And "some-service" responds with status "Forbidden" when request header "X-Role" is "guest"
+    And "some-service" responds with status "OK" and body
+    """
+    {"id":"$id"}
+    """
+
+    And "some-service" receives "POST" request "/users" with body including JSON
+    """
+    {"name":"John"}
+    """
+    And "some-service" responds with status "Created" and body
+    """
+    created
+    """
+
+    Then I call "GET" "/users/1" with body "" and receive "{"id":"1"}"
+    And I call "POST" "/users" with body "{"name":"John","age":30}" and receive "created"
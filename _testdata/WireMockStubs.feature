@@ -0,0 +1,15 @@
+Feature: Stubs of external service
+
+  Scenario: Unmatched requests are served with stubs
+    Given "some-service" receives "GET" request "/users/1"
+    And "some-service" responds with status "OK" and body
+    """
+    {"id":1,"name":"Mocked"}
+    """
+
+    Then I call "GET" "/users/1" with body "" and receive "200 {"id":1,"name":"Mocked"}"
+    And I call "GET" "/users/1" with body "" and receive "200 {"id":1,"name":"Jane"}"
+    And I call "GET" "/users/0?full=1" with body "" and receive "200 {"id":1,"name":"Jane"}"
+    And I call "GET" "/users/0" with body "" and receive "404 {"error":"not found"}"
+    And I call "POST" "/users?debug=1" with body "{"name":"John"}" and receive "201 {"id":2,"name":"John"}"
+    And I call "DELETE" "/health" with body "" and receive "200 ok"
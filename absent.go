@@ -0,0 +1,139 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/yalp/jsonpath"
+)
+
+// notContains checks that received body does not have a substring, e.g. a leaked secret.
+func (l *LocalClient) notContains(ctx context.Context, received []byte, bodyDoc string) error {
+	ctx, rv, err := l.VS.Replace(ctx, []byte(bodyDoc))
+	if err != nil {
+		return err
+	}
+
+	if l.normalizesText(false, received) {
+		received, rv = normalizeText(received), normalizeText(rv)
+	}
+
+	s, substr := string(received), string(rv)
+	if strings.Contains(s, substr) {
+		return augmentBodyErr(ctx, fmt.Errorf("%w %q in %q", ErrContains, substr, s))
+	}
+
+	return nil
+}
+
+// absentJSONPaths checks that JSON paths of the first column are missing in received body,
+// or do not have values of the optional second column, empty value means the path must be missing.
+//
+// Path that resolves to an array (e.g. "$..role") is absent if no element equals the value.
+func (l *LocalClient) absentJSONPaths(ctx context.Context, received []byte, table *godog.Table) error {
+	var rcv interface{}
+	if err := json.Unmarshal(received, &rcv); err != nil {
+		return fmt.Errorf("failed to unmarshal received value: %w", err)
+	}
+
+	for _, row := range table.Rows {
+		if len(row.Cells) != 1 && len(row.Cells) != 2 {
+			return fmt.Errorf("%w, 1 or 2 expected, %d received", ErrInvalidNumberOfColumns, len(row.Cells))
+		}
+
+		path := row.Cells[0].Value
+
+		filter, err := jsonpath.Prepare(path)
+		if err != nil {
+			return fmt.Errorf("%w: jsonpath %s: %s", ErrInvalidValue, path, err.Error())
+		}
+
+		// Missing key or out of bound index is an absent value.
+		val, err := filter(rcv)
+		if err != nil {
+			continue
+		}
+
+		if len(row.Cells) == 1 || row.Cells[1].Value == "" {
+			if list, ok := val.([]interface{}); ok && len(list) == 0 {
+				continue
+			}
+
+			return augmentBodyErr(ctx, fmt.Errorf("%w %s: %s", ErrContains, path, jsonValue(val)))
+		}
+
+		ctx, expected, err := l.VS.Replace(ctx, []byte(row.Cells[1].Value))
+		if err != nil {
+			return err
+		}
+
+		var exp interface{}
+		if err := json.Unmarshal(expected, &exp); err != nil {
+			return fmt.Errorf("%w: JSON value of %s: %s", ErrInvalidValue, path, err.Error())
+		}
+
+		if containsValue(val, exp) {
+			return augmentBodyErr(ctx, fmt.Errorf("%w %s: %s", ErrContains, path, string(expected)))
+		}
+	}
+
+	return nil
+}
+
+// containsValue is true if value or one of its array elements is equal to expected.
+func containsValue(val, expected interface{}) bool {
+	if reflect.DeepEqual(val, expected) {
+		return true
+	}
+
+	list, ok := val.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, v := range list {
+		if reflect.DeepEqual(v, expected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonValue is a JSON representation of a value for error messages.
+func jsonValue(val interface{}) string {
+	j, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+
+	return string(j)
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyNotContaining(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.notContains(ctx, received, bodyDoc)
+	})
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyNotContaining(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.notContains(ctx, received, bodyDoc)
+	})
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyNotContainingJSONPaths(ctx context.Context, service string, table *godog.Table) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.absentJSONPaths(ctx, received, table)
+	})
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyNotContainingJSONPaths(ctx context.Context, service string, table *godog.Table) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.absentJSONPaths(ctx, received, table)
+	})
+}
@@ -0,0 +1,89 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/bool64/httpmock"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// jitterFactor is a randomization factor of jittered backoff, delay is picked from [0.5, 1.5] of interval.
+const jitterFactor = 0.5
+
+func (l *LocalClient) iRetryWithBackOff(ctx context.Context, service, tries, jittered, strategy, interval, statuses string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	every, err := time.ParseDuration(interval)
+	if err != nil || every <= 0 {
+		return ctx, fmt.Errorf("%w: backoff interval: %q", ErrInvalidValue, interval)
+	}
+
+	retrier := constantBackOff(every, jittered != "")
+	if strategy == "exponential" {
+		retrier = exponentialBackOff(every, jittered != "")
+	}
+
+	ctx, b, err := limitedBackOff(ctx, tries, retrier)
+	if err != nil {
+		return ctx, err
+	}
+
+	if statuses != "" {
+		if b, err = retryWhileStatus(c, b, statuses); err != nil {
+			return ctx, err
+		}
+	}
+
+	c.AllowRetries(b)
+
+	return ctx, nil
+}
+
+// constantBackOff retries with a fixed interval, optionally randomized.
+func constantBackOff(every time.Duration, jittered bool) func(ctx context.Context, maxElapsed time.Duration) (context.Context, httpmock.RetryBackOff) {
+	return func(ctx context.Context, maxElapsed time.Duration) (context.Context, httpmock.RetryBackOff) {
+		start := time.Now()
+
+		return ctx, httpmock.RetryBackOffFunc(func() time.Duration {
+			dur := every
+
+			if jittered {
+				dur = time.Duration(float64(every) * (1 - jitterFactor + 2*jitterFactor*rand.Float64())) //nolint:gosec // Jitter does not need secure random.
+			}
+
+			if maxElapsed > 0 && time.Since(start)+dur > maxElapsed {
+				return backoff.Stop
+			}
+
+			return dur
+		})
+	}
+}
+
+// exponentialBackOff retries with exponentially growing interval, optionally randomized.
+func exponentialBackOff(initial time.Duration, jittered bool) func(ctx context.Context, maxElapsed time.Duration) (context.Context, httpmock.RetryBackOff) {
+	return func(ctx context.Context, maxElapsed time.Duration) (context.Context, httpmock.RetryBackOff) {
+		eb := backoff.NewExponentialBackOff()
+		eb.InitialInterval = initial
+		eb.RandomizationFactor = 0
+		eb.MaxElapsedTime = 0
+
+		if jittered {
+			eb.RandomizationFactor = jitterFactor
+		}
+
+		if maxElapsed > 0 {
+			eb.MaxElapsedTime = maxElapsed
+		}
+
+		eb.Reset()
+
+		return ctx, eb
+	}
+}
@@ -0,0 +1,192 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// responsePart is a sub-response of a batch or multi-status response.
+type responsePart struct {
+	id     string
+	status int
+	body   []byte
+}
+
+// batchParts splits JSON batch or WebDAV multi-status body into parts.
+//
+// JSON batch is an array of parts or an object with such array in "responses", every part has "id" (1-based
+// index is used if missing), "status" and "body". WebDAV part is identified by "href", body is contents of "prop".
+func batchParts(body []byte) ([]responsePart, error) {
+	body = bytes.TrimSpace(body)
+
+	if bytes.HasPrefix(body, []byte("<")) {
+		return multiStatusParts(body)
+	}
+
+	var items []struct {
+		ID     interface{}     `json:"id"`
+		Status interface{}     `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	}
+
+	if bytes.HasPrefix(body, []byte("{")) {
+		var batch struct {
+			Responses json.RawMessage `json:"responses"`
+		}
+
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal batch response: %w", err)
+		}
+
+		body = batch.Responses
+	}
+
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch response: %w", err)
+	}
+
+	parts := make([]responsePart, 0, len(items))
+
+	for i, item := range items {
+		p := responsePart{id: strconv.Itoa(i + 1), body: item.Body}
+
+		if item.ID != nil {
+			p.id = plainValue(item.ID)
+		}
+
+		code, err := statusCode(plainValue(item.Status))
+		if err != nil {
+			return nil, fmt.Errorf("part %s: %w", p.id, err)
+		}
+
+		p.status = code
+		parts = append(parts, p)
+	}
+
+	return parts, nil
+}
+
+// multiStatusParts splits WebDAV multi-status body into parts.
+func multiStatusParts(body []byte) ([]responsePart, error) {
+	type prop struct {
+		Inner []byte `xml:",innerxml"`
+	}
+
+	var ms struct {
+		Responses []struct {
+			Href     string `xml:"href"`
+			Status   string `xml:"status"`
+			Propstat []struct {
+				Status string `xml:"status"`
+				Prop   prop   `xml:"prop"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal multi-status response: %w", err)
+	}
+
+	parts := make([]responsePart, 0, len(ms.Responses))
+
+	for _, r := range ms.Responses {
+		p := responsePart{id: strings.TrimSpace(r.Href)}
+		status := r.Status
+
+		if len(r.Propstat) > 0 {
+			if status == "" {
+				status = r.Propstat[0].Status
+			}
+
+			p.body = bytes.TrimSpace(r.Propstat[0].Prop.Inner)
+		}
+
+		// Status line, e.g. "HTTP/1.1 200 OK".
+		f := strings.Fields(status)
+		if len(f) < 2 {
+			return nil, fmt.Errorf("part %s: %w: status %q", p.id, ErrInvalidValue, status)
+		}
+
+		code, err := statusCode(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("part %s: %w", p.id, err)
+		}
+
+		p.status = code
+		parts = append(parts, p)
+	}
+
+	return parts, nil
+}
+
+func (l *LocalClient) iShouldHaveResponseWithParts(ctx context.Context, service string, data *godog.Table) (context.Context, error) {
+	if len(data.Rows) < 2 {
+		return ctx, fmt.Errorf("%w: header and at least one part expected", ErrInvalidValue)
+	}
+
+	columns := make(map[string]int)
+
+	for i, cell := range data.Rows[0].Cells {
+		columns[strings.TrimSpace(cell.Value)] = i
+	}
+
+	idCol, ok := columns["id"]
+	if !ok {
+		return ctx, fmt.Errorf("%w: missing id column", ErrInvalidValue)
+	}
+
+	statusCol, hasStatus := columns["status"]
+	bodyCol, hasBody := columns["body"]
+
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		parts, err := batchParts(received)
+		if err != nil {
+			return augmentBodyErr(ctx, err)
+		}
+
+		byID := make(map[string]responsePart, len(parts))
+		for _, p := range parts {
+			byID[p.id] = p
+		}
+
+		for _, row := range data.Rows[1:] {
+			if len(row.Cells) != len(data.Rows[0].Cells) {
+				return ErrInvalidNumberOfColumns
+			}
+
+			id := row.Cells[idCol].Value
+
+			p, found := byID[id]
+			if !found {
+				return augmentBodyErr(ctx, fmt.Errorf("%w: %s", ErrMissingPart, id))
+			}
+
+			if hasStatus {
+				code, err := statusCode(row.Cells[statusCol].Value)
+				if err != nil {
+					return err
+				}
+
+				if code != p.status {
+					return augmentBodyErr(ctx, fmt.Errorf("part %s: unexpected status, expected: %d, received: %d",
+						id, code, p.status))
+				}
+			}
+
+			if hasBody {
+				if _, err := l.VS.Assert(ctx, []byte(row.Cells[bodyCol].Value), p.body, false); err != nil {
+					return augmentBodyErr(ctx, fmt.Errorf("part %s: %w", id, err))
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,85 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// hexContextSize is a number of bytes around first difference to show in binary body mismatch.
+const hexContextSize = 8
+
+func (l *LocalClient) iShouldHaveResponseWithBinaryBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+	return l.expectBinaryBodyFromFile(ctx, service, false, filePath)
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithBinaryBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+	return l.expectBinaryBodyFromFile(ctx, service, true, filePath)
+}
+
+// expectBinaryBodyFromFile checks response body to be byte-exact with file contents, variables are not replaced.
+func (l *LocalClient) expectBinaryBodyFromFile(ctx context.Context, service string, other bool, filePath string) (context.Context, error) {
+	ctx, filePath, err := resolveFilePath(ctx, l.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	expected, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return ctx, err
+	}
+
+	return l.expectBody(ctx, service, other, func(_ context.Context, received []byte) error {
+		return compareBytes(expected, received)
+	})
+}
+
+// compareBytes reports offset of first difference with hex dump of bytes around it.
+func compareBytes(expected, received []byte) error {
+	if bytes.Equal(expected, received) {
+		return nil
+	}
+
+	offset := 0
+	for offset < len(expected) && offset < len(received) && expected[offset] == received[offset] {
+		offset++
+	}
+
+	return unexpectedBodyError{err: fmt.Errorf("%w at offset %d (0x%x), expected %d bytes, received %d bytes\nexpected: %s\nreceived: %s",
+		ErrBytesDiffer, offset, offset, len(expected), len(received), hexContext(expected, offset), hexContext(received, offset))}
+}
+
+// hexContext dumps bytes around offset, byte at offset is enclosed in brackets.
+func hexContext(b []byte, offset int) string {
+	start := offset - hexContextSize
+	if start < 0 {
+		start = 0
+	}
+
+	end := offset + hexContextSize
+	if end > len(b) {
+		end = len(b)
+	}
+
+	res := strings.Builder{}
+
+	res.WriteString(fmt.Sprintf("%08x:", start))
+
+	for i := start; i < end; i++ {
+		if i == offset {
+			res.WriteString(fmt.Sprintf(" [%02x]", b[i]))
+		} else {
+			res.WriteString(fmt.Sprintf(" %02x", b[i]))
+		}
+	}
+
+	if offset >= len(b) {
+		res.WriteString(" [EOF]")
+	} else if end < len(b) {
+		res.WriteString(" ...")
+	}
+
+	return res.String()
+}
@@ -0,0 +1,218 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/assertjson/json5"
+)
+
+// BodyDiff configures reporting of JSON body mismatch as a list of differing paths, e.g.
+//
+//	unexpected body with 2 differing paths:
+//	  $.user.name: expected "John", received "Jane"
+//	  $.user.tags[1]: missing, expected "admin"
+//
+// Such report is easier to read in CI logs than a full document diff of a large body.
+// Original error is kept if the difference can not be resolved to paths, e.g. for non-JSON bodies.
+type BodyDiff struct {
+	// Color enables ANSI colors of expected (red) and received (green) values.
+	Color bool
+
+	// MaxPaths limits number of reported paths, no limit by default.
+	MaxPaths int
+}
+
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// pathDiff is a difference of JSON value at path.
+type pathDiff struct {
+	path     string
+	exp, rcv interface{}
+	missing  bool
+	added    bool
+}
+
+// explain returns error with differing paths or original error if there are no paths.
+func (d *BodyDiff) explain(diffs []pathDiff, err error) error {
+	if len(diffs) == 0 {
+		return err
+	}
+
+	msg := strings.Builder{}
+
+	if len(diffs) == 1 {
+		msg.WriteString("with 1 differing path:")
+	} else {
+		msg.WriteString("with " + strconv.Itoa(len(diffs)) + " differing paths:")
+	}
+
+	shown := diffs
+	if d.MaxPaths > 0 && len(shown) > d.MaxPaths {
+		shown = shown[:d.MaxPaths]
+	}
+
+	for _, pd := range shown {
+		msg.WriteString("\n  " + pd.path + ": ")
+
+		switch {
+		case pd.missing:
+			msg.WriteString("missing, expected " + d.value(pd.exp, colorRed))
+		case pd.added:
+			msg.WriteString("unexpected, received " + d.value(pd.rcv, colorGreen))
+		default:
+			msg.WriteString("expected " + d.value(pd.exp, colorRed) + ", received " + d.value(pd.rcv, colorGreen))
+		}
+	}
+
+	if omitted := len(diffs) - len(shown); omitted > 0 {
+		msg.WriteString(fmt.Sprintf("\n  ... and %d more", omitted))
+	}
+
+	return bodyDiffError{msg: msg.String(), err: err}
+}
+
+// value returns compact JSON of value, colored if enabled.
+func (d *BodyDiff) value(v interface{}, color string) string {
+	s := "<invalid>"
+
+	if j, err := json.Marshal(v); err == nil {
+		s = string(j)
+	}
+
+	if d.Color {
+		return color + s + colorReset
+	}
+
+	return s
+}
+
+// bodyDiffError describes body mismatch with differing paths.
+type bodyDiffError struct {
+	msg string
+	err error
+}
+
+// Error returns differing paths.
+func (e bodyDiffError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns original error.
+func (e bodyDiffError) Unwrap() error {
+	return e.err
+}
+
+// jsonPathDiff returns differing paths of received JSON, nil if payloads can not be decoded.
+func (l *LocalClient) jsonPathDiff(ctx context.Context, opts JSONOptions, expected, received []byte, ignoreAddedJSONFields bool) []pathDiff {
+	ctx, expected, err := l.VS.Replace(ctx, expected)
+	if err != nil || !json5.Valid(expected) || !json.Valid(received) {
+		return nil
+	}
+
+	if expected, err = json5.Downgrade(expected); err != nil {
+		return nil
+	}
+
+	_, a := l.jsonAligner(ctx, opts, ignoreAddedJSONFields)
+
+	var exp, rcv interface{}
+
+	if decodeJSON(expected, &exp) != nil || decodeJSON(received, &rcv) != nil {
+		return nil
+	}
+
+	exp = a.markers(exp)
+	rcv = a.align(exp, rcv)
+
+	var diffs []pathDiff
+
+	a.diff("$", exp, rcv, &diffs)
+
+	return diffs
+}
+
+// identifier matches object key that can be used in path with dot notation.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// childPath returns path of object property.
+func childPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// diff collects differences of aligned received value.
+func (a jsonAligner) diff(path string, exp, rcv interface{}, diffs *[]pathDiff) {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		r, ok := rcv.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(e)+len(r))
+		for k := range e {
+			keys = append(keys, k)
+		}
+
+		for k := range r {
+			if _, found := e[k]; !found && !a.ignoreAdded {
+				keys = append(keys, k)
+			}
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			ev, inExp := e[k]
+			rv, inRcv := r[k]
+
+			switch {
+			case !inRcv:
+				*diffs = append(*diffs, pathDiff{path: childPath(path, k), exp: ev, missing: true})
+			case !inExp:
+				*diffs = append(*diffs, pathDiff{path: childPath(path, k), rcv: rv, added: true})
+			default:
+				a.diff(childPath(path, k), ev, rv, diffs)
+			}
+		}
+
+		return
+	case []interface{}:
+		r, ok := rcv.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(e) || i < len(r); i++ {
+			p := path + "[" + strconv.Itoa(i) + "]"
+
+			switch {
+			case i >= len(r):
+				*diffs = append(*diffs, pathDiff{path: p, exp: e[i], missing: true})
+			case i >= len(e):
+				*diffs = append(*diffs, pathDiff{path: p, rcv: r[i], added: true})
+			default:
+				a.diff(p, e[i], r[i], diffs)
+			}
+		}
+
+		return
+	}
+
+	if !a.matches(exp, rcv) {
+		*diffs = append(*diffs, pathDiff{path: path, exp: exp, rcv: rcv})
+	}
+}
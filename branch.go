@@ -0,0 +1,130 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// responseBranch is a conditional response of expectation, it is served instead of regular response
+// if request meets condition.
+type responseBranch struct {
+	// header or body JSON path is checked.
+	header, jsonPath string
+	value            string
+
+	status int
+	body   []byte
+
+	// requestURI is a unique request URI of branch expectation, request is rewritten to it if condition is met.
+	requestURI string
+}
+
+// meets checks if request meets condition of branch.
+func (b responseBranch) meets(req *http.Request, body []byte) bool {
+	if b.header != "" {
+		for _, v := range req.Header.Values(b.header) {
+			if v == b.value {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	val, err := jsonPathValue(body, b.jsonPath)
+	if err != nil {
+		return false
+	}
+
+	if s, ok := val.(string); ok {
+		return s == b.value
+	}
+
+	return jsonValue(val) == b.value
+}
+
+// routeBranch rewrites request URI to URI of the first conditional response with met condition
+// of the next matching expectation.
+func (m *mock) routeBranch(req *http.Request, body []byte) {
+	for _, e := range m.candidates() {
+		if len(e.branches) == 0 ||
+			(e.Method != "" && e.Method != req.Method) ||
+			(e.RequestURI != "" && e.RequestURI != req.RequestURI) {
+			continue
+		}
+
+		for _, b := range e.branches {
+			if b.meets(req, body) {
+				req.RequestURI = b.requestURI
+
+				return
+			}
+		}
+	}
+}
+
+// expectBranches adds expectations of conditional responses, they are unlimited and async, so that
+// requests meeting conditions can be received any number of times.
+func (m *mock) expectBranches(e *exp) {
+	for i, b := range e.branches {
+		m.branchSeq++
+
+		be := exp{async: true}
+		be.Method = e.Method
+		be.RequestURI = fmt.Sprintf("%s#when-%d", e.RequestURI, m.branchSeq)
+		be.Unlimited = true
+		be.Status = b.status
+		be.ResponseBody = b.body
+		be.ResponseHeader = map[string]string{}
+
+		e.branches[i].requestURI = be.RequestURI
+
+		m.expect(be)
+	}
+}
+
+func (e *ExternalServer) serviceRespondsWithStatusWhen(
+	ctx context.Context,
+	service, statusOrCode, subject, name, value string,
+	body []byte,
+) (context.Context, error) {
+	code, err := statusCode(statusOrCode)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, v, err := e.VS.Replace(ctx, []byte(value))
+	if err != nil {
+		return ctx, err
+	}
+
+	b := responseBranch{value: string(v), status: code, body: body}
+
+	if subject == "header" {
+		b.header = name
+	} else {
+		b.jsonPath = name
+	}
+
+	m.exp.branches = append(m.exp.branches, b)
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceRespondsWithStatusAndBodyWhen(
+	ctx context.Context,
+	service, statusOrCode, subject, name, value, bodyDoc string,
+) (context.Context, error) {
+	ctx, body, err := replaceVars(ctx, e.VS, []byte(bodyDoc))
+	if err != nil {
+		return ctx, err
+	}
+
+	return e.serviceRespondsWithStatusWhen(ctx, service, statusOrCode, subject, name, value, body)
+}
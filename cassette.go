@@ -0,0 +1,153 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/bool64/httpmock"
+	"gopkg.in/yaml.v3"
+)
+
+// Cassette is a YAML file with recorded requests of a service and responses to them.
+//
+// File contains a list of records, each has a request (method, uri, body) and a response (status, headers, body).
+type Cassette struct {
+	mu       sync.Mutex
+	filePath string
+	records  []cassetteRecord
+	err      error
+}
+
+type cassetteRecord struct {
+	Request struct {
+		Method string `yaml:"method"`
+		URI    string `yaml:"uri"`
+		Body   string `yaml:"body,omitempty"`
+	} `yaml:"request"`
+	Response struct {
+		Status  int               `yaml:"status"`
+		Headers map[string]string `yaml:"headers,omitempty"`
+		Body    string            `yaml:"body,omitempty"`
+	} `yaml:"response"`
+}
+
+// NewCassette creates a cassette to record requests into a file.
+//
+// Use Record as a recorder of ExternalServer.WithPassthrough to capture real traffic of service.
+func NewCassette(filePath string) *Cassette {
+	return &Cassette{filePath: filePath}
+}
+
+// LoadCassette reads recorded requests from a file.
+func LoadCassette(filePath string) (*Cassette, error) {
+	data, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{filePath: filePath}
+
+	if err := yaml.Unmarshal(data, &c.records); err != nil {
+		return nil, fmt.Errorf("failed to load cassette %s: %w", filePath, err)
+	}
+
+	return c, nil
+}
+
+// Record adds request with response to cassette and saves the file.
+//
+// It is safe for concurrent use, failure to save is available with Err.
+func (c *Cassette) Record(exchange httpmock.Expectation) {
+	r := cassetteRecord{}
+	r.Request.Method = exchange.Method
+	r.Request.URI = exchange.RequestURI
+	r.Request.Body = string(exchange.RequestBody)
+	r.Response.Status = exchange.Status
+	r.Response.Headers = exchange.ResponseHeader
+	r.Response.Body = string(exchange.ResponseBody)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records = append(c.records, r)
+
+	data, err := yaml.Marshal(c.records)
+	if err == nil {
+		err = os.WriteFile(c.filePath, data, 0o600)
+	}
+
+	if err != nil {
+		c.err = fmt.Errorf("failed to save cassette %s: %w", c.filePath, err)
+	}
+}
+
+// Err returns the last failure to save recorded requests.
+func (c *Cassette) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Expectations returns recorded requests as expectations, each request is expected once.
+func (c *Cassette) Expectations() []httpmock.Expectation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make([]httpmock.Expectation, 0, len(c.records))
+
+	for _, r := range c.records {
+		e := httpmock.Expectation{
+			Method:         r.Request.Method,
+			RequestURI:     r.Request.URI,
+			Status:         r.Response.Status,
+			ResponseHeader: r.Response.Headers,
+		}
+
+		if r.Request.Body != "" {
+			e.RequestBody = []byte(r.Request.Body)
+		}
+
+		if r.Response.Body != "" {
+			e.ResponseBody = []byte(r.Response.Body)
+		}
+
+		if e.ResponseHeader == nil {
+			e.ResponseHeader = map[string]string{}
+		}
+
+		res = append(res, e)
+	}
+
+	return res
+}
+
+func (e *ExternalServer) serviceReplaysCassette(ctx context.Context, service, filePath string) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	if m.exp != nil {
+		return ctx, fmt.Errorf("%w for %q: %+v", ErrUnexpectedExpectations, service, *m.exp)
+	}
+
+	ctx, filePath, err = resolveFilePath(ctx, e.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	c, err := LoadCassette(filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	// Recorded requests are expected in any order, repeated requests are served with responses in recorded order.
+	for _, ex := range c.Expectations() {
+		m.expect(exp{Expectation: ex, async: true})
+	}
+
+	return ctx, nil
+}
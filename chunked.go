@@ -0,0 +1,85 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// chunking streams response body line by line.
+type chunking struct {
+	// interval is a pause between chunks.
+	interval time.Duration
+}
+
+// stream sends kept response body in chunks, every line of body is flushed as a chunk.
+func (w *responseWriter) stream(req *http.Request) {
+	if w.chunking == nil || w.fault != nil {
+		return
+	}
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+
+	f, _ := w.ResponseWriter.(http.Flusher)
+
+	for i, chunk := range bytes.SplitAfter(w.body, []byte("\n")) {
+		if len(chunk) == 0 {
+			continue
+		}
+
+		if i > 0 && w.chunking.interval > 0 {
+			t := time.NewTimer(w.chunking.interval)
+
+			select {
+			case <-t.C:
+			case <-req.Context().Done():
+				t.Stop()
+
+				return
+			}
+		}
+
+		if _, err := w.ResponseWriter.Write(chunk); err != nil {
+			return
+		}
+
+		if f != nil {
+			f.Flush()
+		}
+	}
+}
+
+func (e *ExternalServer) serviceRespondsWithChunkedBody(ctx context.Context, service, interval, bodyDoc string) (context.Context, error) {
+	c := chunking{}
+
+	if interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d < 0 {
+			return ctx, fmt.Errorf("%w: chunk interval %q", ErrInvalidValue, interval)
+		}
+
+		c.interval = d
+	}
+
+	ctx, m, err := e.responding(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.chunking = &c
+
+	ctx, body, err := replaceVars(ctx, e.VS, []byte(bodyDoc))
+	if err != nil {
+		return ctx, err
+	}
+
+	return e.serviceRespondsWithStatusAndPreparedBody(ctx, service, strconv.Itoa(http.StatusOK), body)
+}
@@ -0,0 +1,137 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bool64/shared"
+)
+
+// ConcurrentVarsPolicy defines how variables captured from concurrent responses are merged.
+type ConcurrentVarsPolicy int
+
+const (
+	// ConcurrentVarsStrict fails if concurrent responses provide different values for a variable.
+	ConcurrentVarsStrict ConcurrentVarsPolicy = iota
+
+	// ConcurrentVarsCollect sets variable to an array of values captured from all concurrent responses.
+	//
+	// Values are sorted by their JSON representation.
+	ConcurrentVarsCollect
+)
+
+// checkBodies checks received body or all concurrent bodies.
+//
+// Each of concurrent bodies is checked against an isolated snapshot of variables,
+// captured variables are merged according to ConcurrentVars policy.
+func (l *LocalClient) checkBodies(
+	ctx context.Context,
+	bodies [][]byte,
+	received []byte,
+	check func(ctx context.Context, received []byte) error,
+) error {
+	if len(bodies) < 2 {
+		return check(ctx, received)
+	}
+
+	_, v := l.VS.Vars(ctx)
+	base := v.GetAll()
+	captured := map[string][]interface{}{}
+
+	for _, body := range bodies {
+		snapshot := &shared.Vars{VarPrefix: v.VarPrefix}
+		for k, val := range base {
+			snapshot.Set(k, val)
+		}
+
+		sctx, sv := snapshot.Fork(context.Background())
+
+		if err := check(sctx, body); err != nil {
+			return err
+		}
+
+		for k, val := range sv.GetAll() {
+			if prev, ok := base[k]; ok && reflect.DeepEqual(prev, val) {
+				continue
+			}
+
+			captured[k] = append(captured[k], val)
+		}
+	}
+
+	return l.mergeVars(v, captured)
+}
+
+func (l *LocalClient) mergeVars(v *shared.Vars, captured map[string][]interface{}) error {
+	names := make([]string, 0, len(captured))
+	for name := range captured {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var conflicts []string
+
+	for _, name := range names {
+		values, encoded := sortValues(captured[name])
+
+		if l.ConcurrentVars == ConcurrentVarsCollect {
+			v.Set(name, values)
+
+			continue
+		}
+
+		for _, e := range encoded[1:] {
+			if e != encoded[0] {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s", name, strings.Join(encoded, ", ")))
+
+				break
+			}
+		}
+
+		v.Set(name, values[0])
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s", ErrConflictingVars, strings.Join(conflicts, "; "))
+	}
+
+	return nil
+}
+
+// sortValues sorts values by JSON representation and returns it too.
+func sortValues(values []interface{}) ([]interface{}, []string) {
+	encoded := make([]string, len(values))
+
+	for i, val := range values {
+		j, err := json.Marshal(val)
+		if err != nil {
+			j = []byte(fmt.Sprintf("%v", val))
+		}
+
+		encoded[i] = string(j)
+	}
+
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	sort.SliceStable(idx, func(i, j int) bool {
+		return encoded[idx[i]] < encoded[idx[j]]
+	})
+
+	sortedValues := make([]interface{}, len(values))
+	sortedEncoded := make([]string, len(values))
+
+	for i, k := range idx {
+		sortedValues[i] = values[k]
+		sortedEncoded[i] = encoded[k]
+	}
+
+	return sortedValues, sortedEncoded
+}
@@ -0,0 +1,145 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bool64/httpmock"
+)
+
+// quotedArgs captures double-quoted arguments of a list, e.g. `"HttpOnly", "Secure" and "SameSite=Strict"`.
+var quotedArgs = regexp.MustCompile(`"([^"]*)"`)
+
+// responseCookie finds the last cookie with a name in Set-Cookie headers.
+func responseCookie(resp *http.Response, name string) (*http.Cookie, error) {
+	var found *http.Cookie
+
+	for _, c := range resp.Cookies() {
+		if c.Name == name {
+			found = c
+		}
+	}
+
+	if found == nil {
+		names := make([]string, 0)
+		for _, c := range resp.Cookies() {
+			names = append(names, c.Name)
+		}
+
+		return nil, fmt.Errorf("%w %s, received: [%s]", ErrMissingCookie, name, strings.Join(names, ", "))
+	}
+
+	return found, nil
+}
+
+// checkCookieAttribute checks attribute in a form of "Name" or "Name=Value", e.g. "HttpOnly" or "SameSite=Strict".
+func checkCookieAttribute(c *http.Cookie, attribute string) error {
+	name, value, withValue := strings.Cut(attribute, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+	var (
+		received string
+		present  bool
+	)
+
+	switch strings.ToLower(name) {
+	case "httponly":
+		present = c.HttpOnly
+	case "secure":
+		present = c.Secure
+	case "samesite":
+		received, present = sameSite(c.SameSite)
+	case "path":
+		received, present = c.Path, c.Path != ""
+	case "domain":
+		received, present = c.Domain, c.Domain != ""
+	case "max-age":
+		received, present = strconv.Itoa(c.MaxAge), c.MaxAge != 0
+		if c.MaxAge < 0 {
+			received = "0"
+		}
+	case "expires":
+		present = !c.Expires.IsZero()
+	default:
+		return fmt.Errorf("%w: cookie attribute %q", ErrInvalidValue, attribute)
+	}
+
+	if !present {
+		return fmt.Errorf("%w: %s is missing in cookie %s", ErrUnexpectedCookie, name, c.Name)
+	}
+
+	if withValue && !strings.EqualFold(received, value) {
+		return fmt.Errorf("%w: %s of cookie %s is %q, expected %q", ErrUnexpectedCookie, name, c.Name, received, value)
+	}
+
+	return nil
+}
+
+// sameSite returns value of SameSite attribute.
+func sameSite(s http.SameSite) (string, bool) {
+	switch s {
+	case http.SameSiteStrictMode:
+		return "Strict", true
+	case http.SameSiteLaxMode:
+		return "Lax", true
+	case http.SameSiteNoneMode:
+		return "None", true
+	case http.SameSiteDefaultMode:
+		return "", true
+	default:
+		return "", false
+	}
+}
+
+// iShouldHaveResponseWithCookie checks cookie by name, optional value after colon (e.g. "session: $sid")
+// is compared or captured as a variable.
+func (l *LocalClient) iShouldHaveResponseWithCookie(ctx context.Context, service, cookie string) (context.Context, error) {
+	return l.iShouldHaveResponseWithCookieHaving(ctx, service, cookie, "")
+}
+
+func (l *LocalClient) iShouldHaveResponseWithCookieHaving(ctx context.Context, service, cookie, attributes string) (context.Context, error) {
+	ctx = l.VS.PrepareContext(ctx)
+
+	name, value, withValue := strings.Cut(cookie, ":")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+	ctx, err := l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		c, err := responseCookie(d.Resp, name)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range quotedArgs.FindAllStringSubmatch(attributes, -1) {
+			if err := checkCookieAttribute(c, m[1]); err != nil {
+				return err
+			}
+		}
+
+		if !withValue {
+			return nil
+		}
+
+		expected, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		received, err := json.Marshal(c.Value)
+		if err != nil {
+			return err
+		}
+
+		if _, err := l.VS.Assert(ctx, expected, received, false); err != nil {
+			return fmt.Errorf("unexpected value of cookie %s: %w", name, err)
+		}
+
+		return nil
+	})
+
+	return l.assertion(ctx, err)
+}
@@ -0,0 +1,107 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cucumber/godog"
+)
+
+// csvRecords converts CSV body to a list of records with columns of header,
+// only columns of expected header are kept.
+func csvRecords(received []byte, columns []string) ([]map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(received))
+	r.FieldsPerRecord = -1
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: empty CSV", ErrUnexpectedBody)
+	}
+
+	idx := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		idx[name] = i
+	}
+
+	for _, name := range columns {
+		if _, ok := idx[name]; !ok {
+			return nil, fmt.Errorf("%w %s, received: %v", ErrMissingColumn, name, rows[0])
+		}
+	}
+
+	res := make([]map[string]string, 0, len(rows)-1)
+
+	for _, row := range rows[1:] {
+		rec := make(map[string]string, len(columns))
+
+		for _, name := range columns {
+			if i := idx[name]; i < len(row) {
+				rec[name] = row[i]
+			}
+		}
+
+		res = append(res, rec)
+	}
+
+	return res, nil
+}
+
+// assertCSV compares CSV body with table, first row of table is a header with a subset of CSV columns.
+func (l *LocalClient) assertCSV(ctx context.Context, table *godog.Table, received []byte) error {
+	if len(table.Rows) == 0 {
+		return fmt.Errorf("%w: header row expected", ErrInvalidValue)
+	}
+
+	columns := make([]string, 0, len(table.Rows[0].Cells))
+	for _, c := range table.Rows[0].Cells {
+		columns = append(columns, c.Value)
+	}
+
+	rcv, err := csvRecords(received, columns)
+	if err != nil {
+		return err
+	}
+
+	exp := make([]map[string]string, 0, len(table.Rows)-1)
+
+	for _, row := range table.Rows[1:] {
+		rec := make(map[string]string, len(columns))
+		for i, c := range row.Cells {
+			rec[columns[i]] = c.Value
+		}
+
+		exp = append(exp, rec)
+	}
+
+	// Records are compared as JSON to support "<ignore-diff>" and variables in cells.
+	expected, err := json.Marshal(exp)
+	if err != nil {
+		return err
+	}
+
+	rb, err := json.Marshal(rcv)
+	if err != nil {
+		return err
+	}
+
+	return augmentBodyErr(l.VS.Assert(ctx, expected, rb, false))
+}
+
+func (l *LocalClient) iShouldHaveResponseWithCSVBody(ctx context.Context, service string, table *godog.Table) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.assertCSV(ctx, table, received)
+	})
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithCSVBody(ctx context.Context, service string, table *godog.Table) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.assertCSV(ctx, table, received)
+	})
+}
@@ -0,0 +1,49 @@
+package httpsteps
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// shellQuote quotes value for POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// curlCommand returns an equivalent curl command of request with secrets redacted.
+func curlCommand(req *http.Request, body []byte) string {
+	u := *req.URL
+	u.RawQuery = redactValues(u.RawQuery)
+
+	parts := []string{"curl", "-X", req.Method, shellQuote(u.String())}
+
+	header := redactHeader(req.Header)
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, v := range header[name] {
+			parts = append(parts, "-H", shellQuote(name+": "+v))
+		}
+	}
+
+	cmd := strings.Join(parts, " ")
+
+	switch {
+	case len(body) == 0:
+	case !utf8.Valid(body):
+		cmd += fmt.Sprintf(" # binary body of %d bytes is omitted", len(body))
+	default:
+		cmd += " --data-raw " + shellQuote(redactBody(req.Header.Get("Content-Type"), body))
+	}
+
+	return cmd
+}
@@ -0,0 +1,57 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bool64/httpmock"
+)
+
+type debugLoggingCtxKey struct{}
+
+func (l *LocalClient) iEnableHTTPDebugLogging(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugLoggingCtxKey{}, true)
+}
+
+// debugLogging is true if scenario has enabled debug logging.
+func debugLogging(ctx context.Context) bool {
+	enabled, _ := ctx.Value(debugLoggingCtxKey{}).(bool) //nolint:errcheck // Disabled by default.
+
+	return enabled
+}
+
+// debugOutput returns writer of debug logging.
+func (l *LocalClient) debugOutput() io.Writer {
+	if l.DebugOutput != nil {
+		return l.DebugOutput
+	}
+
+	return os.Stdout
+}
+
+// logHTTPDetails writes dumps of request and response with secrets redacted.
+func (l *LocalClient) logHTTPDetails(service string, d httpmock.HTTPValue) {
+	req, err := dumpRequest(d.Req, d.ReqBody)
+	if err != nil {
+		return
+	}
+
+	service = serviceName(service)
+	out := fmt.Sprintf(">>> %s request\n%s\n", service, req)
+
+	if d.Resp != nil {
+		if resp, err := dumpResponse(d.Resp, d.RespBody); err == nil {
+			out += fmt.Sprintf("<<< %s response\n%s\n", service, resp)
+		}
+	}
+
+	if d.OtherResp != nil {
+		if resp, err := dumpResponse(d.OtherResp, d.OtherRespBody); err == nil {
+			out += fmt.Sprintf("<<< %s other responses\n%s\n", service, resp)
+		}
+	}
+
+	_, _ = io.WriteString(l.debugOutput(), out) //nolint:errcheck // Best effort logging.
+}
@@ -0,0 +1,51 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// wait holds response for delay of served expectation or until request is canceled,
+// then writes the held response.
+func (w *responseWriter) wait(req *http.Request) {
+	if w.delay <= 0 {
+		return
+	}
+
+	t := time.NewTimer(w.delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-req.Context().Done():
+	}
+
+	status, body := w.status, w.delayed
+	w.delay, w.status, w.delayed = 0, 0, nil
+
+	if status != 0 {
+		w.WriteHeader(status)
+	}
+
+	if len(body) > 0 {
+		_, _ = w.Write(body) //nolint:errcheck // Client may be gone after delay.
+	}
+}
+
+func (e *ExternalServer) serviceRespondsWithDelay(ctx context.Context, service, delay string) (context.Context, error) {
+	d, err := time.ParseDuration(delay)
+	if err != nil || d < 0 {
+		return ctx, fmt.Errorf("%w: response delay %q", ErrInvalidValue, delay)
+	}
+
+	ctx, m, err := e.responding(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.delay = d
+
+	return ctx, nil
+}
@@ -0,0 +1,267 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bool64/httpmock"
+	"github.com/cucumber/godog"
+)
+
+// Deprecations collects endpoints that respond with Deprecation or Sunset header in a test suite,
+// so that use of deprecated API can be tracked from existing features.
+//
+// Same instance can be shared by several clients.
+//
+//	deprecations := &httpsteps.Deprecations{}
+//	local.Deprecations = deprecations
+//
+//	suite := godog.TestSuite{
+//		TestSuiteInitializer: func(s *godog.TestSuiteContext) {
+//			s.AfterSuite(func() { _ = deprecations.Report(os.Stdout) })
+//		},
+//		// ...
+//	}
+type Deprecations struct {
+	mu        sync.Mutex
+	endpoints map[string]*DeprecatedEndpoint
+}
+
+// DeprecatedEndpoint describes usage of a deprecated endpoint.
+type DeprecatedEndpoint struct {
+	Service string
+	Method  string
+	Path    string
+
+	// Deprecation is a time of deprecation, zero value if it is not specified (e.g. "Deprecation: true").
+	Deprecation time.Time
+
+	// Sunset is a time when endpoint becomes unavailable, zero value if Sunset header is missing.
+	Sunset time.Time
+
+	// Uses is a number of received deprecated responses.
+	Uses int
+
+	// Features is a sorted list of feature files that use the endpoint.
+	Features []string
+
+	features map[string]struct{}
+}
+
+// deprecationHeaders parses Deprecation and Sunset headers of response.
+//
+// Deprecation is a structured date (e.g. "@1688169599"), HTTP-date or "true", Sunset is HTTP-date.
+func deprecationHeaders(h http.Header) (deprecated bool, deprecation, sunset time.Time, err error) {
+	if v := h.Get("Sunset"); v != "" {
+		if sunset, err = http.ParseTime(v); err != nil {
+			return false, deprecation, sunset, fmt.Errorf("%w: Sunset header %q: %s", ErrInvalidValue, v, err.Error())
+		}
+	}
+
+	v := h.Get("Deprecation")
+
+	switch {
+	case v == "":
+		return false, deprecation, sunset, nil
+	case v == "true":
+		return true, deprecation, sunset, nil
+	case strings.HasPrefix(v, "@"):
+		sec, err := strconv.ParseInt(v[1:], 10, 64)
+		if err != nil {
+			return false, deprecation, sunset, fmt.Errorf("%w: Deprecation header %q: %s", ErrInvalidValue, v, err.Error())
+		}
+
+		return true, time.Unix(sec, 0).UTC(), sunset, nil
+	}
+
+	if deprecation, err = http.ParseTime(v); err != nil {
+		return false, deprecation, sunset, fmt.Errorf("%w: Deprecation header %q: %s", ErrInvalidValue, v, err.Error())
+	}
+
+	return true, deprecation, sunset, nil
+}
+
+// Endpoints returns deprecated endpoints that were used, endpoints with the earliest sunset go first.
+func (d *Deprecations) Endpoints() []DeprecatedEndpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	res := make([]DeprecatedEndpoint, 0, len(d.endpoints))
+
+	for _, e := range d.endpoints {
+		ep := *e
+		ep.Features = nil
+
+		for f := range e.features {
+			ep.Features = append(ep.Features, f)
+		}
+
+		sort.Strings(ep.Features)
+
+		res = append(res, ep)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Sunset.Equal(res[j].Sunset) {
+			return res[j].Sunset.IsZero() || (!res[i].Sunset.IsZero() && res[i].Sunset.Before(res[j].Sunset))
+		}
+
+		return res[i].key() < res[j].key()
+	})
+
+	return res
+}
+
+// Report writes deprecated endpoints that are still in use with features that use them.
+func (d *Deprecations) Report(w io.Writer) error {
+	endpoints := d.Endpoints()
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(w, "Deprecated endpoints in use:"); err != nil {
+		return err
+	}
+
+	for _, e := range endpoints {
+		sunset := "no sunset"
+		if !e.Sunset.IsZero() {
+			sunset = "sunset " + e.Sunset.UTC().Format(time.RFC3339)
+		}
+
+		if _, err := fmt.Fprintf(w, "%6d %s (%s)\n", e.Uses, e.key(), sunset); err != nil {
+			return err
+		}
+
+		for _, f := range e.Features {
+			if _, err := fmt.Fprintf(w, "       %s\n", f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e DeprecatedEndpoint) key() string {
+	return e.Service + ": " + e.Method + " " + e.Path
+}
+
+// reset discards collected endpoints.
+func (d *Deprecations) reset() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.endpoints = nil
+}
+
+type deprecationsCtxKey struct{}
+
+// beforeScenario puts feature file of scenario in context.
+func (d *Deprecations) beforeScenario(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+	if d == nil {
+		return ctx, nil
+	}
+
+	return context.WithValue(ctx, deprecationsCtxKey{}, sc.Uri), nil
+}
+
+// responseReceived records response if it is marked deprecated, invalid headers are ignored.
+func (d *Deprecations) responseReceived(ctx context.Context, service string, v httpmock.HTTPValue) {
+	if d == nil || v.Req == nil || v.Resp == nil {
+		return
+	}
+
+	deprecated, deprecation, sunset, err := deprecationHeaders(v.Resp.Header)
+	if err != nil || (!deprecated && sunset.IsZero()) {
+		return
+	}
+
+	feature, _ := ctx.Value(deprecationsCtxKey{}).(string) //nolint:errcheck // Empty feature is fine.
+
+	ep := DeprecatedEndpoint{Service: serviceName(service), Method: v.Req.Method, Path: v.Req.URL.Path}
+	key := ep.key()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.endpoints == nil {
+		d.endpoints = make(map[string]*DeprecatedEndpoint)
+	}
+
+	e, ok := d.endpoints[key]
+	if !ok {
+		e = &ep
+		e.features = make(map[string]struct{})
+		d.endpoints[key] = e
+	}
+
+	e.Deprecation = deprecation
+	e.Sunset = sunset
+	e.Uses++
+
+	if feature != "" {
+		e.features[feature] = struct{}{}
+	}
+}
+
+func (l *LocalClient) iShouldHaveResponseMarkedDeprecated(ctx context.Context, service string) (context.Context, error) {
+	return l.iShouldHaveResponseMarkedDeprecatedWithSunset(ctx, service, "")
+}
+
+// iShouldHaveResponseMarkedDeprecatedWithSunset checks Deprecation and Sunset headers, expected sunset is
+// a date (e.g. "2025-01-01") or a full time in RFC 3339 or HTTP-date format.
+func (l *LocalClient) iShouldHaveResponseMarkedDeprecatedWithSunset(ctx context.Context, service, expected string) (context.Context, error) {
+	ctx, err := l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		deprecated, _, sunset, err := deprecationHeaders(d.Resp.Header)
+		if err != nil {
+			return err
+		}
+
+		if !deprecated {
+			return fmt.Errorf("%w: missing Deprecation header", ErrNotDeprecated)
+		}
+
+		if expected == "" {
+			return nil
+		}
+
+		if sunset.IsZero() {
+			return fmt.Errorf("%w: Sunset", ErrMissingHeader)
+		}
+
+		if date, err := time.Parse("2006-01-02", expected); err == nil {
+			if sunset.UTC().Format("2006-01-02") != date.Format("2006-01-02") {
+				return fmt.Errorf("%w: expected %s, received %s", ErrUnexpectedSunset, expected, d.Resp.Header.Get("Sunset"))
+			}
+
+			return nil
+		}
+
+		exp, err := time.Parse(time.RFC3339, expected)
+		if err != nil {
+			if exp, err = http.ParseTime(expected); err != nil {
+				return fmt.Errorf("%w: sunset %q, date, RFC 3339 or HTTP-date expected", ErrInvalidValue, expected)
+			}
+		}
+
+		if !sunset.Equal(exp) {
+			return fmt.Errorf("%w: expected %s, received %s", ErrUnexpectedSunset, expected, d.Resp.Header.Get("Sunset"))
+		}
+
+		return nil
+	})
+
+	return l.assertion(ctx, err)
+}
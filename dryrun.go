@@ -0,0 +1,264 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/godogx/vars"
+	"github.com/swaggest/assertjson/json5"
+)
+
+// dryRunChecks matches expressions of steps that send requests to check responses or check received requests,
+// such steps only validate their arguments in dry-run mode.
+var dryRunChecks = regexp.MustCompile(`^\^(I should have|response of|I poll|I store|I use)|` +
+	`" (should have received|received request|receives at most|receives request within) `)
+
+// dryRunArg is a kind of step argument.
+type dryRunArg int
+
+const (
+	dryRunText dryRunArg = iota
+	dryRunService
+	dryRunStatus
+	dryRunDuration
+	dryRunExternalService
+)
+
+// dryRunArgs returns kinds of capturing groups of step expression, in order of groups.
+//
+// Services are captured with (.*) before the rest of step text in LocalClient steps and
+// with the first group in ExternalServer steps.
+func dryRunArgs(expr string, external bool) []dryRunArg {
+	type group struct {
+		pos, idx int
+	}
+
+	var (
+		res   []dryRunArg
+		open  []group
+		class bool
+	)
+
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\':
+			i++
+		case class:
+			class = c != ']'
+		case c == '[':
+			class = true
+		case c == '(':
+			// Non-capturing groups are not numbered.
+			if strings.HasPrefix(expr[i:], "(?") {
+				open = append(open, group{pos: i, idx: -1})
+
+				continue
+			}
+
+			open = append(open, group{pos: i, idx: len(res)})
+			res = append(res, dryRunText)
+		case c == ')' && len(open) > 0:
+			g := open[len(open)-1]
+			open = open[:len(open)-1]
+
+			if g.idx == -1 {
+				continue
+			}
+
+			before, pattern, after := expr[:g.pos], expr[g.pos:i+1], expr[i+1:]
+
+			switch {
+			case external && g.idx == 0, !external && pattern == "(.*)" && strings.HasPrefix(after, " "):
+				res[g.idx] = dryRunService
+			case strings.HasSuffix(before, `status "`), strings.HasSuffix(before, `status is "`):
+				res[g.idx] = dryRunStatus
+			case strings.HasSuffix(before, `within "`), strings.HasSuffix(before, `every "`),
+				strings.HasSuffix(before, `up to "`), strings.HasSuffix(before, `delay "`),
+				strings.HasSuffix(before, `backoff "`), strings.HasSuffix(before, `unavailable for "`):
+				res[g.idx] = dryRunDuration
+			}
+		}
+	}
+
+	return res
+}
+
+// dryRunValidator checks step arguments without sending requests.
+type dryRunValidator struct {
+	vs       *vars.Steps
+	known    func(ctx context.Context, service string) bool
+	external func(service string) bool
+	expr     string
+	args     []dryRunArg
+	handler  reflect.Value
+}
+
+// dryRunStep returns a handler that validates arguments instead of running the step.
+func dryRunStep(expr string, external bool, handler interface{}, vs *vars.Steps,
+	known func(ctx context.Context, service string) bool,
+) interface{} {
+	v := dryRunValidator{
+		vs:      vs,
+		known:   known,
+		expr:    expr,
+		args:    dryRunArgs(expr, external),
+		handler: reflect.ValueOf(handler),
+	}
+
+	return reflect.MakeFunc(v.handler.Type(), v.call).Interface()
+}
+
+func (v dryRunValidator) call(args []reflect.Value) []reflect.Value {
+	ctx := context.Background()
+	if len(args) > 0 {
+		if c, ok := args[0].Interface().(context.Context); ok && c != nil {
+			ctx = c
+			args = args[1:]
+		}
+	}
+
+	ctx, err := v.validate(ctx, args)
+
+	return stepResults(v.handler.Type(), ctx, err)
+}
+
+// dryRunStep returns a handler that validates arguments of round trip step instead of running it,
+// args are kinds of captured values of step expression.
+func (r *RoundTrip) dryRunStep(expr string, handler interface{}, args []dryRunArg) interface{} {
+	v := dryRunValidator{
+		vs:       r.local.VS,
+		known:    r.local.knownService,
+		external: r.external.knownService,
+		expr:     expr,
+		args:     args,
+		handler:  reflect.ValueOf(handler),
+	}
+
+	return reflect.MakeFunc(v.handler.Type(), v.call).Interface()
+}
+
+// stepResults returns values of step handler results with context and error.
+func stepResults(t reflect.Type, ctx context.Context, err error) []reflect.Value {
+	res := make([]reflect.Value, t.NumOut())
+
+	for i := range res {
+		switch {
+		case t.Out(i) == reflect.TypeOf((*context.Context)(nil)).Elem():
+			res[i] = reflect.ValueOf(&ctx).Elem()
+		case t.Out(i) == reflect.TypeOf((*error)(nil)).Elem():
+			res[i] = reflect.ValueOf(&err).Elem()
+		default:
+			res[i] = reflect.Zero(t.Out(i))
+		}
+	}
+
+	return res
+}
+
+func (v dryRunValidator) validate(ctx context.Context, args []reflect.Value) (context.Context, error) {
+	for i, a := range args {
+		switch val := a.Interface().(type) {
+		case string:
+			// Document is passed as string argument after captured values.
+			if i >= len(v.args) {
+				if err := v.validateDoc(ctx, val); err != nil {
+					return ctx, err
+				}
+
+				continue
+			}
+
+			if err := v.validateArg(ctx, v.args[i], val); err != nil {
+				return ctx, err
+			}
+
+			if _, _, err := v.vs.Replace(ctx, []byte(val)); err != nil {
+				return ctx, fmt.Errorf("failed to replace vars in %q: %w", val, err)
+			}
+		case *godog.DocString:
+			if err := v.validateDoc(ctx, val.Content); err != nil {
+				return ctx, err
+			}
+		}
+	}
+
+	return ctx, nil
+}
+
+func (v dryRunValidator) validateArg(ctx context.Context, kind dryRunArg, val string) error {
+	switch kind {
+	case dryRunService:
+		if service := serviceName(val); !v.known(ctx, service) {
+			return fmt.Errorf("%w: %s", ErrUnknownService, service)
+		}
+	case dryRunExternalService:
+		if !v.external(val) {
+			return fmt.Errorf("%w: %s", ErrUnknownService, val)
+		}
+	case dryRunStatus:
+		if _, err := statusCode(val); err != nil {
+			return err
+		}
+	case dryRunDuration:
+		if _, err := time.ParseDuration(val); err != nil {
+			return fmt.Errorf("%w: duration %q", ErrInvalidValue, val)
+		}
+	case dryRunText:
+	}
+
+	return nil
+}
+
+// validateDoc checks that file exists or that JSON document is valid JSON5.
+func (v dryRunValidator) validateDoc(ctx context.Context, doc string) error {
+	_, rv, err := v.vs.Replace(ctx, []byte(doc))
+	if err != nil {
+		return fmt.Errorf("failed to replace vars: %w", err)
+	}
+
+	rv = bytes.TrimSpace(rv)
+
+	if strings.Contains(v.expr, "from file") {
+		_, err := os.Stat(string(rv))
+
+		return err
+	}
+
+	isJSON := len(rv) > 0 && (rv[0] == '{' || rv[0] == '[')
+	if isJSON && strings.Contains(v.expr, "body") && !strings.Contains(v.expr, "NDJSON") && !json5.Valid(rv) {
+		return fmt.Errorf("%w: JSON5 document %s", ErrInvalidValue, string(rv))
+	}
+
+	return nil
+}
+
+// knownService is true if service is registered or is registered in current scenario.
+func (l *LocalClient) knownService(ctx context.Context, service string) bool {
+	if _, found := scenarioServices(ctx, l)[service]; found {
+		return true
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, found := l.services[service]
+
+	return found
+}
+
+// knownService is true if service is added.
+func (e *ExternalServer) knownService(service string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, found := e.mocks[service]
+
+	return found
+}
@@ -0,0 +1,86 @@
+package httpsteps
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/bool64/httpmock"
+)
+
+// identityEncoding is a content encoding of uncompressed body.
+const identityEncoding = "identity"
+
+// decodeBody decompresses body according to Content-Encoding, encodings are applied in reverse order.
+func decodeBody(contentEncoding string, body []byte) ([]byte, error) {
+	encodings := strings.Split(contentEncoding, ",")
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+
+		var (
+			r   io.Reader
+			err error
+		)
+
+		switch encoding {
+		case "", identityEncoding:
+			continue
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(bytes.NewReader(body))
+		case "br":
+			r = brotli.NewReader(bytes.NewReader(body))
+		case "deflate":
+			// Deflate is expected to be wrapped in zlib format, but raw deflate is also used by some servers.
+			if r, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+				r, err = flate.NewReader(bytes.NewReader(body)), nil
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s response body: %w", encoding, err)
+		}
+
+		if body, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("failed to decode %s response body: %w", encoding, err)
+		}
+	}
+
+	return body, nil
+}
+
+// responseEncoding returns content encoding of received response.
+//
+// Response transparently decompressed by http.Transport is reported as gzip.
+func responseEncoding(resp *http.Response) string {
+	if resp.Uncompressed {
+		return "gzip"
+	}
+
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "" {
+		return encoding
+	}
+
+	return identityEncoding
+}
+
+func (l *LocalClient) iShouldHaveResponseCompressedWith(ctx context.Context, service, encoding string) (context.Context, error) {
+	ctx, err := l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		if received := responseEncoding(d.Resp); !strings.EqualFold(received, encoding) {
+			return fmt.Errorf("%w, expected: %s, received: %s", ErrUnexpectedEncoding, encoding, received)
+		}
+
+		return nil
+	})
+
+	return l.assertion(ctx, err)
+}
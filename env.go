@@ -0,0 +1,67 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/godogx/vars"
+)
+
+// ExportVars returns values of scenario variables to pass them out of vars store, e.g. to a CLI runner step.
+//
+// Mapping defines variable name for an environment variable name, e.g. {"ORDER_ID": "$orderId"}.
+// String values are exported as is, other values are encoded as JSON.
+func ExportVars(ctx context.Context, mapping map[string]string) (map[string]string, error) {
+	v := vars.FromContext(ctx)
+	env := make(map[string]string, len(mapping))
+
+	for name, varName := range mapping {
+		val, found := v[varName]
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUndefinedVar, varName)
+		}
+
+		env[name] = plainValue(val)
+	}
+
+	return env, nil
+}
+
+// Environ returns values of scenario variables in os.Environ format ("NAME=value") sorted by name.
+//
+// Mapping defines variable name for an environment variable name, e.g. {"ORDER_ID": "$orderId"}.
+//
+//	env, err := httpsteps.Environ(ctx, map[string]string{"ORDER_ID": "$orderId"})
+//	cmd.Env = append(os.Environ(), env...)
+func Environ(ctx context.Context, mapping map[string]string) ([]string, error) {
+	env, err := ExportVars(ctx, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(env))
+
+	for name, val := range env {
+		res = append(res, name+"="+val)
+	}
+
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// plainValue formats variable value for use outside of JSON documents.
+func plainValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(j)
+}
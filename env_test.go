@@ -0,0 +1,78 @@
+package httpsteps_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/godogx/httpsteps"
+	"github.com/godogx/vars"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnviron(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"id":"abc-1","total":12.5,"items":[1,2]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	mapping := map[string]string{"ORDER_ID": "$orderId", "TOTAL": "$total", "ITEMS": "$items"}
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+
+			s.Step(`^command output is "([^"]*)"$`, func(ctx context.Context, expected string) error {
+				env, err := httpsteps.Environ(ctx, mapping)
+				if err != nil {
+					return err
+				}
+
+				cmd := exec.Command("sh", "-c", "echo id=$ORDER_ID total=$TOTAL items=$ITEMS")
+				cmd.Env = append(os.Environ(), env...)
+
+				res, err := cmd.Output()
+				if err != nil {
+					return err
+				}
+
+				if received := strings.TrimSpace(string(res)); received != expected {
+					return fmt.Errorf("unexpected output: %s", received)
+				}
+
+				_, err = httpsteps.ExportVars(ctx, map[string]string{"MISSING": "$missing"})
+				if !errors.Is(err, httpsteps.ErrUndefinedVar) {
+					return fmt.Errorf("undefined variable expected, received: %v", err)
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Environ.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+}
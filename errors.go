@@ -0,0 +1,282 @@
+package httpsteps
+
+import (
+	"context"
+
+	"github.com/cucumber/godog"
+)
+
+// SentinelError is a constant error that can be checked with errors.Is.
+type SentinelError string
+
+// Error returns the error message.
+func (e SentinelError) Error() string {
+	return string(e)
+}
+
+// Sentinel errors of step failures.
+const (
+	ErrUnknownStatusCode      = SentinelError("unknown http status")
+	ErrNoMockForService       = SentinelError("no mock for service")
+	ErrUndefinedRequest       = SentinelError("undefined request (missing `receives <METHOD> request` step)")
+	ErrUndefinedResponse      = SentinelError("undefined response (missing `responds with status <STATUS>` step)")
+	ErrUnknownService         = SentinelError("unknown service")
+	ErrUnexpectedExpectations = SentinelError("unexpected existing expectations")
+	ErrInvalidNumberOfColumns = SentinelError("invalid number of columns")
+	ErrUnexpectedBody         = SentinelError("unexpected body")
+	ErrDoesNotContain         = SentinelError("does not contain")
+	ErrContains               = SentinelError("contains")
+	ErrMissingFile            = SentinelError("missing file")
+	ErrInvalidTablePath       = SentinelError("invalid table path")
+	ErrUnknownTypeHint        = SentinelError("unknown type hint")
+	ErrInvalidValue           = SentinelError("invalid value")
+	ErrInvalidGenerator       = SentinelError("invalid generator")
+	ErrUnknownFakeKind        = SentinelError("unknown kind of fake data")
+	ErrConflictingVars        = SentinelError("conflicting values of variables in concurrent responses")
+	ErrExpressionNotSatisfied = SentinelError("expression is not satisfied")
+	ErrExpressionNotBool      = SentinelError("expression result is not bool")
+	ErrUnknownProvider        = SentinelError("unknown body provider")
+	ErrUnknownBodyStream      = SentinelError("unknown body stream")
+	ErrUnsupportedEncoding    = SentinelError("unsupported content encoding")
+	ErrUnexpectedEncoding     = SentinelError("unexpected content encoding")
+	ErrUnknownFixture         = SentinelError("unknown fixture")
+	ErrNotRevalidated         = SentinelError("cached response was not revalidated")
+	ErrBytesDiffer            = SentinelError("bytes differ")
+	ErrUndefinedVar           = SentinelError("undefined variable")
+	ErrUnknownProfile         = SentinelError("unknown response profile")
+	ErrMissingServerTiming    = SentinelError("missing server timing")
+	ErrMissingHeader          = SentinelError("missing header")
+	ErrMissingCookie          = SentinelError("missing cookie")
+	ErrUnexpectedCookie       = SentinelError("unexpected cookie")
+	ErrDurationExceeded       = SentinelError("duration exceeds limit")
+	ErrUnexpectedTrailer      = SentinelError("unexpected trailer")
+	ErrInterleavedRequests    = SentinelError("interleaved requests")
+	ErrMissingRequest         = SentinelError("missing request")
+	ErrUnknownRequest         = SentinelError("unknown request")
+	ErrNoResponse             = SentinelError("no response")
+	ErrUnknownSigningKey      = SentinelError("unknown signing key")
+	ErrMissingPart            = SentinelError("missing part")
+	ErrForkedVars             = SentinelError("forked variables")
+	ErrMalformedService       = SentinelError("malformed service qualifier")
+	ErrInvalidFeature         = SentinelError("invalid feature")
+	ErrUnexpectedStatus       = SentinelError("unexpected status")
+	ErrContentTypeMismatch    = SentinelError("body does not match content type")
+	ErrNotIdempotent          = SentinelError("operation is not idempotent")
+	ErrNoOtherResponses       = SentinelError("other responses expected")
+	ErrNoOutage               = SentinelError("service is not unavailable (missing `is unavailable for` step)")
+	ErrUnexpectedRequests     = SentinelError("unexpected requests")
+	ErrResponsesDiffer        = SentinelError("concurrent responses differ")
+	ErrNotDeprecated          = SentinelError("response is not marked deprecated")
+	ErrUnexpectedSunset       = SentinelError("unexpected sunset")
+	ErrUnmetCondition         = SentinelError("condition is not met")
+	ErrMissingColumn          = SentinelError("missing column")
+	ErrUnknownMessageType     = SentinelError("unknown protobuf message type")
+	ErrUnexpectedLines        = SentinelError("unexpected number of lines")
+	ErrUnknownHandler         = SentinelError("unknown request handler")
+	ErrUnsupportedMapping     = SentinelError("unsupported WireMock mapping")
+	ErrInvalidStepPattern     = SentinelError("invalid step pattern")
+)
+
+// StepError describes a failed step.
+//
+// Use errors.As to access step metadata and errors.Is to check the kind of failure with sentinel errors.
+type StepError struct {
+	// Step is the text of failed step.
+	Step string
+
+	// Service is the name of the last service used by the step, empty if service was not resolved.
+	Service string
+
+	Err error
+}
+
+// Error returns the error message of the failure.
+func (e *StepError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the failure.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// unexpectedBodyError is a failure of body assertion, it matches ErrUnexpectedBody and the cause.
+type unexpectedBodyError struct {
+	err error
+}
+
+func (e unexpectedBodyError) Error() string {
+	return ErrUnexpectedBody.Error() + " " + e.err.Error()
+}
+
+func (e unexpectedBodyError) Is(target error) bool {
+	return target == ErrUnexpectedBody //nolint:errorlint,goerr113 // Sentinel comparison.
+}
+
+func (e unexpectedBodyError) Unwrap() error {
+	return e.err
+}
+
+// stepInfo collects metadata of a running step.
+type stepInfo struct {
+	step    string
+	service string
+}
+
+type stepInfoCtxKey struct{}
+
+// beforeStep puts metadata of a running step in context.
+func beforeStep(ctx context.Context, st *godog.Step) (context.Context, error) {
+	return context.WithValue(ctx, stepInfoCtxKey{}, &stepInfo{step: st.Text}), nil
+}
+
+// usesService records service of a running step.
+func usesService(ctx context.Context, service string) {
+	if info, ok := ctx.Value(stepInfoCtxKey{}).(*stepInfo); ok {
+		info.service = service
+	}
+}
+
+// step adds step definition with failures reported as *StepError.
+func step(s stepContext, expr string, handler interface{}) {
+	s.Step(expr, stepHandler(expr, handler))
+}
+
+// stepFailure returns error with metadata of running step.
+func stepFailure(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	se := &StepError{Err: err}
+
+	if ctx != nil {
+		if info, ok := ctx.Value(stepInfoCtxKey{}).(*stepInfo); ok {
+			se.Step = info.step
+			se.Service = info.service
+		}
+	}
+
+	return se
+}
+
+// stepHandler wraps handler with a closure of the same type that counts step usage and reports failures
+// as *StepError.
+//
+// Closures are typed, so that godog can still resolve handler function, handlers of other types are used as is.
+func stepHandler(expr string, handler interface{}) interface{} {
+	switch h := handler.(type) {
+	case func(context.Context) context.Context:
+		return func(ctx context.Context) context.Context {
+			stepUsed(ctx, expr)
+
+			return h(ctx)
+		}
+	case func(context.Context, string) context.Context:
+		return func(ctx context.Context, a string) context.Context {
+			stepUsed(ctx, expr)
+
+			return h(ctx, a)
+		}
+	case func(context.Context, string) (context.Context, error):
+		return func(ctx context.Context, a string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string) (context.Context, error):
+		return func(ctx context.Context, a, b string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string, string) (context.Context, error):
+		return func(ctx context.Context, a, b, c string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b, c)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string, string, string) (context.Context, error):
+		return func(ctx context.Context, a, b, c, d string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b, c, d)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string, string, string, string) (context.Context, error):
+		return func(ctx context.Context, a, b, c, d, e string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b, c, d, e)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string, string, string, string, string) (context.Context, error):
+		return func(ctx context.Context, a, b, c, d, e, f string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b, c, d, e, f)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, *godog.Table) (context.Context, error):
+		return func(ctx context.Context, a string, t *godog.Table) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, t)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string, string, *godog.Table) (context.Context, error):
+		return func(ctx context.Context, a, b, c string, t *godog.Table) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b, c, t)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, int) (context.Context, error):
+		return func(ctx context.Context, a string, n int) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, n)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, string, int) (context.Context, error):
+		return func(ctx context.Context, a, b string, n int) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, b, n)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, int, string) (context.Context, error):
+		return func(ctx context.Context, a string, n int, b string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, n, b)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, int, int, int) (context.Context, error):
+		return func(ctx context.Context, a string, n, m, k int) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, n, m, k)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, int64) (context.Context, error):
+		return func(ctx context.Context, a string, n int64) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, n)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	case func(context.Context, string, int64, string) (context.Context, error):
+		return func(ctx context.Context, a string, n int64, b string) (context.Context, error) {
+			stepUsed(ctx, expr)
+			ctx, err := h(ctx, a, n, b)
+
+			return ctx, stepFailure(ctx, err)
+		}
+	default:
+		return handler
+	}
+}
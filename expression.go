@@ -0,0 +1,188 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bool64/httpmock"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// expressionCostLimit limits evaluation of response expressions to keep them sandboxed.
+const expressionCostLimit = 1000000
+
+var (
+	expressionEnvOnce sync.Once
+	expressionEnv     *cel.Env
+	expressionEnvErr  error
+)
+
+// responseExpressionEnv returns CEL environment with response variables.
+//
+// Expression has access to parsed JSON body (or a string for non-JSON body), status code and headers.
+func responseExpressionEnv() (*cel.Env, error) {
+	expressionEnvOnce.Do(func() {
+		expressionEnv, expressionEnvErr = cel.NewEnv(
+			cel.Variable("body", cel.DynType),
+			cel.Variable("status", cel.IntType),
+			cel.Variable("headers", cel.MapType(cel.StringType, cel.StringType)),
+			cel.CrossTypeNumericComparisons(true),
+			cel.Function("sum",
+				cel.Overload("sum_list", []*cel.Type{cel.ListType(cel.DynType)}, cel.DoubleType,
+					cel.UnaryBinding(sumList),
+				),
+			),
+		)
+	})
+
+	return expressionEnv, expressionEnvErr
+}
+
+// sumList returns sum of numeric list elements as double.
+func sumList(list ref.Val) ref.Val {
+	l, ok := list.(traits.Lister)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(list)
+	}
+
+	sum := 0.0
+
+	for it := l.Iterator(); it.HasNext() == types.True; {
+		v := it.Next()
+
+		switch n := v.(type) {
+		case types.Double:
+			sum += float64(n)
+		case types.Int:
+			sum += float64(n)
+		case types.Uint:
+			sum += float64(n)
+		default:
+			return types.NewErr("sum: unexpected non-numeric value %v", v)
+		}
+	}
+
+	return types.Double(sum)
+}
+
+func compileExpression(expression string) (cel.Program, error) {
+	env, err := responseExpressionEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression: %w", iss.Err())
+	}
+
+	return env.Program(ast, cel.CostLimit(expressionCostLimit))
+}
+
+func evalExpression(prg cel.Program, expression string, resp *http.Response, body []byte) error {
+	var b interface{}
+
+	if err := json.Unmarshal(body, &b); err != nil {
+		b = string(body)
+	}
+
+	status := 0
+	headers := make(map[string]string)
+
+	if resp != nil {
+		status = resp.StatusCode
+
+		for k, v := range resp.Header {
+			headers[k] = strings.Join(v, ", ")
+		}
+	}
+
+	out, _, err := prg.Eval(map[string]interface{}{
+		"body":    b,
+		"status":  status,
+		"headers": headers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to evaluate expression: %w", err)
+	}
+
+	satisfied, ok := out.Value().(bool)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrExpressionNotBool, out)
+	}
+
+	if !satisfied {
+		return fmt.Errorf("%w: %s", ErrExpressionNotSatisfied, expression)
+	}
+
+	return nil
+}
+
+func (l *LocalClient) iShouldHaveResponseSatisfyingExpression(ctx context.Context, service, expression string) (context.Context, error) {
+	return l.expectExpression(ctx, service, false, expression)
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesSatisfyingExpression(ctx context.Context, service, expression string) (context.Context, error) {
+	return l.expectExpression(ctx, service, true, expression)
+}
+
+func (l *LocalClient) expectExpression(ctx context.Context, service string, other bool, expression string) (context.Context, error) {
+	ctx = l.VS.PrepareContext(ctx)
+
+	ctx, expr, err := l.VS.Replace(ctx, []byte(expression))
+	if err != nil {
+		return ctx, err
+	}
+
+	expression = string(expr)
+
+	prg, err := compileExpression(expression)
+	if err != nil {
+		return ctx, err
+	}
+
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	var failed []byte
+
+	check := func(resp *http.Response) func(ctx context.Context, received []byte) error {
+		return func(_ context.Context, received []byte) error {
+			err := evalExpression(prg, expression, resp, received)
+			if err != nil {
+				failed = received
+			}
+
+			return err
+		}
+	}
+
+	if other {
+		ctx, err = l.checkResponse(ctx, service, func(c *httpmock.Client) error {
+			return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
+				resp := c.Details().OtherResp
+
+				return l.checkBodies(ctx, concurrentBodies(c, resp), received, check(resp))
+			})
+		})
+	} else {
+		ctx, err = l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+			return l.checkBodies(ctx, concurrentBodies(c, d.Resp), d.RespBody, check(d.Resp))
+		})
+	}
+
+	if err != nil && len(failed) > 0 {
+		ctx, err = l.bodyMismatch(ctx, failed, err)
+	}
+
+	return l.assertion(ctx, err)
+}
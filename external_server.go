@@ -2,8 +2,16 @@ package httpsteps
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bool64/httpmock"
 	"github.com/bool64/shared"
@@ -14,50 +22,118 @@ import (
 
 type exp struct {
 	httpmock.Expectation
-	async bool
+	async      bool
+	include    bool
+	jsonPaths  bool
+	form       bool
+	matchJSON  bool
+	delay      time.Duration
+	uriRegex   *regexp.Regexp
+	branches   []responseBranch
+	fault      *fault
+	chunking   *chunking
+	handler    *requestHandler
+	signatures []requestSignature
 }
 
 // NewExternalServer creates an ExternalServer.
 func NewExternalServer() *ExternalServer {
-	es := &ExternalServer{}
+	es := &ExternalServer{Fixtures: &Fixtures{}}
 	es.mocks = make(map[string]*mock, 1)
-	es.lock = resource.NewLock(func(service string) error {
-		m := es.mocks[service]
-		if m == nil {
-			return fmt.Errorf("%w: %s", errNoMockForService, service)
-		}
+	es.lock = resource.NewLock(es.release)
 
-		if m.exp != nil {
-			return fmt.Errorf("%w in %s for %s %s",
-				errUndefinedResponse, service, m.exp.Method, m.exp.RequestURI)
-		}
+	return es
+}
 
-		if err := m.srv.ExpectationsWereMet(); err != nil {
-			return fmt.Errorf("expectations were not met for %s: %w", service, err)
-		}
+// release checks expectations of service when its lock is released after scenario.
+func (e *ExternalServer) release(service string) error {
+	e.mu.RLock()
+	m := e.mocks[service]
+	e.mu.RUnlock()
 
+	if m == nil {
+		return fmt.Errorf("%w: %s", ErrNoMockForService, service)
+	}
+
+	if m.exp != nil {
+		return fmt.Errorf("%w in %s for %s %s",
+			ErrUndefinedResponse, service, m.exp.Method, m.exp.RequestURI)
+	}
+
+	if e.DryRun {
 		return nil
-	})
+	}
 
-	return es
+	if err := m.srv.ExpectationsWereMet(); err != nil {
+		return fmt.Errorf("expectations were not met for %s: %w", service, err)
+	}
+
+	if err := m.checkCriticalSections(); err != nil {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+
+	return nil
 }
 
 // ExternalServer is a collection of step-driven HTTP servers to serve requests of application with mocked data.
 //
 // Please use NewExternalServer() to create an instance.
 type ExternalServer struct {
-	mocks map[string]*mock
-	lock  *resource.Lock
+	mu         sync.RWMutex
+	mocks      map[string]*mock
+	lock       *resource.Lock
+	sharedLock bool
+	registered registry
+
+	// adding is a mock that is being configured with options of Add.
+	addMu  sync.Mutex
+	adding *mock
+
+	caOnce sync.Once
+	ca     *authority
+	caErr  error
+
+	signingKeys map[string]SigningKey
+
+	// handlers are request handlers by service and request URI.
+	handlers map[string]map[string]http.HandlerFunc
+
+	// observers receive requests of mocked services with responses.
+	observers []func(req RequestInfo, resp ResponseInfo)
 
 	// Deprecated: use VS.JSONComparer.Vars to seed initial values if necessary.
 	Vars *shared.Vars
 
 	VS *vars.Steps
-}
 
-type mock struct {
-	exp *exp
-	srv *httpmock.Server
+	// TagHeaders maps scenario tag name to request header name, tag argument is used as header value.
+	// For example, with {"variant": "X-Experiment"} every request expected in scenario tagged @variant(B)
+	// must have X-Experiment: B header.
+	TagHeaders map[string]string
+
+	// Fixtures provides generated request and response bodies, can be shared with LocalClient.
+	Fixtures *Fixtures
+
+	// StepUsage collects statistics of step usage, optional. Can be shared with LocalClient.
+	StepUsage *StepUsage
+
+	// WireMockExport collects expectations defined with steps as WireMock stub mappings, optional.
+	WireMockExport *WireMockExport
+
+	// Output receives debug information, os.Stdout is used by default.
+	Output io.Writer
+
+	// StepPrefix is prepended to expressions of all steps, so that steps do not conflict with steps of other
+	// packages or instances, e.g. with "api " steps look like `api "some-service" responds with status "OK"`.
+	StepPrefix string
+
+	// StepPatterns replaces default expressions of steps, e.g. with translations for feature files in other languages.
+	StepPatterns StepPatterns
+
+	// DryRun disables checks of received requests, so that feature files can be validated quickly
+	// together with LocalClient.DryRun. Steps that check received requests only validate their arguments,
+	// expectations that were not met do not fail the scenario.
+	DryRun bool
 }
 
 // RegisterSteps adds steps to godog scenario context to serve outgoing requests with mocked data.
@@ -71,6 +147,22 @@ type mock struct {
 //	{"key":"value"}
 //	"""
 //
+// Query parameters of request URI are matched in any order.
+//
+// Path of request URI may have wildcard segments, named ("{id}") or positional ("*"). Values of matched segments
+// are available in JSON response body as variables, e.g. "$id" or "$1".
+//
+//	Given "some-service" receives "GET" request "/users/{id}/orders"
+//	And "some-service" responds with status "OK" and body
+//	"""
+//	{"userId":"$id"}
+//	"""
+//
+// Request URI can be matched with a regular expression, e.g. if it has timestamps or random IDs.
+// Values of submatches are available in JSON response body as variables too, e.g. "$month" or "$1".
+//
+//	Given "some-service" receives "GET" request matching "^/reports/(?P<month>\d{4}-\d{2})$"
+//
 // Or request with body.
 //
 //	And "another-service" receives "POST" request "/post-something" with body
@@ -79,6 +171,35 @@ type mock struct {
 //	{"foo":"bar"}
 //	"""
 //
+// Request body may be expected to include JSON, so that additional fields and array elements are allowed.
+//
+//	And "another-service" receives "POST" request "/post-something" with body including JSON
+//	"""
+//	{"foo":"bar"}
+//	"""
+//
+// Or to match JSON, additional fields are allowed, but array elements must be in same order,
+// "<ignore-diff>" matches any value.
+//
+//	And "another-service" receives "POST" request "/post-something" with body, that matches JSON
+//	"""
+//	{"id":"<ignore-diff>","foo":"bar"}
+//	"""
+//
+// Or only some values of request body may be expected by JSON paths, values are JSON with variables replaced,
+// "<ignore-diff>" matches any value. Received values may have additional fields and array elements, same as with including JSON.
+//
+//	And "another-service" receives "POST" request "/post-something" with body, that matches JSON paths
+//	  | $.foo      | "bar"           |
+//	  | $.items[0] | {"id":1}        |
+//	  | $.time     | "<ignore-diff>" |
+//
+// Urlencoded form can be expected regardless of order of parameters, "<ignore-diff>" matches any value.
+//
+//	And "another-service" receives "POST" request "/submit" with urlencoded form data
+//	  | name  | John          |
+//	  | token | <ignore-diff> |
+//
 // Request with body from a file.
 //
 //	And "another-service" receives "POST" request "/post-something" with body from file
@@ -86,10 +207,20 @@ type mock struct {
 //	_testdata/sample.json
 //	"""
 //
+// Request or response body can be generated at run time by a fixture registered in ExternalServer.Fixtures.
+// Fixture is generated once per scenario.
+//
+//	And "another-service" receives "POST" request "/post-something" with body from fixture "signedOrder"
+//
 // Request can expect to have a header.
 //
 //	And "some-service" request includes header "X-Foo: bar"
 //
+// Request can be expected to have a valid signature (hex-encoded HMAC-SHA256 of body) made with a key
+// registered with AddSigningKey.
+//
+//	And "some-service" request is signed with key "webhook-secret"
+//
 // By default, each configured request is expected to be received 1 time. This can be changed to a different number.
 //
 //	And "some-service" request is received 1234 times
@@ -104,10 +235,77 @@ type mock struct {
 //
 //	And "some-service" request is async
 //
+// Requests of a critical section (from the first request with URI or path "/start" to the next one with "/commit")
+// can be expected to not interleave with other requests to the service, this is checked at the end of scenario.
+//
+//	And "some-service" receives no other requests between "/start" and "/commit"
+//
+// Number of received requests can be asserted after the fact, target is a method with request URI, path or pattern,
+// or only request URI.
+//
+//	Then "some-service" should have received 3 requests to "GET /things"
+//
+// Value of a field of the last received request body can be stored in a variable to use it in later steps.
+//
+//	Then "some-service" received request body field "$.chargeId" stored as $chargeID
+//
+// Service can be unavailable for a while (from the first request) and then serve regular expectations.
+// Requests during the window receive status "Service Unavailable", recovery of application can be asserted afterwards.
+//
+//	Given "some-service" is unavailable for "10s" then recovers
+//	Then "some-service" receives request within "1s" after recovery
+//	And "some-service" receives at most 5 requests during outage
+//
+// Large request body can be consumed as a stream, it is counted without keeping in memory, so that
+// expectation can not have a body. Progress of received bytes over time can be asserted.
+//
+//	Given "some-service" receives request body as stream
+//	Then "some-service" receives request body of 1073741824 bytes
+//	And "some-service" receives 1048576 bytes of request body within "1s"
+//
 // Response may have a header.
 //
 //	And "some-service" response includes header "X-Bar: foo"
 //
+// Response can be delayed to simulate a slow service, e.g. to test timeouts and retries of application.
+// Delay is interrupted if request is canceled.
+//
+//	And "some-service" responds with delay "1.5s"
+//
+// Complex dynamic response can be served by a handler registered with OnRequest for the request URI of expectation,
+// request is still accounted as any other expectation.
+//
+//	Given "some-service" receives "POST" request "/quote/{id}"
+//	And "some-service" responds with registered handler
+//
+// Response body can be streamed with chunked transfer encoding, every line of body is flushed as a chunk,
+// optionally with a pause between chunks.
+//
+//	And "some-service" responds with chunked body every "100ms"
+//	"""
+//	{"event":"started"}
+//	{"event":"finished"}
+//	"""
+//
+// Network-level faults can be injected to test resilience of application: body can be truncated
+// (connection is closed after a number of bytes), connection can be reset or response may never come
+// (until request is canceled by client).
+//
+//	And "some-service" responds with truncated body after 100 bytes
+//	And "some-service" responds with status "OK" and body from file
+//	"""
+//	_testdata/large.json
+//	"""
+//
+//	And "some-service" resets the connection
+//
+//	And "some-service" never responds
+//
+// Requests recorded into a Cassette (e.g. with WithPassthrough) can be replayed as expectations,
+// each recorded request is expected once in any order.
+//
+//	Given "some-service" replays cassette "_testdata/some-service.yaml"
+//
 // Response must have a status.
 //
 //	And "some-service" responds with status "OK"
@@ -119,56 +317,248 @@ type mock struct {
 //	{"key":"value"}
 //	"""
 //
+// Expectation can have conditional responses, defined before the regular response. A request that meets
+// a condition (value of header or of JSON path in body) is served with conditional response instead of regular one.
+// Such requests are not counted as received requests of expectation.
+//
+//	Given "some-service" receives "GET" request "/admin"
+//	And "some-service" request is received several times
+//	And "some-service" responds with status "Forbidden" when request header "X-Role" is "guest"
+//	And "some-service" responds with status "OK"
+//
+// JSON string values of response body can reference incoming request, references are evaluated for every request.
+// Available references are method, uri, path, query.<name>, header.<name>, params.<name> (wildcard segments
+// or submatches of request URI) and body.<JSON path>.
+//
+//	And "some-service" responds with status "OK" and body
+//	"""
+//	{"echoId":"{{request.query.id}}","userId":"{{request.params.id}}","name":"{{request.body.$.name}}"}
+//	"""
+//
+// Repeated requests can be served with a sequence of responses, each following "responds with" step
+// adds an expectation of the same request with the next response.
+//
+//	Given "some-service" receives "GET" request "/flaky"
+//	And "some-service" responds with status "Service Unavailable"
+//	And "some-service" responds with status "OK"
+//
 // Response body can also be defined in file.
 //
 //	And "another-service" responds with status "200" and body from file
 //	"""
 //	_testdata/sample.json5
 //	"""
+//
+//	And "another-service" responds with status "200" and body from fixture "signedReceipt"
+//
+// Headers defined by scenario tags with ExternalServer.TagHeaders mapping are expected in all requests of scenario.
+//
+// Pending expectations can be printed for debugging.
+//
+//	And I print pending expectations for "some-service"
 func (e *ExternalServer) RegisterSteps(s *godog.ScenarioContext) {
-	e.lock.Register(s)
-	e.steps(s)
+	if err := e.RegisterStepsWithPatterns(s, nil); err != nil {
+		failScenarios(s, err)
+	}
+}
+
+// RegisterStepsWithPatterns adds steps to godog scenario context with custom expressions
+// that take precedence over StepPatterns.
+//
+// Steps are registered once per scenario context, so RegisterSteps has no effect after RegisterStepsWithPatterns.
+// Steps are not registered and error is returned if StepPatterns or custom expressions are invalid.
+func (e *ExternalServer) RegisterStepsWithPatterns(s *godog.ScenarioContext, custom StepPatterns) error {
+	if err := validatePatterns(e.StepPatterns, custom); err != nil {
+		return err
+	}
+
+	if !e.registered.add(s) {
+		return nil
+	}
+
+	// Hooks of shared lock are registered by its owner.
+	if !e.sharedLock {
+		e.lock.Register(s)
+	}
+
+	s.Before(withTagHeaders(e, func() map[string]string { return e.TagHeaders }))
+	s.Before(e.StepUsage.beforeScenario)
+	s.StepContext().Before(beforeStep)
+	e.steps(withPatterns(s, custom))
+
+	return nil
 }
 
-func (e *ExternalServer) steps(s *godog.ScenarioContext) {
+// StepDefinitions returns expressions and handlers of steps in order of registration.
+func (e *ExternalServer) StepDefinitions() []StepDefinition {
+	var c stepCollector
+
+	e.steps(&c)
+
+	return c
+}
+
+func (e *ExternalServer) steps(s stepContext) {
 	// Init request expectation.
-	s.Step(`^"([^"]*)" receives "([^"]*)" request "([^"]*)"$`,
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)"$`,
 		e.serviceReceivesRequest)
-	s.Step(`^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body$`,
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body$`,
 		e.serviceReceivesRequestWithBody)
-	s.Step(`^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body from file$`,
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body from file$`,
 		e.serviceReceivesRequestWithBodyFromFile)
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body including JSON$`,
+		e.serviceReceivesRequestWithBodyIncludingJSON)
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body, that matches JSON$`,
+		e.serviceReceivesRequestWithBodyThatMatchesJSON)
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body, that matches JSON paths$`,
+		e.serviceReceivesRequestWithBodyThatMatchesJSONPaths)
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with body from fixture "([^"]*)"$`,
+		e.serviceReceivesRequestWithBodyFromFixture)
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request "([^"]*)" with urlencoded form data$`,
+		e.serviceReceivesRequestWithURLEncodedFormData)
+	e.step(s, `^"([^"]*)" receives "([^"]*)" request matching "([^"]*)"$`,
+		e.serviceReceivesRequestMatching)
+
+	e.step(s, `^"([^"]*)" replays cassette "([^"]*)"$`,
+		e.serviceReplaysCassette)
 
 	// Configure request expectation.
-	s.Step(`^"([^"]*)" request includes header "([^"]*): ([^"]*)"$`,
+	e.step(s, `^"([^"]*)" request includes header "([^"]*): ([^"]*)"$`,
 		e.serviceRequestIncludesHeader)
-	s.Step(`^"([^"]*)" request is async$`,
+	e.step(s, `^"([^"]*)" request is signed with key "([^"]*)"$`,
+		e.serviceRequestIsSignedWithKey)
+	e.step(s, `^"([^"]*)" request is async$`,
 		e.serviceRequestIsAsync)
-	s.Step(`^"([^"]*)" request is received several times$`,
+	e.step(s, `^"([^"]*)" request is received several times$`,
 		e.serviceReceivesRequestMultipleTimes)
-	s.Step(`^"([^"]*)" request is received (\d+) times$`,
+	e.step(s, `^"([^"]*)" request is received (\d+) times$`,
 		e.serviceReceivesRequestNTimes)
 
+	e.step(s, `^"([^"]*)" is unavailable for "([^"]*)" then recovers$`,
+		e.serviceIsUnavailableThenRecovers)
+	e.step(s, `^"([^"]*)" receives request within "([^"]*)" after recovery$`,
+		e.serviceReceivesRequestWithinAfterRecovery)
+	e.step(s, `^"([^"]*)" receives at most (\d+) requests during outage$`,
+		e.serviceReceivesAtMostRequestsDuringOutage)
+
+	e.step(s, `^"([^"]*)" receives no other requests between "([^"]*)" and "([^"]*)"$`,
+		e.serviceReceivesNoOtherRequestsBetween)
+	e.step(s, `^"([^"]*)" should have received (\d+) requests? to "([^"]*)"$`,
+		e.serviceShouldHaveReceivedRequests)
+	e.step(s, `^"([^"]*)" received request body field "([^"]*)" stored as (\S+)$`,
+		e.serviceReceivedRequestBodyFieldStoredAs)
+
+	e.step(s, `^"([^"]*)" receives request body as stream$`,
+		e.serviceReceivesRequestBodyAsStream)
+	e.step(s, `^"([^"]*)" receives request body of (\d+) bytes$`,
+		e.serviceReceivesRequestBodyOfBytes)
+	e.step(s, `^"([^"]*)" receives (\d+) bytes of request body within "([^"]*)"$`,
+		e.serviceReceivesBytesOfRequestBodyWithin)
+
 	// Configure response.
-	s.Step(`^"([^"]*)" response includes header "([^"]*): ([^"]*)"$`,
+	e.step(s, `^"([^"]*)" response includes header "([^"]*): ([^"]*)"$`,
 		e.serviceResponseIncludesHeader)
+	e.step(s, `^"([^"]*)" responds with delay "([^"]*)"$`,
+		e.serviceRespondsWithDelay)
+
+	e.step(s, `^"([^"]*)" responds with status "([^"]*)" when request (header|body field) "([^"]*)" is "([^"]*)"$`,
+		func(ctx context.Context, service, statusOrCode, subject, name, value string) (context.Context, error) {
+			return e.serviceRespondsWithStatusWhen(ctx, service, statusOrCode, subject, name, value, nil)
+		})
+	e.step(s, `^"([^"]*)" responds with status "([^"]*)" and body when request (header|body field) "([^"]*)" is "([^"]*)"$`,
+		e.serviceRespondsWithStatusAndBodyWhen)
+
+	e.step(s, `^"([^"]*)" responds with truncated body after (\d+) bytes$`,
+		e.serviceRespondsWithTruncatedBody)
 
 	// Finalize request expectation.
-	s.Step(`^"([^"]*)" responds with status "([^"]*)"$`,
+	e.step(s, `^"([^"]*)" responds with status "([^"]*)"$`,
 		func(ctx context.Context, service, statusOrCode string) (context.Context, error) {
 			return e.serviceRespondsWithStatusAndPreparedBody(ctx, service, statusOrCode, nil)
 		})
-	s.Step(`^"([^"]*)" responds with status "([^"]*)" and body$`,
+	e.step(s, `^"([^"]*)" responds with status "([^"]*)" and body$`,
 		e.serviceRespondsWithStatusAndBody)
-	s.Step(`^"([^"]*)" responds with status "([^"]*)" and body from file$`,
+	e.step(s, `^"([^"]*)" responds with status "([^"]*)" and body from file$`,
 		e.serviceRespondsWithStatusAndBodyFromFile)
+	e.step(s, `^"([^"]*)" responds with status "([^"]*)" and body from fixture "([^"]*)"$`,
+		e.serviceRespondsWithStatusAndBodyFromFixture)
+	e.step(s, `^"([^"]*)" responds with chunked body(?: every "([^"]*)")?$`,
+		e.serviceRespondsWithChunkedBody)
+	e.step(s, `^"([^"]*)" responds with registered handler$`,
+		e.serviceRespondsWithRegisteredHandler)
+	e.step(s, `^"([^"]*)" resets the connection$`,
+		e.serviceResetsTheConnection)
+	e.step(s, `^"([^"]*)" never responds$`,
+		e.serviceNeverResponds)
+
+	// Debug.
+	e.step(s, `^I print pending expectations for "([^"]*)"$`,
+		e.iPrintPendingExpectationsFor)
 }
 
 // GetMock exposes mock of external service for configuration.
 func (e *ExternalServer) GetMock(service string) *httpmock.Server {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	return e.mocks[service].srv
 }
 
+// Reset removes expectations of all services, step registrations and statistics of StepUsage.
+//
+// It can be used between runs of test suites that share ExternalServer instance.
+func (e *ExternalServer) Reset() {
+	e.mu.RLock()
+	for _, m := range e.mocks {
+		m.reset()
+	}
+	e.mu.RUnlock()
+
+	e.registered.reset()
+	e.StepUsage.reset()
+}
+
+// PendingExpectations returns expectations of a service that are not met yet.
+//
+// Only expectations configured with steps are available.
+func (e *ExternalServer) PendingExpectations(service string) ([]PendingExpectation, error) {
+	e.mu.RLock()
+	m, found := e.mocks[service]
+	e.mu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	return m.pending(), nil
+}
+
+func (e *ExternalServer) iPrintPendingExpectationsFor(ctx context.Context, service string) (context.Context, error) {
+	pending, err := e.PendingExpectations(service)
+	if err != nil {
+		return ctx, err
+	}
+
+	out := e.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	res := fmt.Sprintf("pending expectations for %q: %d\n", service, len(pending))
+
+	for i, p := range pending {
+		res += fmt.Sprintf("%d. %s\n", i+1, p.String())
+	}
+
+	_, err = io.WriteString(out, res)
+
+	return godog.Attach(ctx, godog.Attachment{
+		Body:      []byte(res),
+		FileName:  "pending expectations",
+		MediaType: "text/plain",
+	}), err
+}
+
 func (e *ExternalServer) pending(ctx context.Context, service string) (context.Context, *mock, error) {
 	ctx, m, err := e.mock(ctx, service)
 	if err != nil {
@@ -176,7 +566,7 @@ func (e *ExternalServer) pending(ctx context.Context, service string) (context.C
 	}
 
 	if m.exp == nil {
-		return ctx, nil, fmt.Errorf("%w: %q", errUndefinedRequest, service)
+		return ctx, nil, fmt.Errorf("%w: %q", ErrUndefinedRequest, service)
 	}
 
 	return ctx, m, nil
@@ -190,9 +580,18 @@ func (e *ExternalServer) mock(ctx context.Context, service string) (context.Cont
 		service = Default
 	}
 
+	usesService(ctx, service)
+
+	e.mu.RLock()
 	c, found := e.mocks[service]
+	e.mu.RUnlock()
+
 	if !found {
-		return ctx, nil, fmt.Errorf("%w: %s", errUnknownService, service)
+		return ctx, nil, fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	if c.err != nil {
+		return ctx, nil, fmt.Errorf("%s: %w", service, c.err)
 	}
 
 	acquired, err := e.lock.Acquire(ctx, service)
@@ -202,24 +601,85 @@ func (e *ExternalServer) mock(ctx context.Context, service string) (context.Cont
 
 	// Reset client after acquiring lock.
 	if acquired {
-		c.exp = nil
-		c.srv.ResetExpectations()
+		c.reset()
 	}
 
 	return ctx, c, nil
 }
 
 // Add starts a mocked server for a named service and returns url.
+//
+// Use WithTLS option to serve HTTPS, WithStubs option to serve default responses to unmatched requests,
+// WithPassthrough option to forward unmatched requests to a real upstream. These options configure services
+// of the same ExternalServer only.
 func (e *ExternalServer) Add(service string, options ...func(mock *httpmock.Server)) string {
-	m, url := httpmock.NewServer()
+	m, _ := httpmock.NewServer()
+	mk := &mock{srv: m, service: service, observers: e.roundTripObservers}
+
+	// Options of ExternalServer configure the mock that is being added.
+	e.addMu.Lock()
+	e.adding = mk
 
 	for _, option := range options {
 		option(m)
 	}
 
-	e.mocks[service] = &mock{srv: m}
+	e.adding = nil
+	e.addMu.Unlock()
+
+	mk.vars = m.JSONComparer.Vars
+	onRequest := m.OnRequest
+
+	m.OnRequest = func(rw http.ResponseWriter, req *http.Request) {
+		if onRequest != nil {
+			onRequest(rw, req)
+		}
+
+		mk.onRequest(req)
+
+		if w, ok := rw.(*responseWriter); ok {
+			mk.takeResponse(w)
+		}
+	}
+
+	// Mock is served by own server to inject faults and to stream bodies of responses.
+	if mk.tls {
+		mk.server = e.serveTLS(mk)
+	} else {
+		mk.server = httptest.NewServer(mk)
+	}
+
+	m.Close()
+
+	e.mu.Lock()
+	// Server of replaced mock is not used anymore.
+	if prev, ok := e.mocks[service]; ok {
+		prev.server.Close()
+	}
+
+	e.mocks[service] = mk
+	e.mu.Unlock()
+
+	return mk.server.URL
+}
+
+// configure applies option of ExternalServer to the mock that is being added, option is ignored out of Add.
+func (e *ExternalServer) configure(f func(m *mock)) {
+	if e.adding != nil {
+		f(e.adding)
+	}
+}
+
+// Close stops servers of all services.
+//
+// It should be called when ExternalServer is not used anymore, e.g. after test suite.
+func (e *ExternalServer) Close() {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 
-	return url
+	for _, m := range e.mocks {
+		m.server.Close()
+	}
 }
 
 func (e *ExternalServer) serviceReceivesRequestWithPreparedBody(ctx context.Context, service, method, requestURI string, body []byte) (context.Context, error) {
@@ -262,7 +722,90 @@ func (e *ExternalServer) serviceReceivesRequestWithBody(ctx context.Context, ser
 	return e.serviceReceivesRequestWithPreparedBody(ctx, service, method, requestURI, body)
 }
 
+func (e *ExternalServer) serviceReceivesRequestWithBodyThatMatchesJSON(ctx context.Context, service, method, requestURI string, bodyDoc string) (context.Context, error) {
+	ctx, err := e.serviceReceivesRequestWithBody(ctx, service, method, requestURI, bodyDoc)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.matchJSON = true
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivesRequestWithBodyIncludingJSON(ctx context.Context, service, method, requestURI string, bodyDoc string) (context.Context, error) {
+	ctx, err := e.serviceReceivesRequestWithBody(ctx, service, method, requestURI, bodyDoc)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.include = true
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivesRequestWithBodyThatMatchesJSONPaths(
+	ctx context.Context,
+	service, method, requestURI string,
+	jsonPaths *godog.Table,
+) (context.Context, error) {
+	expected := make(map[string]json.RawMessage, len(jsonPaths.Rows))
+
+	for _, row := range jsonPaths.Rows {
+		if len(row.Cells) != 2 {
+			return ctx, fmt.Errorf("%w: 2 expected, %d received", ErrInvalidNumberOfColumns, len(row.Cells))
+		}
+
+		path := row.Cells[0].Value
+
+		ctx, val, err := e.VS.Replace(ctx, []byte(row.Cells[1].Value))
+		if err != nil {
+			return ctx, fmt.Errorf("failed to prepare expected value at jsonpath %s: %w", path, err)
+		}
+
+		if !json.Valid(val) {
+			return ctx, fmt.Errorf("%w at jsonpath %s: %s", ErrInvalidValue, path, val)
+		}
+
+		expected[path] = val
+	}
+
+	body, err := json.Marshal(expected)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, err = e.serviceReceivesRequestWithPreparedBody(ctx, service, method, requestURI, body)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.jsonPaths = true
+
+	return ctx, nil
+}
+
 func (e *ExternalServer) serviceReceivesRequestWithBodyFromFile(ctx context.Context, service, method, requestURI string, filePath string) (context.Context, error) {
+	ctx, filePath, err := resolveFilePath(ctx, e.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
 	ctx, body, err := e.VS.ReplaceFile(ctx, filePath)
 	if err != nil {
 		return ctx, err
@@ -271,6 +814,15 @@ func (e *ExternalServer) serviceReceivesRequestWithBodyFromFile(ctx context.Cont
 	return e.serviceReceivesRequestWithPreparedBody(ctx, service, method, requestURI, body)
 }
 
+func (e *ExternalServer) serviceReceivesRequestWithBodyFromFixture(ctx context.Context, service, method, requestURI, name string) (context.Context, error) {
+	ctx, body, err := e.Fixtures.body(ctx, name)
+	if err != nil {
+		return ctx, err
+	}
+
+	return e.serviceReceivesRequestWithPreparedBody(ctx, service, method, requestURI, body)
+}
+
 func (e *ExternalServer) serviceReceivesRequest(ctx context.Context, service, method, requestURI string) (context.Context, error) {
 	ctx, m, err := e.mock(ctx, service)
 	if err != nil {
@@ -278,13 +830,21 @@ func (e *ExternalServer) serviceReceivesRequest(ctx context.Context, service, me
 	}
 
 	if m.exp != nil {
-		return ctx, fmt.Errorf("%w for %q: %+v", errUnexpectedExpectations, service, *m.exp)
+		return ctx, fmt.Errorf("%w for %q: %+v", ErrUnexpectedExpectations, service, *m.exp)
 	}
 
 	m.exp = &exp{}
 	m.exp.Method = method
 	m.exp.RequestURI = requestURI
 
+	for k, v := range tagHeaders(ctx, e) {
+		if m.exp.RequestHeader == nil {
+			m.exp.RequestHeader = make(map[string]string)
+		}
+
+		m.exp.RequestHeader[k] = v
+	}
+
 	return ctx, nil
 }
 
@@ -321,19 +881,31 @@ func (e *ExternalServer) serviceReceivesRequestMultipleTimes(ctx context.Context
 	return ctx, nil
 }
 
+func (e *ExternalServer) serviceReceivesNoOtherRequestsBetween(ctx context.Context, service, start, end string) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.expectCriticalSection(start, end)
+
+	return ctx, nil
+}
+
 func (e *ExternalServer) serviceRespondsWithStatusAndPreparedBody(ctx context.Context, service, statusOrCode string, body []byte) (context.Context, error) {
 	code, err := statusCode(statusOrCode)
 	if err != nil {
 		return ctx, err
 	}
 
-	ctx, m, err := e.pending(ctx, service)
+	ctx, m, err := e.responding(ctx, service)
 	if err != nil {
 		return ctx, err
 	}
 
 	pending := *m.exp
 	m.exp = nil
+	m.last = &pending
 
 	pending.Status = code
 	pending.ResponseBody = body
@@ -342,17 +914,18 @@ func (e *ExternalServer) serviceRespondsWithStatusAndPreparedBody(ctx context.Co
 		pending.ResponseHeader = map[string]string{}
 	}
 
-	if pending.async {
-		m.srv.ExpectAsync(pending.Expectation)
-	} else {
-		m.srv.Expect(pending.Expectation)
+	m.expectBranches(&pending)
+	m.expect(pending)
+
+	if e.WireMockExport != nil {
+		e.WireMockExport.collect(m.service, pending)
 	}
 
 	return ctx, nil
 }
 
 func (e *ExternalServer) serviceResponseIncludesHeader(ctx context.Context, service, header, value string) (context.Context, error) {
-	ctx, m, err := e.pending(ctx, service)
+	ctx, m, err := e.responding(ctx, service)
 	if err != nil {
 		return ctx, err
 	}
@@ -367,7 +940,7 @@ func (e *ExternalServer) serviceResponseIncludesHeader(ctx context.Context, serv
 }
 
 func (e *ExternalServer) serviceRespondsWithStatusAndBody(ctx context.Context, service, statusOrCode string, bodyDoc string) (context.Context, error) {
-	ctx, body, err := e.VS.Replace(ctx, []byte(bodyDoc))
+	ctx, body, err := replaceVars(ctx, e.VS, []byte(bodyDoc))
 	if err != nil {
 		return ctx, err
 	}
@@ -376,7 +949,21 @@ func (e *ExternalServer) serviceRespondsWithStatusAndBody(ctx context.Context, s
 }
 
 func (e *ExternalServer) serviceRespondsWithStatusAndBodyFromFile(ctx context.Context, service, statusOrCode string, filePath string) (context.Context, error) {
-	ctx, body, err := e.VS.ReplaceFile(ctx, filePath)
+	ctx, filePath, err := resolveFilePath(ctx, e.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, body, err := replaceFileVars(ctx, e.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	return e.serviceRespondsWithStatusAndPreparedBody(ctx, service, statusOrCode, body)
+}
+
+func (e *ExternalServer) serviceRespondsWithStatusAndBodyFromFixture(ctx context.Context, service, statusOrCode, name string) (context.Context, error) {
+	ctx, body, err := e.Fixtures.body(ctx, name)
 	if err != nil {
 		return ctx, err
 	}
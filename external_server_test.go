@@ -1,13 +1,25 @@
 package httpsteps_test
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/bool64/httpmock"
 	"github.com/cucumber/godog"
 	"github.com/godogx/httpsteps"
 	"github.com/stretchr/testify/assert"
@@ -120,3 +132,647 @@ func callServices(t *testing.T, someServiceURL, anotherServiceURL string) func()
 		assertjson.Equal(t, []byte(`"foo"`), respBody)
 	}
 }
+
+func TestExternalServer_PendingExpectations(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	someServiceURL := es.Add("some-service")
+
+	out := bytes.NewBuffer(nil)
+	es.Output = out
+
+	_, err := es.PendingExpectations("unknown-service")
+	require.EqualError(t, err, "unknown service: unknown-service")
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" "([^"]*)"$`, func(method, uri string) error {
+				req, err := http.NewRequest(method, someServiceURL+uri, bytes.NewReader([]byte(`{"foo":"bar"}`)))
+				if err != nil {
+					return err
+				}
+
+				resp, err := http.DefaultTransport.RoundTrip(req)
+				if err != nil {
+					return err
+				}
+
+				return resp.Body.Close()
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/PendingExpectations.feature"},
+		},
+	}
+
+	require.Equal(t, 0, suite.Run())
+	assert.Equal(t, `pending expectations for "some-service": 2
+1. POST /second, body: {"foo":"bar"}, responds with status 201
+2. GET /async, async, responds with status 200
+pending expectations for "some-service": 0
+`, out.String())
+}
+
+func TestExternalServer_Reset(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	es.Add("some-service")
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			// Repeated registration is ignored.
+			es.RegisterSteps(s)
+			es.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/Leftover.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+
+	pending, err := es.PendingExpectations("some-service")
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	es.Reset()
+
+	pending, err = es.PendingExpectations("some-service")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	assert.NoError(t, es.GetMock("some-service").ExpectationsWereMet())
+}
+
+func TestExternalServer_Close(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	url := es.Add("some-service")
+
+	resp, err := http.Get(url) //nolint:noctx
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	es.Close()
+
+	_, err = http.Get(url) //nolint:noctx
+	assert.Error(t, err)
+}
+
+func TestExternalServer_responseDelay_concurrent(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	backend := es.Add("backend")
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+			s.Step(`^fast request is served during slow request$`, func() error {
+				slow := make(chan error, 1)
+
+				go func() {
+					resp, err := http.Get(backend + "/slow") //nolint:noctx
+					if err == nil {
+						err = resp.Body.Close()
+					}
+
+					slow <- err
+				}()
+
+				time.Sleep(100 * time.Millisecond)
+
+				start := time.Now()
+
+				resp, err := http.Get(backend + "/fast") //nolint:noctx
+				if err != nil {
+					return err
+				}
+
+				if err := resp.Body.Close(); err != nil {
+					return err
+				}
+
+				if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+					return fmt.Errorf("fast request is blocked for %s", elapsed)
+				}
+
+				return <-slow
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/ResponseDelayConcurrent.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+}
+
+func TestExternalServer_Add_withTLS(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	url := es.Add("secure-service", es.WithTLS())
+
+	assert.True(t, strings.HasPrefix(url, "https://"), url)
+
+	es.GetMock("secure-service").Expect(httpmock.Expectation{
+		Method:       http.MethodGet,
+		RequestURI:   "/secure",
+		Status:       http.StatusOK,
+		ResponseBody: []byte(`{"secure":true}`),
+	})
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(es.CACertificate()))
+
+	client := http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+	}}
+
+	req, err := http.NewRequest(http.MethodGet, url+"/secure", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, `{"secure":true}`, string(body))
+	assert.NoError(t, es.GetMock("secure-service").ExpectationsWereMet())
+
+	// Certificate is not trusted without generated CA.
+	_, err = http.Get(url + "/secure") //nolint:noctx
+	assert.Error(t, err)
+
+	assert.True(t, strings.HasPrefix(es.Add("plain-service"), "http://"))
+}
+
+func TestExternalServer_faults(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	someServiceURL := es.Add("some-service")
+
+	client := http.Client{Timeout: 200 * time.Millisecond}
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" and receive error$`, func(uri string) error {
+				resp, err := client.Get(someServiceURL + uri) //nolint:noctx
+				if err == nil {
+					_ = resp.Body.Close()
+
+					return errors.New("error expected")
+				}
+
+				return nil
+			})
+
+			s.Step(`^I call "([^"]*)" and receive "([^"]*)" of (\d+) bytes before error$`, func(uri, expected string, length int64) error {
+				resp, err := client.Get(someServiceURL + uri) //nolint:noctx
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				body, err := io.ReadAll(resp.Body)
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					return fmt.Errorf("unexpected EOF expected, %v received", err)
+				}
+
+				// Length of full body is announced.
+				if resp.ContentLength != length {
+					return fmt.Errorf("unexpected content length: %d", resp.ContentLength)
+				}
+
+				if string(body) != expected {
+					return fmt.Errorf("unexpected body: %s", string(body))
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/Faults.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+}
+
+func TestExternalServer_chunkedBody(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	someServiceURL := es.Add("some-service")
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" and receive (\d+) lines with pauses of "([^"]*)"$`, func(uri string, n int, pause string) error {
+				d, err := time.ParseDuration(pause)
+				if err != nil {
+					return err
+				}
+
+				resp, err := http.Get(someServiceURL + uri) //nolint:noctx
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				if len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+					return fmt.Errorf("chunked transfer encoding expected, %v received", resp.TransferEncoding)
+				}
+
+				r := bufio.NewReader(resp.Body)
+				prev := time.Now()
+
+				for i := 0; i < n; i++ {
+					if _, err := r.ReadString('\n'); err != nil && !errors.Is(err, io.EOF) {
+						return err
+					}
+
+					if now := time.Now(); i > 0 && now.Sub(prev) < d/2 {
+						return fmt.Errorf("line %d received too early: %s", i+1, now.Sub(prev))
+					}
+
+					prev = time.Now()
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/ChunkedBody.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+}
+
+func TestExternalServer_OnRequest(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	someServiceURL := es.Add("some-service")
+
+	es.OnRequest("some-service", "/quote/{id}", func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		n, err := strconv.Atoi(string(body))
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(rw, "quote %s: %d", req.URL.Path, 2*n)
+	})
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" "([^"]*)" with body "([^"]*)" and receive "([^"]*)"$`, func(method, uri, body, expected string) error {
+				req, err := http.NewRequest(method, someServiceURL+uri, strings.NewReader(body))
+				if err != nil {
+					return err
+				}
+
+				resp, err := http.DefaultTransport.RoundTrip(req)
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				received, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+
+				if string(received) != expected {
+					return fmt.Errorf("unexpected response: %d %s", resp.StatusCode, string(received))
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format:   "pretty",
+			Output:   out,
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RequestHandler.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "unknown request handler for some-service /unknown")
+}
+
+func TestExternalServer_Add_withPassthrough(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		rw.Header().Set("X-Upstream", "yes")
+		_, _ = fmt.Fprintf(rw, "upstream %s %s: %s", req.Method, req.RequestURI, string(body))
+	}))
+	defer upstream.Close()
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	var (
+		mu       sync.Mutex
+		recorded []httpmock.Expectation
+	)
+
+	es := httpsteps.NewExternalServer()
+	someServiceURL := es.Add("some-service", es.WithPassthrough(u, func(exchange httpmock.Expectation) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		recorded = append(recorded, exchange)
+	}))
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" "([^"]*)" with body "([^"]*)" and receive "(.*)"$`, func(method, uri, body, expected string) error {
+				req, err := http.NewRequest(method, someServiceURL+uri, strings.NewReader(body))
+				if err != nil {
+					return err
+				}
+
+				resp, err := http.DefaultTransport.RoundTrip(req)
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				received, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+
+				if string(received) != expected {
+					return fmt.Errorf("unexpected response: %d %s", resp.StatusCode, string(received))
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/Passthrough.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+
+	require.Len(t, recorded, 1)
+	assert.Equal(t, http.MethodPost, recorded[0].Method)
+	assert.Equal(t, "/real?x=1", recorded[0].RequestURI)
+	assert.Equal(t, "hello", string(recorded[0].RequestBody))
+	assert.Equal(t, http.StatusOK, recorded[0].Status)
+	assert.Equal(t, "upstream POST /real?x=1: hello", string(recorded[0].ResponseBody))
+	assert.Equal(t, "yes", recorded[0].ResponseHeader["X-Upstream"])
+}
+
+func TestExternalServer_Add_withStubs(t *testing.T) {
+	stubs, err := httpsteps.LoadWireMockStubs("_testdata/wiremock/mappings")
+	require.NoError(t, err)
+	require.Len(t, stubs, 4)
+
+	es := httpsteps.NewExternalServer()
+	someServiceURL := es.Add("some-service", es.WithStubs(stubs...))
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" "([^"]*)" with body "(.*)" and receive "(.*)"$`, func(method, uri, body, expected string) error {
+				req, err := http.NewRequest(method, someServiceURL+uri, strings.NewReader(body))
+				if err != nil {
+					return err
+				}
+
+				if body != "" {
+					req.Header.Set("Content-Type", "application/json")
+				}
+
+				resp, err := http.DefaultTransport.RoundTrip(req)
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				received, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+
+				if r := strconv.Itoa(resp.StatusCode) + " " + string(received); r != expected {
+					return fmt.Errorf("unexpected response: %s", r)
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/WireMockStubs.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+
+	f := filepath.Join(t.TempDir(), "mapping.json")
+	require.NoError(t, os.WriteFile(f, []byte(`{"request":{"url":"/","headers":{"X":{"contains":"a"}}}}`), 0o600))
+
+	_, err = httpsteps.LoadWireMockStubs(f)
+	assert.ErrorIs(t, err, httpsteps.ErrUnsupportedMapping)
+}
+
+func TestExternalServer_WireMockExport(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	es.WireMockExport = &httpsteps.WireMockExport{}
+	someServiceURL := es.Add("some-service")
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" "([^"]*)" with body "(.*)" and receive "(.*)"$`, func(method, uri, body, expected string) error {
+				req, err := http.NewRequest(method, someServiceURL+uri, strings.NewReader(body))
+				if err != nil {
+					return err
+				}
+
+				resp, err := http.DefaultTransport.RoundTrip(req)
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				received, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+
+				if string(received) != expected {
+					return fmt.Errorf("unexpected response: %d %s", resp.StatusCode, string(received))
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/WireMockExport.feature", "_testdata/WireMockExport.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+	assert.Equal(t, []string{"some-service"}, es.WireMockExport.Services())
+
+	mappings, err := es.WireMockExport.Mappings("some-service")
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "mappings": [
+		{
+		  "priority": 1,
+		  "request": {"method": "GET", "urlPattern": "/users/[^/]+", "headers": {"X-Role": {"equalTo": "guest"}}},
+		  "response": {"status": 403}
+		},
+		{
+		  "request": {"method": "GET", "urlPattern": "/users/[^/]+"},
+		  "response": {"status": 200, "jsonBody": {"id": "$id"}}
+		},
+		{
+		  "request": {
+			"method": "POST", "url": "/users",
+			"bodyPatterns": [{"equalToJson": {"name": "John"}, "ignoreExtraElements": true}]
+		  },
+		  "response": {"status": 201, "body": "created"}
+		}
+	  ]
+	}`), mappings)
+
+	dir := t.TempDir()
+	require.NoError(t, es.WireMockExport.WriteFiles(dir))
+
+	written, err := os.ReadFile(filepath.Join(dir, "some-service.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(mappings), string(written))
+}
+
+func TestExternalServer_cassette(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusAccepted)
+		_, _ = fmt.Fprintf(rw, "upstream %s %s", req.Method, req.RequestURI)
+	}))
+	defer upstream.Close()
+
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.yaml")
+	cassette := httpsteps.NewCassette(cassettePath)
+
+	es := httpsteps.NewExternalServer()
+	recordingURL := es.Add("recording-service", es.WithPassthrough(u, cassette.Record))
+
+	resp, err := http.Get(recordingURL + "/foo?bar=1")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, cassette.Err())
+
+	loaded, err := httpsteps.LoadCassette(cassettePath)
+	require.NoError(t, err)
+
+	recorded := loaded.Expectations()
+	require.Len(t, recorded, 1)
+	assert.Equal(t, http.MethodGet, recorded[0].Method)
+	assert.Equal(t, "/foo?bar=1", recorded[0].RequestURI)
+	assert.Equal(t, http.StatusAccepted, recorded[0].Status)
+	assert.Equal(t, "upstream GET /foo?bar=1", string(recorded[0].ResponseBody))
+
+	someServiceURL := es.Add("some-service")
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			es.RegisterSteps(s)
+
+			s.Step(`^I call "([^"]*)" "([^"]*)" with body "(.*)" and receive "(.*)"$`, func(method, uri, body, expected string) error {
+				req, err := http.NewRequest(method, someServiceURL+uri, strings.NewReader(body))
+				if err != nil {
+					return err
+				}
+
+				resp, err := http.DefaultTransport.RoundTrip(req)
+				if err != nil {
+					return err
+				}
+
+				defer resp.Body.Close()
+
+				received, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+
+				if string(received) != expected {
+					return fmt.Errorf("unexpected response: %d %s", resp.StatusCode, string(received))
+				}
+
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Output: io.Discard,
+			Strict: true,
+			Paths:  []string{"_testdata/Cassette.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+}
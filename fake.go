@@ -0,0 +1,85 @@
+package httpsteps
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+var (
+	fakeFirstNames = []string{
+		"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "William", "Elizabeth",
+		"David", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen",
+		"Olivia", "Liam", "Emma", "Noah", "Amelia", "Oliver", "Sophia", "Elijah", "Isabella", "Lucas",
+	}
+	fakeLastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+		"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin",
+		"Lee", "Perez", "Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson",
+	}
+	fakeCities = []string{
+		"Amsterdam", "Berlin", "Boston", "Chicago", "Dublin", "Lisbon", "London", "Madrid", "Oslo", "Paris",
+		"Prague", "Rome", "Seattle", "Sydney", "Tokyo", "Toronto", "Vienna", "Warsaw", "Zurich", "Denver",
+	}
+	fakeCompanySuffixes = []string{"Inc", "LLC", "Group", "Ltd", "Labs", "Systems", "Partners", "Holdings"}
+	fakeStreets         = []string{"Main St", "Oak Ave", "Maple Dr", "Park Rd", "Pine St", "Cedar Ln", "Elm St", "Lake Blvd"}
+	fakeDomains         = []string{"example.com", "example.org", "example.net"}
+)
+
+// fakers are generators of realistic fake data by kind.
+var fakers = map[string]func() string{
+	"first name": func() string {
+		return pick(fakeFirstNames)
+	},
+	"last name": func() string {
+		return pick(fakeLastNames)
+	},
+	"full name": func() string {
+		return pick(fakeFirstNames) + " " + pick(fakeLastNames)
+	},
+	"username": func() string {
+		return strings.ToLower(pick(fakeFirstNames)+"."+pick(fakeLastNames)) + fmt.Sprintf("%d", randIntn(100000))
+	},
+	"email": func() string {
+		return strings.ToLower(pick(fakeFirstNames)+"."+pick(fakeLastNames)) +
+			fmt.Sprintf("%d@", randIntn(100000)) + pick(fakeDomains)
+	},
+	"phone number": func() string {
+		return fmt.Sprintf("+1-555-%03d-%04d", randIntn(1000), randIntn(10000))
+	},
+	"city": func() string {
+		return pick(fakeCities)
+	},
+	"street address": func() string {
+		return fmt.Sprintf("%d %s", 1+randIntn(9999), pick(fakeStreets))
+	},
+	"company name": func() string {
+		return pick(fakeLastNames) + " " + pick(fakeCompanySuffixes)
+	},
+}
+
+func randIntn(n int) int {
+	return rand.Intn(n) //nolint:gosec // Test data does not need secure random.
+}
+
+func pick(values []string) string {
+	return values[randIntn(len(values))]
+}
+
+// fakeValue generates a fake value of a kind, e.g. "email" or "full name".
+func fakeValue(kind string) (string, error) {
+	f, ok := fakers[strings.ToLower(strings.TrimSpace(kind))]
+	if !ok {
+		kinds := make([]string, 0, len(fakers))
+		for k := range fakers {
+			kinds = append(kinds, k)
+		}
+
+		sort.Strings(kinds)
+
+		return "", fmt.Errorf("%w %q, available: %s", ErrUnknownFakeKind, kind, strings.Join(kinds, ", "))
+	}
+
+	return f(), nil
+}
@@ -0,0 +1,257 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// faultKind is a kind of network-level fault of response.
+type faultKind int
+
+const (
+	faultReset faultKind = iota + 1
+	faultTruncate
+	faultHang
+)
+
+// fault describes a network-level fault of response.
+type fault struct {
+	kind faultKind
+
+	// bytes is a number of body bytes sent before truncation.
+	bytes int
+}
+
+// String describes fault.
+func (f fault) String() string {
+	switch f.kind {
+	case faultReset:
+		return "connection reset"
+	case faultTruncate:
+		return "body truncated after " + strconv.Itoa(f.bytes) + " bytes"
+	case faultHang:
+		return "no response"
+	}
+
+	return ""
+}
+
+// responseWriter intercepts response of httpmock.Server to inject fault, to stream body or to serve with handler
+// of served expectation.
+type responseWriter struct {
+	http.ResponseWriter
+
+	fault    *fault
+	chunking *chunking
+	handler  *requestHandler
+	release  <-chan struct{}
+	status   int
+	body     []byte
+
+	// delay holds response, it is written after delay without lock of httpmock.Server.
+	delay   time.Duration
+	delayed []byte
+}
+
+// WriteHeader postpones status of delayed, truncated or chunked response until body is known.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.fault == nil && w.chunking == nil && w.handler == nil && w.delay == 0 {
+		w.ResponseWriter.WriteHeader(status)
+
+		return
+	}
+
+	w.status = status
+}
+
+// Write discards response body, sends a part of it or keeps it to be streamed.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.delay > 0 {
+		w.delayed = append(w.delayed, p...)
+
+		return len(p), nil
+	}
+
+	if w.handler != nil {
+		return len(p), nil
+	}
+
+	// Body of chunked or truncated response is kept until it is complete.
+	if w.chunking != nil && w.fault == nil || w.fault != nil && w.fault.kind == faultTruncate {
+		w.body = append(w.body, p...)
+
+		return len(p), nil
+	}
+
+	if w.fault == nil {
+		return w.ResponseWriter.Write(p)
+	}
+
+	return len(p), nil
+}
+
+// truncate sends a part of kept body with full length announced, so that client can detect truncation.
+func (w *responseWriter) truncate() {
+	if w.body == nil {
+		return
+	}
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(w.body)))
+	w.ResponseWriter.WriteHeader(w.status)
+
+	n := w.fault.bytes
+	if n > len(w.body) {
+		n = len(w.body)
+	}
+
+	_, _ = w.ResponseWriter.Write(w.body[:n]) //nolint:errcheck // Connection is aborted anyway.
+}
+
+// inject breaks connection after response is served by httpmock.Server.
+func (w *responseWriter) inject(req *http.Request) {
+	if w.fault == nil {
+		return
+	}
+
+	switch w.fault.kind {
+	case faultTruncate:
+		w.truncate()
+
+		if f, ok := w.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+	case faultReset:
+		if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+			if conn, _, err := h.Hijack(); err == nil {
+				resetConn(conn)
+
+				return
+			}
+		}
+	case faultHang:
+		select {
+		case <-req.Context().Done():
+		case <-w.release:
+		}
+	}
+
+	// Connection is closed without finishing response.
+	panic(http.ErrAbortHandler)
+}
+
+// resetConn closes connection with TCP RST.
+func resetConn(conn net.Conn) {
+	c := conn
+	if tc, ok := c.(*tls.Conn); ok {
+		c = tc.NetConn()
+	}
+
+	if tc, ok := c.(*net.TCPConn); ok {
+		_ = tc.SetLinger(0) //nolint:errcheck // Best effort.
+	}
+
+	_ = conn.Close() //nolint:errcheck // Best effort.
+}
+
+// ServeHTTP serves request with httpmock.Server and finishes response of served expectation.
+func (m *mock) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if observers := m.observers(); len(observers) > 0 {
+		ow := &observedWriter{ResponseWriter: rw}
+		info := RequestInfo{Service: m.service, Method: req.Method, URL: req.RequestURI, Header: req.Header.Clone()}
+		info.Body, _ = io.ReadAll(req.Body) //nolint:errcheck // Failure to read is a failure to match request.
+		req.Body = io.NopCloser(bytes.NewReader(info.Body))
+		start := time.Now()
+
+		// Observers are also called when response is aborted to inject a fault.
+		defer func() {
+			observe(observers, info, ResponseInfo{
+				Status: ow.status, Header: ow.Header().Clone(), Body: ow.body.Bytes(), Duration: time.Since(start),
+			})
+		}()
+
+		rw = ow
+	}
+
+	w := &responseWriter{ResponseWriter: rw}
+
+	m.srv.ServeHTTP(w, req)
+
+	// Response is finished without lock of httpmock.Server, so that other requests are not blocked.
+	w.wait(req)
+	w.handle(req)
+	w.stream(req)
+	w.inject(req)
+}
+
+// takeResponse sets up writer with fault, chunking and handler of current request,
+// writer of request that never gets response also receives a channel that is closed when mock is reset.
+func (m *mock) takeResponse(w *responseWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.fault, w.chunking, w.delay = m.fault, m.chunking, m.delay
+	m.fault, m.chunking, m.delay = nil, nil, 0
+
+	// Handler receives original request URI and body.
+	if m.handler != nil && len(m.received) > 0 {
+		h := *m.handler
+		r := m.received[len(m.received)-1]
+		h.requestURI, h.body = r.requestURI, r.body
+		w.handler = &h
+	}
+
+	m.handler = nil
+
+	if w.fault == nil || w.fault.kind != faultHang {
+		return
+	}
+
+	if m.release == nil {
+		m.release = make(chan struct{})
+	}
+
+	w.release = m.release
+}
+
+func (e *ExternalServer) serviceRespondsWithFault(ctx context.Context, service string, f fault) (context.Context, error) {
+	ctx, m, err := e.responding(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.fault = &f
+
+	// Connection reset and hang have no response.
+	if f.kind == faultTruncate {
+		return ctx, nil
+	}
+
+	return e.serviceRespondsWithStatusAndPreparedBody(ctx, service, strconv.Itoa(http.StatusOK), nil)
+}
+
+func (e *ExternalServer) serviceResetsTheConnection(ctx context.Context, service string) (context.Context, error) {
+	return e.serviceRespondsWithFault(ctx, service, fault{kind: faultReset})
+}
+
+func (e *ExternalServer) serviceNeverResponds(ctx context.Context, service string) (context.Context, error) {
+	return e.serviceRespondsWithFault(ctx, service, fault{kind: faultHang})
+}
+
+func (e *ExternalServer) serviceRespondsWithTruncatedBody(ctx context.Context, service string, n int) (context.Context, error) {
+	if n < 0 {
+		return ctx, fmt.Errorf("%w: number of bytes %d", ErrInvalidValue, n)
+	}
+
+	return e.serviceRespondsWithFault(ctx, service, fault{kind: faultTruncate, bytes: n})
+}
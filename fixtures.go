@@ -0,0 +1,141 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/godogx/vars"
+)
+
+// FixtureFunc produces request or response body at run time, e.g. a signed or timestamped payload.
+type FixtureFunc func(ctx context.Context) ([]byte, error)
+
+// Fixtures is a registry of named body generators.
+//
+// Generated body is cached per scenario, so that every step of a scenario that refers to
+// a fixture receives the same body. Same instance can be shared by LocalClient and ExternalServer.
+type Fixtures struct {
+	mu       sync.RWMutex
+	fixtures map[string]FixtureFunc
+}
+
+// Add registers named fixture function.
+func (f *Fixtures) Add(name string, fixture FixtureFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fixtures == nil {
+		f.fixtures = make(map[string]FixtureFunc)
+	}
+
+	f.fixtures[name] = fixture
+}
+
+// AddCommand registers named fixture produced by external command, body is read from command stdout.
+//
+// Variables (e.g. $id) in command arguments are replaced with their values.
+func (f *Fixtures) AddCommand(name string, command string, args ...string) {
+	f.Add(name, CommandFixture(command, args...))
+}
+
+// CommandFixture creates a fixture function that runs external command and uses its stdout as body.
+//
+// Variables (e.g. $id) in command arguments are replaced with their values.
+func CommandFixture(command string, args ...string) FixtureFunc {
+	return func(ctx context.Context) ([]byte, error) {
+		a := make([]string, 0, len(args))
+
+		for _, arg := range args {
+			a = append(a, replaceVarsInArg(ctx, arg))
+		}
+
+		var stderr bytes.Buffer
+
+		cmd := exec.CommandContext(ctx, command, a...) //nolint:gosec // Command is defined by test suite.
+		cmd.Stderr = &stderr
+
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("command %s failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+		}
+
+		return out, nil
+	}
+}
+
+// replaceVarsInArg replaces variables with their plain values, longer names are replaced first.
+func replaceVarsInArg(ctx context.Context, arg string) string {
+	v := vars.FromContext(ctx)
+	names := make([]string, 0, len(v))
+
+	for k := range v {
+		names = append(names, k)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+
+	for _, k := range names {
+		arg = strings.ReplaceAll(arg, k, plainValue(v[k]))
+	}
+
+	return arg
+}
+
+type fixturesCtxKey struct{}
+
+// fixtureCache keeps bodies generated in a scenario.
+type fixtureCache struct {
+	mu     sync.Mutex
+	bodies map[fixtureCacheKey][]byte
+}
+
+type fixtureCacheKey struct {
+	fixtures *Fixtures
+	name     string
+}
+
+// body returns fixture body, it is generated on first use in a scenario.
+func (f *Fixtures) body(ctx context.Context, name string) (context.Context, []byte, error) {
+	if f == nil {
+		return ctx, nil, fmt.Errorf("%w: %s", ErrUnknownFixture, name)
+	}
+
+	f.mu.RLock()
+	fixture, found := f.fixtures[name]
+	f.mu.RUnlock()
+
+	if !found {
+		return ctx, nil, fmt.Errorf("%w: %s", ErrUnknownFixture, name)
+	}
+
+	c, ok := ctx.Value(fixturesCtxKey{}).(*fixtureCache)
+	if !ok {
+		c = &fixtureCache{bodies: make(map[fixtureCacheKey][]byte)}
+		ctx = context.WithValue(ctx, fixturesCtxKey{}, c)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := fixtureCacheKey{fixtures: f, name: name}
+
+	if body, ok := c.bodies[k]; ok {
+		return ctx, body, nil
+	}
+
+	body, err := fixture(ctx)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("fixture %s failed: %w", name, err)
+	}
+
+	c.bodies[k] = body
+
+	return ctx, body, nil
+}
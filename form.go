@@ -0,0 +1,224 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/cucumber/messages/go/v21"
+)
+
+// fileRefPrefix marks form values that are loaded from files, e.g. "@file:_testdata/avatar.png".
+const fileRefPrefix = "@file:"
+
+// fileRef returns path of a file reference in form value.
+func fileRef(value string) (string, bool) {
+	if !strings.HasPrefix(value, fileRefPrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(value, fileRefPrefix), true
+}
+
+// embedFiles returns a copy of table with file references replaced by file contents.
+func (l *LocalClient) embedFiles(ctx context.Context, data *godog.Table) (context.Context, *godog.Table, error) {
+	res := &godog.Table{Rows: make([]*messages.PickleTableRow, 0, len(data.Rows))}
+
+	for _, r := range data.Rows {
+		if len(r.Cells) != 2 {
+			return ctx, nil, fmt.Errorf("%w, 2 expected, %d received", ErrInvalidNumberOfColumns, len(r.Cells))
+		}
+
+		value := r.Cells[1].Value
+
+		if path, ok := fileRef(value); ok {
+			var (
+				filePath string
+				err      error
+			)
+
+			ctx, filePath, err = resolveFilePath(ctx, l.VS, path)
+			if err != nil {
+				return ctx, nil, err
+			}
+
+			content, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+			if err != nil {
+				return ctx, nil, err
+			}
+
+			value = string(content)
+		}
+
+		res.Rows = append(res.Rows, &messages.PickleTableRow{Cells: []*messages.PickleTableCell{
+			r.Cells[0], {Value: value},
+		}})
+	}
+
+	return ctx, res, nil
+}
+
+// multipartForm creates multipart body from table of fields, values with file references become file parts.
+func (l *LocalClient) multipartForm(ctx context.Context, data *godog.Table) (context.Context, []byte, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	ctx = l.VS.PrepareContext(ctx)
+
+	for _, r := range data.Rows {
+		if len(r.Cells) != 2 {
+			return ctx, nil, "", fmt.Errorf("%w, 2 expected, %d received", ErrInvalidNumberOfColumns, len(r.Cells))
+		}
+
+		name, value := r.Cells[0].Value, r.Cells[1].Value
+
+		path, ok := fileRef(value)
+		if !ok {
+			_, rv, err := replaceVars(ctx, l.VS, []byte(value))
+			if err != nil {
+				return ctx, nil, "", fmt.Errorf("failed to replace vars in form field %s: %w", name, err)
+			}
+
+			if err := writer.WriteField(name, string(rv)); err != nil {
+				return ctx, nil, "", err
+			}
+
+			continue
+		}
+
+		var (
+			filePath string
+			err      error
+		)
+
+		ctx, filePath, err = resolveFilePath(ctx, l.VS, path)
+		if err != nil {
+			return ctx, nil, "", err
+		}
+
+		// File contents are sent as is, without replacing variables, to keep binary files intact.
+		content, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+		if err != nil {
+			return ctx, nil, "", err
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(filePath))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(name), quoteEscaper.Replace(filepath.Base(filePath))))
+		h.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(h)
+		if err != nil {
+			return ctx, nil, "", err
+		}
+
+		if _, err := part.Write(content); err != nil {
+			return ctx, nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return ctx, nil, "", err
+	}
+
+	return ctx, body.Bytes(), writer.FormDataContentType(), nil
+}
+
+func (l *LocalClient) iRequestWithMultipartFormData(ctx context.Context, service string, data *godog.Table) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, body, contentType, err := l.multipartForm(ctx, data)
+	if err == nil {
+		c.WithBody(body)
+		c.WithContentType(contentType)
+	}
+
+	return ctx, err
+}
+
+// formProjection returns received urlencoded form encoded in canonical order (sorted by key), values of wildcard
+// expected values are replaced with expected ones.
+//
+// Boolean result is true if received form has same parameters and values as expected one.
+func formProjection(expected, received []byte, wildcard func(s string) bool) ([]byte, bool) {
+	exp, err := url.ParseQuery(string(expected))
+	if err != nil {
+		return nil, false
+	}
+
+	rcv, err := url.ParseQuery(string(received))
+	if err != nil {
+		return nil, false
+	}
+
+	for k, ev := range exp {
+		rv := rcv[k]
+		if len(rv) != len(ev) {
+			return nil, false
+		}
+
+		for i, v := range ev {
+			if wildcard(v) {
+				rv[i] = v
+			}
+		}
+	}
+
+	projected := rcv.Encode()
+
+	return []byte(projected), projected == exp.Encode()
+}
+
+func (e *ExternalServer) serviceReceivesRequestWithURLEncodedFormData(
+	ctx context.Context,
+	service, method, requestURI string,
+	data *godog.Table,
+) (context.Context, error) {
+	form := make(url.Values, len(data.Rows))
+
+	for _, row := range data.Rows {
+		if len(row.Cells) != 2 {
+			return ctx, fmt.Errorf("%w: 2 expected, %d received", ErrInvalidNumberOfColumns, len(row.Cells))
+		}
+
+		name := row.Cells[0].Value
+
+		ctx, val, err := e.VS.Replace(ctx, []byte(row.Cells[1].Value))
+		if err != nil {
+			return ctx, fmt.Errorf("failed to replace vars in form data parameter %s: %w", name, err)
+		}
+
+		form[name] = append(form[name], string(val))
+	}
+
+	// Form is encoded with sorted keys, received form is encoded in same way before matching.
+	ctx, err := e.serviceReceivesRequestWithPreparedBody(ctx, service, method, requestURI, []byte(form.Encode()))
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.form = true
+
+	return ctx, nil
+}
@@ -0,0 +1,193 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godogx/vars"
+	"github.com/gofrs/uuid"
+)
+
+var (
+	// generatorExpr matches generator expressions, e.g. `$uuid()` or `$randInt(1,100)`.
+	generatorExpr       = regexp.MustCompile(`\$(uuid|randInt|now)\(([^)]*)\)`)
+	quotedGeneratorExpr = regexp.MustCompile(`"` + generatorExpr.String() + `"`)
+)
+
+// generatorArity limits number of arguments of generators, the last argument takes the rest of text,
+// so that time layout of `$now(Jan 2, 2006)` is not split.
+var generatorArity = map[string]int{
+	"randInt": 2,
+	"now":     1,
+}
+
+// generators are built-in value generator functions.
+var generators = map[string]func(args []string) (interface{}, error){
+	"uuid": func(_ []string) (interface{}, error) {
+		u, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+
+		return u.String(), nil
+	},
+	"randInt": func(args []string) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%w: 2 arguments expected, %d received", ErrInvalidGenerator, len(args))
+		}
+
+		minVal, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidGenerator, err.Error())
+		}
+
+		maxVal, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || maxVal < minVal {
+			return nil, fmt.Errorf("%w: invalid range %s..%s", ErrInvalidGenerator, args[0], args[1])
+		}
+
+		return randRange(minVal, maxVal), nil
+	},
+	"now": func(args []string) (interface{}, error) {
+		layout := ""
+		if len(args) > 0 {
+			layout = args[0]
+		}
+
+		now := time.Now()
+
+		switch layout {
+		case "", "RFC3339":
+			return now.Format(time.RFC3339), nil
+		case "RFC3339Nano":
+			return now.Format(time.RFC3339Nano), nil
+		case "RFC1123":
+			return now.Format(time.RFC1123), nil
+		case "Unix":
+			return now.Unix(), nil
+		case "UnixMilli":
+			return now.UnixNano() / int64(time.Millisecond), nil
+		default:
+			return now.Format(layout), nil
+		}
+	},
+}
+
+// randRange returns random number in inclusive range, width of range is computed through uint64
+// to avoid overflow, e.g. for `$randInt(0,9223372036854775807)`.
+//
+//nolint:gosec // Test data does not need secure random.
+func randRange(minVal, maxVal int64) int64 {
+	width := uint64(maxVal) - uint64(minVal)
+
+	if width < math.MaxInt64 {
+		return minVal + rand.Int63n(int64(width)+1)
+	}
+
+	// Wide range accepts at least half of random values.
+	for {
+		if n := rand.Uint64(); n <= width {
+			return int64(uint64(minVal) + n)
+		}
+	}
+}
+
+// expandGenerators replaces generator expressions with generated values.
+//
+// Expression enclosed in double quotes is replaced with JSON value (e.g. a number for `"$randInt(1,9)"`),
+// other occurrences are replaced with raw value.
+func expandGenerators(body []byte) ([]byte, error) {
+	if !bytes.Contains(body, []byte("$")) {
+		return body, nil
+	}
+
+	var err error
+
+	generate := func(expr []byte) ([]byte, bool) {
+		m := generatorExpr.FindSubmatch(expr)
+
+		var args []string
+
+		n, ok := generatorArity[string(m[1])]
+		if !ok {
+			n = -1
+		}
+
+		if a := strings.TrimSpace(string(m[2])); a != "" {
+			for _, arg := range strings.SplitN(a, ",", n) {
+				args = append(args, strings.TrimSpace(arg))
+			}
+		}
+
+		v, gErr := generators[string(m[1])](args)
+		if gErr != nil {
+			err = fmt.Errorf("failed to generate value for %s: %w", string(expr), gErr)
+
+			return expr, false
+		}
+
+		j, gErr := json.Marshal(v)
+		if gErr != nil {
+			err = gErr
+
+			return expr, false
+		}
+
+		return j, true
+	}
+
+	body = quotedGeneratorExpr.ReplaceAllFunc(body, func(expr []byte) []byte {
+		if j, ok := generate(expr[1 : len(expr)-1]); ok {
+			return j
+		}
+
+		return expr
+	})
+
+	body = generatorExpr.ReplaceAllFunc(body, func(expr []byte) []byte {
+		j, ok := generate(expr)
+		if !ok {
+			return expr
+		}
+
+		if j[0] == '"' {
+			var s string
+			if err := json.Unmarshal(j, &s); err == nil {
+				return []byte(s)
+			}
+		}
+
+		return j
+	})
+
+	return body, err
+}
+
+// replaceVars expands generator expressions and replaces vars.
+func replaceVars(ctx context.Context, vs *vars.Steps, body []byte) (context.Context, []byte, error) {
+	body, err := expandGenerators(body)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return vs.Replace(ctx, body)
+}
+
+// replaceFileVars expands generator expressions and replaces vars in file contents.
+func replaceFileVars(ctx context.Context, vs *vars.Steps, filePath string) (context.Context, []byte, error) {
+	body, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return replaceVars(ctx, vs, body)
+}
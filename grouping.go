@@ -0,0 +1,83 @@
+package httpsteps
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bool64/httpmock"
+)
+
+// IdempotencyGrouping returns a key of concurrent response, responses with the same key belong to the same group.
+//
+// In idempotent mode responses are grouped into exactly ONE response of a kind and OTHER responses of another kind,
+// or all responses are of the same kind.
+type IdempotencyGrouping func(resp *http.Response, body []byte) string
+
+// WithIdempotencyGrouping sets grouping of concurrent responses for body expectations, status code is used by default.
+//
+// For example, endpoint may respond with status OK to all concurrent requests, but only one of them
+// has a "created": true field.
+func (l *LocalClient) WithIdempotencyGrouping(group IdempotencyGrouping) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.grouping = group
+}
+
+// GroupByJSONPath groups concurrent responses by a value at JSON path, e.g. "$.created".
+//
+// Responses without a value or with invalid JSON body belong to the same group.
+func GroupByJSONPath(path string) IdempotencyGrouping {
+	return func(_ *http.Response, body []byte) string {
+		val, err := jsonPathValue(body, path)
+		if err != nil {
+			return ""
+		}
+
+		return plainValue(val)
+	}
+}
+
+func (l *LocalClient) idempotencyGrouping() IdempotencyGrouping {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.grouping
+}
+
+// groupedBodies returns bodies of ONE and OTHER concurrent responses according to grouping.
+func groupedBodies(c *httpmock.Client, group IdempotencyGrouping) (one, other [][]byte, err error) {
+	last := recorderOf(c).last()
+
+	groups := make(map[string][][]byte)
+
+	for _, e := range last {
+		key := group(e.resp, e.respBody)
+		groups[key] = append(groups[key], e.respBody)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	switch {
+	case len(groups) == 1:
+		return groups[keys[0]], nil, nil
+	case len(groups) == 2 && len(groups[keys[0]]) == 1 && len(groups[keys[1]]) > 1:
+		return groups[keys[0]], groups[keys[1]], nil
+	case len(groups) == 2 && len(groups[keys[1]]) == 1 && len(groups[keys[0]]) > 1:
+		return groups[keys[1]], groups[keys[0]], nil
+	}
+
+	counts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		counts = append(counts, fmt.Sprintf("%q x%d", k, len(groups[k])))
+	}
+
+	return nil, nil, fmt.Errorf("%w: groups of responses: %s", ErrNotIdempotent, strings.Join(counts, ", "))
+}
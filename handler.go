@@ -0,0 +1,69 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// requestHandler serves response of expectation with Go code.
+type requestHandler struct {
+	handler http.HandlerFunc
+
+	// requestURI and body of received request, they are restored before handler is called.
+	requestURI string
+	body       []byte
+}
+
+// OnRequest registers handler of requests with request URI (or pattern) of service.
+//
+// Handler is only used in scenarios that expect such request with a step
+// `"service" responds with registered handler`, request is accounted as any other expectation.
+func (e *ExternalServer) OnRequest(service, requestURI string, handler http.HandlerFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.handlers == nil {
+		e.handlers = make(map[string]map[string]http.HandlerFunc)
+	}
+
+	if e.handlers[service] == nil {
+		e.handlers[service] = make(map[string]http.HandlerFunc)
+	}
+
+	e.handlers[service][requestURI] = handler
+}
+
+// handle serves response with handler of expectation.
+func (w *responseWriter) handle(req *http.Request) {
+	if w.handler == nil {
+		return
+	}
+
+	req.RequestURI = w.handler.requestURI
+	req.Body = io.NopCloser(bytes.NewReader(w.handler.body))
+
+	w.handler.handler(w.ResponseWriter, req)
+}
+
+func (e *ExternalServer) serviceRespondsWithRegisteredHandler(ctx context.Context, service string) (context.Context, error) {
+	ctx, m, err := e.responding(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	e.mu.RLock()
+	h := e.handlers[m.service][m.exp.RequestURI]
+	e.mu.RUnlock()
+
+	if h == nil {
+		return ctx, fmt.Errorf("%w for %s %s", ErrUnknownHandler, m.service, m.exp.RequestURI)
+	}
+
+	m.exp.handler = &requestHandler{handler: h}
+
+	return e.serviceRespondsWithStatusAndPreparedBody(ctx, service, strconv.Itoa(http.StatusOK), nil)
+}
@@ -0,0 +1,67 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// HealthCheck defines expectations of a healthy response of health or readiness endpoint.
+type HealthCheck struct {
+	ResponseProfile
+
+	// MaxLatency limits duration of request, zero value means no limit.
+	MaxLatency time.Duration
+}
+
+// DefaultHealthCheck expects status OK, JSON body with "status" of "ok", "pass", "up" or "healthy"
+// (in lower or upper case) and latency under 1 second.
+func DefaultHealthCheck() HealthCheck {
+	return HealthCheck{
+		ResponseProfile: ResponseProfile{
+			Status: "OK",
+			Schema: json.RawMessage(`{
+  "type": "object",
+  "required": ["status"],
+  "properties": {
+    "status": {"enum": ["ok", "OK", "pass", "PASS", "up", "UP", "healthy", "HEALTHY"]}
+  }
+}`),
+		},
+		MaxLatency: time.Second,
+	}
+}
+
+func (l *LocalClient) iShouldHaveHealthyResponse(ctx context.Context, service string) (context.Context, error) {
+	hc := DefaultHealthCheck()
+	if l.HealthCheck != nil {
+		hc = *l.HealthCheck
+	}
+
+	ctx, err := l.expectProfile(ctx, service, hc.ResponseProfile)
+	if err != nil {
+		return ctx, fmt.Errorf("healthy response: %w", err)
+	}
+
+	if hc.MaxLatency <= 0 {
+		return ctx, nil
+	}
+
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	e, found := recorderOf(c).latest()
+	if !found {
+		return ctx, fmt.Errorf("%w: healthy response was not received", ErrNoResponse)
+	}
+
+	if e.duration >= hc.MaxLatency {
+		return l.assertion(ctx, fmt.Errorf("healthy response: %w: latency is %s, expected under %s",
+			ErrDurationExceeded, e.duration, hc.MaxLatency))
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,108 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bool64/httpmock"
+)
+
+// volatileHeaders are not compared between concurrent responses, they differ by nature.
+var volatileHeaders = map[string]bool{
+	"Date": true,
+}
+
+// checkIdenticalResponses compares status, headers and body of every concurrent response with the first one.
+func checkIdenticalResponses(last []exchange) error {
+	first := last[0]
+
+	for i, e := range last[1:] {
+		diff := ""
+
+		switch {
+		case e.resp.StatusCode != first.resp.StatusCode:
+			diff = fmt.Sprintf("status %d, expected %d", e.resp.StatusCode, first.resp.StatusCode)
+		case !bytes.Equal(e.respBody, first.respBody):
+			diff = fmt.Sprintf("body %q, expected %q", sniffPrefix(e.respBody), sniffPrefix(first.respBody))
+		default:
+			diff = headersDiff(first.resp.Header, e.resp.Header)
+		}
+
+		if diff != "" {
+			return fmt.Errorf("%w: response %d of %d has %s", ErrResponsesDiffer, i+2, len(last), diff)
+		}
+	}
+
+	return nil
+}
+
+// headersDiff describes the first differing header, empty result means headers are identical.
+func headersDiff(expected, received http.Header) string {
+	keys := make([]string, 0, len(expected)+len(received))
+
+	for k := range expected {
+		keys = append(keys, k)
+	}
+
+	for k := range received {
+		if _, ok := expected[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if volatileHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+
+		e, r := strings.Join(expected.Values(k), ", "), strings.Join(received.Values(k), ", ")
+
+		switch {
+		case len(received.Values(k)) == 0:
+			return fmt.Sprintf("missing header %s, expected %q", k, e)
+		case len(expected.Values(k)) == 0:
+			return fmt.Sprintf("unexpected header %s: %q", k, r)
+		case e != r:
+			return fmt.Sprintf("header %s: %q, expected %q", k, r, e)
+		}
+	}
+
+	return ""
+}
+
+// sniffPrefix returns beginning of body for error messages.
+func sniffPrefix(body []byte) string {
+	if len(body) > sniffLimit {
+		return string(body[:sniffLimit]) + "..."
+	}
+
+	return string(body)
+}
+
+func (l *LocalClient) iShouldHaveAllResponsesIdentical(ctx context.Context, service string) (context.Context, error) {
+	ctx, err := l.checkResponse(ctx, service, func(c *httpmock.Client) error {
+		rt := recorderOf(c)
+		if !rt.concurrent() {
+			return fmt.Errorf("%w: missing `I concurrently request idempotent HTTP endpoint` step", ErrInvalidValue)
+		}
+
+		// Responses with different statuses may fail idempotency check before the callback,
+		// so recorded responses are compared regardless of it.
+		err := c.ExpectResponseBodyCallback(func(_ []byte) error { return nil })
+
+		last := rt.last()
+		if len(last) < 2 {
+			return err
+		}
+
+		return checkIdenticalResponses(last)
+	})
+
+	return l.assertion(ctx, err)
+}
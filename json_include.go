@@ -0,0 +1,236 @@
+package httpsteps
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/swaggest/assertjson/json5"
+	"github.com/yalp/jsonpath"
+)
+
+// includedJSON projects received JSON payload on expected JSON5 payload.
+//
+// Object fields that are absent in expected payload are removed and array elements are matched
+// regardless of order, unmatched elements are removed, so that result can be compared with expected payload for equality.
+// Strings for which wildcard returns true (variables, ignored values) match any value.
+//
+// Boolean result is true if received payload includes expected payload.
+func includedJSON(expected, received []byte, wildcard func(s string) bool) ([]byte, bool) {
+	var exp, rcv interface{}
+
+	if !json5.Valid(expected) || json.Unmarshal(received, &rcv) != nil {
+		return received, false
+	}
+
+	expected, err := json5.Downgrade(expected)
+	if err != nil || json.Unmarshal(expected, &exp) != nil {
+		return received, false
+	}
+
+	projected, ok := projectJSON(exp, rcv, wildcard)
+
+	j, err := json.Marshal(projected)
+	if err != nil {
+		return received, false
+	}
+
+	return j, ok
+}
+
+// matchedJSON projects received JSON payload on expected JSON5 payload, like includedJSON,
+// but arrays must have same length and elements are matched in order.
+func matchedJSON(expected, received []byte, wildcard func(s string) bool) ([]byte, bool) {
+	var exp, rcv interface{}
+
+	if !json5.Valid(expected) || json.Unmarshal(received, &rcv) != nil {
+		return received, false
+	}
+
+	expected, err := json5.Downgrade(expected)
+	if err != nil || json.Unmarshal(expected, &exp) != nil {
+		return received, false
+	}
+
+	projected, ok := projectJSONOrdered(exp, rcv, wildcard)
+
+	j, err := json.Marshal(projected)
+	if err != nil {
+		return received, false
+	}
+
+	return j, ok
+}
+
+// jsonPathsProjection projects received JSON payload on expected values of JSON paths.
+//
+// Expected payload is an object with JSON paths as keys, result is an object with same keys and received values
+// projected on expected values as with includedJSON, so that result can be compared with expected payload for equality.
+//
+// Boolean result is true if all paths are found and received values include expected values.
+func jsonPathsProjection(expected, received []byte, wildcard func(s string) bool) ([]byte, bool) {
+	var (
+		exp map[string]interface{}
+		rcv interface{}
+	)
+
+	if json.Unmarshal(expected, &exp) != nil || json.Unmarshal(received, &rcv) != nil {
+		return received, false
+	}
+
+	res := make(map[string]interface{}, len(exp))
+
+	for path, ev := range exp {
+		rv, err := jsonpath.Read(rcv, path)
+		if err != nil {
+			return received, false
+		}
+
+		p, ok := projectJSON(ev, rv, wildcard)
+		if !ok {
+			return received, false
+		}
+
+		res[path] = p
+	}
+
+	j, err := json.Marshal(res)
+	if err != nil {
+		return received, false
+	}
+
+	return j, true
+}
+
+func projectJSON(exp, rcv interface{}, wildcard func(s string) bool) (interface{}, bool) {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		r, ok := rcv.(map[string]interface{})
+		if !ok {
+			return rcv, false
+		}
+
+		res := make(map[string]interface{}, len(e))
+		match := true
+
+		for k, ev := range e {
+			rv, found := r[k]
+			if !found {
+				match = false
+
+				continue
+			}
+
+			res[k], ok = projectJSON(ev, rv, wildcard)
+			match = match && ok
+		}
+
+		return res, match
+	case []interface{}:
+		r, ok := rcv.([]interface{})
+		if !ok {
+			return rcv, false
+		}
+
+		return projectJSONArray(e, r, wildcard)
+	case string:
+		if wildcard(e) {
+			return rcv, true
+		}
+	}
+
+	return rcv, reflect.DeepEqual(exp, rcv)
+}
+
+func projectJSONArray(exp, rcv []interface{}, wildcard func(s string) bool) (interface{}, bool) {
+	res := make([]interface{}, 0, len(exp))
+	used := make([]bool, len(rcv))
+	match := true
+
+	for i, ev := range exp {
+		found := -1
+
+		for j, rv := range rcv {
+			if used[j] {
+				continue
+			}
+
+			if _, ok := projectJSON(ev, rv, wildcard); ok {
+				found = j
+
+				break
+			}
+		}
+
+		if found == -1 {
+			match = false
+
+			// Element in same position is kept to be shown in diff.
+			if i >= len(rcv) || used[i] {
+				continue
+			}
+
+			found = i
+		}
+
+		used[found] = true
+		p, _ := projectJSON(ev, rcv[found], wildcard)
+		res = append(res, p)
+	}
+
+	return res, match
+}
+
+// projectJSONOrdered removes object fields that are absent in expected value, arrays are projected element-wise.
+func projectJSONOrdered(exp, rcv interface{}, wildcard func(s string) bool) (interface{}, bool) {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		r, ok := rcv.(map[string]interface{})
+		if !ok {
+			return rcv, false
+		}
+
+		res := make(map[string]interface{}, len(e))
+		match := true
+
+		for k, ev := range e {
+			rv, found := r[k]
+			if !found {
+				match = false
+
+				continue
+			}
+
+			res[k], ok = projectJSONOrdered(ev, rv, wildcard)
+			match = match && ok
+		}
+
+		return res, match
+	case []interface{}:
+		r, ok := rcv.([]interface{})
+		if !ok {
+			return rcv, false
+		}
+
+		res := make([]interface{}, len(r))
+		match := len(e) == len(r)
+
+		for i, rv := range r {
+			if i >= len(e) {
+				res[i] = rv
+
+				continue
+			}
+
+			res[i], ok = projectJSONOrdered(e[i], rv, wildcard)
+			match = match && ok
+		}
+
+		return res, match
+	case string:
+		if wildcard(e) {
+			return rcv, true
+		}
+	}
+
+	return rcv, reflect.DeepEqual(exp, rcv)
+}
@@ -0,0 +1,379 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/assertjson/json5"
+)
+
+// JSONOptions tunes comparison of JSON bodies.
+//
+// Options apply to body steps that compare whole JSON documents, e.g. "response with body",
+// "response with body from file" and "response with body, that matches JSON".
+type JSONOptions struct {
+	// Tolerance is a maximum absolute difference of numbers that are considered equal, e.g. 0.001 for rounded floats.
+	Tolerance float64
+
+	// IgnoreArrayOrder enables matching of array elements regardless of their order.
+	IgnoreArrayOrder bool
+
+	// IgnoreMarkers are values of expected document that match any received value in addition to "<ignore-diff>",
+	// e.g. "<any-time>".
+	IgnoreMarkers []string
+}
+
+// isZero is true if options do not affect comparison.
+func (o JSONOptions) isZero() bool {
+	return o.Tolerance == 0 && !o.IgnoreArrayOrder && len(o.IgnoreMarkers) == 0
+}
+
+type jsonOptionsCtxKey struct{}
+
+// jsonOptions returns options of client combined with options of scenario.
+func (l *LocalClient) jsonOptions(ctx context.Context) JSONOptions {
+	opts := l.JSONOptions
+
+	if so, ok := ctx.Value(jsonOptionsCtxKey{}).(JSONOptions); ok {
+		if so.Tolerance != 0 {
+			opts.Tolerance = so.Tolerance
+		}
+
+		opts.IgnoreArrayOrder = opts.IgnoreArrayOrder || so.IgnoreArrayOrder
+		opts.IgnoreMarkers = append(append([]string(nil), opts.IgnoreMarkers...), so.IgnoreMarkers...)
+	}
+
+	return opts
+}
+
+// withJSONOptions updates options of scenario.
+func withJSONOptions(ctx context.Context, update func(o *JSONOptions)) context.Context {
+	so, _ := ctx.Value(jsonOptionsCtxKey{}).(JSONOptions) //nolint:errcheck // Zero value is fine.
+	so.IgnoreMarkers = append([]string(nil), so.IgnoreMarkers...)
+
+	update(&so)
+
+	return context.WithValue(ctx, jsonOptionsCtxKey{}, so)
+}
+
+func (l *LocalClient) jsonNumbersAreComparedWithTolerance(ctx context.Context, tolerance string) (context.Context, error) {
+	t, err := strconv.ParseFloat(tolerance, 64)
+	if err != nil || t < 0 {
+		return ctx, fmt.Errorf("%w: tolerance %q", ErrInvalidValue, tolerance)
+	}
+
+	return withJSONOptions(ctx, func(o *JSONOptions) { o.Tolerance = t }), nil
+}
+
+func (l *LocalClient) jsonArraysAreComparedIgnoringOrder(ctx context.Context) context.Context {
+	return withJSONOptions(ctx, func(o *JSONOptions) { o.IgnoreArrayOrder = true })
+}
+
+func (l *LocalClient) jsonValueMatchesAnyValue(ctx context.Context, marker string) context.Context {
+	return withJSONOptions(ctx, func(o *JSONOptions) { o.IgnoreMarkers = append(o.IgnoreMarkers, marker) })
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSONIgnoringArrayOrder(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, l.matchesJSONIgnoringArrayOrder(bodyDoc))
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyThatMatchesJSONIgnoringArrayOrder(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, l.matchesJSONIgnoringArrayOrder(bodyDoc))
+}
+
+// matchesJSONIgnoringArrayOrder checks received body to match JSON with array elements in any order.
+func (l *LocalClient) matchesJSONIgnoringArrayOrder(bodyDoc string) func(ctx context.Context, received []byte) error {
+	return func(ctx context.Context, received []byte) error {
+		opts := l.jsonOptions(ctx)
+		opts.IgnoreArrayOrder = true
+
+		return augmentBodyErr(l.assertJSONWith(ctx, opts, []byte(bodyDoc), received, true))
+	}
+}
+
+// assertJSON compares payloads with JSON options of client and scenario.
+func (l *LocalClient) assertJSON(ctx context.Context, expected, received []byte, ignoreAddedJSONFields bool) (context.Context, error) {
+	return l.assertJSONWith(ctx, l.jsonOptions(ctx), expected, received, ignoreAddedJSONFields)
+}
+
+// assertJSONWith compares payloads with options, mismatch is explained with BodyDiff if it is enabled.
+func (l *LocalClient) assertJSONWith(ctx context.Context, opts JSONOptions, expected, received []byte, ignoreAddedJSONFields bool) (context.Context, error) {
+	ctx, err := l.compareJSON(ctx, opts, expected, received, ignoreAddedJSONFields)
+	if err != nil && l.BodyDiff != nil {
+		err = l.BodyDiff.explain(l.jsonPathDiff(ctx, opts, expected, received, ignoreAddedJSONFields), err)
+	}
+
+	return ctx, err
+}
+
+// compareJSON compares payloads, received JSON is aligned with expected according to options,
+// so that tolerated differences are not reported.
+func (l *LocalClient) compareJSON(ctx context.Context, opts JSONOptions, expected, received []byte, ignoreAddedJSONFields bool) (context.Context, error) {
+	if opts.isZero() || !json.Valid(received) {
+		return l.VS.Assert(ctx, expected, received, ignoreAddedJSONFields)
+	}
+
+	ctx, expected, err := l.VS.Replace(ctx, expected)
+	if err != nil {
+		return ctx, err
+	}
+
+	if !json5.Valid(expected) {
+		return l.VS.Assert(ctx, expected, received, ignoreAddedJSONFields)
+	}
+
+	if expected, err = json5.Downgrade(expected); err != nil {
+		return ctx, err
+	}
+
+	ctx, a := l.jsonAligner(ctx, opts, ignoreAddedJSONFields)
+
+	var exp, rcv interface{}
+
+	if err := decodeJSON(expected, &exp); err != nil {
+		return ctx, err
+	}
+
+	if err := decodeJSON(received, &rcv); err != nil {
+		return ctx, err
+	}
+
+	exp = a.markers(exp)
+	rcv = a.align(exp, rcv)
+
+	if expected, err = json.Marshal(exp); err != nil {
+		return ctx, err
+	}
+
+	if received, err = json.Marshal(rcv); err != nil {
+		return ctx, err
+	}
+
+	return l.VS.Assert(ctx, expected, received, ignoreAddedJSONFields)
+}
+
+// jsonAligner returns aligner of received values, unset variables and ignore diff value of expected match any value.
+func (l *LocalClient) jsonAligner(ctx context.Context, opts JSONOptions, ignoreAddedJSONFields bool) (context.Context, jsonAligner) {
+	ignoreDiff := assertjson.IgnoreDiff
+	if l.VS != nil {
+		ignoreDiff = l.VS.JSONComparer.IgnoreDiff
+	}
+
+	ctx, v := l.VS.Vars(ctx)
+
+	return ctx, jsonAligner{
+		opts:        opts,
+		ignoreAdded: ignoreAddedJSONFields,
+		ignoreDiff:  ignoreDiff,
+		wildcard: func(s string) bool {
+			return (ignoreDiff != "" && s == ignoreDiff) || v.IsVar(s)
+		},
+	}
+}
+
+// assertJSONFile compares payload with file contents with JSON options of client and scenario.
+func (l *LocalClient) assertJSONFile(ctx context.Context, filePath string, received []byte, ignoreAddedJSONFields bool) (context.Context, error) {
+	if l.jsonOptions(ctx).isZero() && l.BodyDiff == nil {
+		return l.VS.AssertFile(ctx, filePath, received, ignoreAddedJSONFields)
+	}
+
+	expected, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return ctx, err
+	}
+
+	return l.assertJSON(ctx, expected, received, ignoreAddedJSONFields)
+}
+
+// decodeJSON decodes payload keeping numbers as is.
+func decodeJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	return d.Decode(v)
+}
+
+// jsonAligner makes received JSON value equal to expected where difference is tolerated by options.
+type jsonAligner struct {
+	opts        JSONOptions
+	ignoreAdded bool
+	ignoreDiff  string
+	wildcard    func(s string) bool
+}
+
+// markers replaces ignore markers of expected value with ignore diff value.
+func (a jsonAligner) markers(exp interface{}) interface{} {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		for k, v := range e {
+			e[k] = a.markers(v)
+		}
+	case []interface{}:
+		for i, v := range e {
+			e[i] = a.markers(v)
+		}
+	case string:
+		for _, m := range a.opts.IgnoreMarkers {
+			if e == m && a.ignoreDiff != "" {
+				return a.ignoreDiff
+			}
+		}
+	}
+
+	return exp
+}
+
+// align returns received value with tolerated differences replaced by expected values
+// and array elements ordered as in expected value.
+func (a jsonAligner) align(exp, rcv interface{}) interface{} {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		r, ok := rcv.(map[string]interface{})
+		if !ok {
+			return rcv
+		}
+
+		res := make(map[string]interface{}, len(r))
+
+		for k, rv := range r {
+			if ev, found := e[k]; found {
+				rv = a.align(ev, rv)
+			}
+
+			res[k] = rv
+		}
+
+		return res
+	case []interface{}:
+		r, ok := rcv.([]interface{})
+		if !ok {
+			return rcv
+		}
+
+		if a.opts.IgnoreArrayOrder {
+			return a.alignArray(e, r)
+		}
+
+		res := make([]interface{}, len(r))
+
+		for i, rv := range r {
+			if i < len(e) {
+				rv = a.align(e[i], rv)
+			}
+
+			res[i] = rv
+		}
+
+		return res
+	case json.Number:
+		r, ok := rcv.(json.Number)
+		if !ok || a.opts.Tolerance == 0 {
+			return rcv
+		}
+
+		ef, err1 := e.Float64()
+		rf, err2 := r.Float64()
+
+		if err1 == nil && err2 == nil && math.Abs(ef-rf) <= a.opts.Tolerance {
+			return e
+		}
+	}
+
+	return rcv
+}
+
+// alignArray orders received elements as matching expected elements, unmatched elements keep their order.
+func (a jsonAligner) alignArray(exp, rcv []interface{}) []interface{} {
+	res := make([]interface{}, len(rcv))
+	used := make([]bool, len(rcv))
+	placed := make([]bool, len(rcv))
+
+	for i, ev := range exp {
+		if i >= len(rcv) {
+			break
+		}
+
+		for j, rv := range rcv {
+			if !used[j] && a.matches(ev, rv) {
+				used[j], placed[i] = true, true
+				res[i] = a.align(ev, rv)
+
+				break
+			}
+		}
+	}
+
+	// Unmatched elements fill remaining positions in received order to be shown in diff.
+	j := 0
+
+	for i := range res {
+		if placed[i] {
+			continue
+		}
+
+		for used[j] {
+			j++
+		}
+
+		used[j] = true
+
+		if i < len(exp) {
+			res[i] = a.align(exp[i], rcv[j])
+		} else {
+			res[i] = rcv[j]
+		}
+	}
+
+	return res
+}
+
+// matches is true if received value is equal to expected with options.
+func (a jsonAligner) matches(exp, rcv interface{}) bool {
+	switch e := exp.(type) {
+	case map[string]interface{}:
+		r, ok := rcv.(map[string]interface{})
+		if !ok || (!a.ignoreAdded && len(r) != len(e)) {
+			return false
+		}
+
+		for k, ev := range e {
+			rv, found := r[k]
+			if !found || !a.matches(ev, rv) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		r, ok := rcv.([]interface{})
+		if !ok || len(r) != len(e) {
+			return false
+		}
+
+		aligned, _ := a.align(e, r).([]interface{}) //nolint:errcheck // Array is aligned to array.
+		for i := range e {
+			if !a.matches(e[i], aligned[i]) {
+				return false
+			}
+		}
+
+		return true
+	case string:
+		if a.wildcard(e) {
+			return true
+		}
+	case json.Number:
+		if r, ok := rcv.(json.Number); ok {
+			return a.align(e, r) == exp
+		}
+	}
+
+	return reflect.DeepEqual(exp, rcv)
+}
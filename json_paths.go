@@ -0,0 +1,163 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/yalp/jsonpath"
+)
+
+// jsonPathOperators are prefixes of expected values that are checked as conditions instead of JSON equality,
+// longer operators go first to be matched before their prefixes.
+var jsonPathOperators = []string{"len ", ">=", "<=", "==", "!=", "~=", ">", "<"}
+
+// jsonPathOperator splits expected value into operator and operand, ok is false for plain JSON value.
+func jsonPathOperator(expected string) (op, operand string, ok bool) {
+	for _, op := range jsonPathOperators {
+		if strings.HasPrefix(expected, op) {
+			return strings.TrimSpace(op), strings.TrimSpace(strings.TrimPrefix(expected, op)), true
+		}
+	}
+
+	return "", "", false
+}
+
+// assertJSONPaths checks values of JSON paths, expected value can be a JSON value or a condition with operator,
+// e.g. ">= 10", "~= ^ord-\d+$" or "len == 3".
+func (l *LocalClient) assertJSONPaths(ctx context.Context, jsonPaths *godog.Table, received []byte, ignoreAddedJSONFields bool) error {
+	var rcv interface{}
+	if err := json.Unmarshal(received, &rcv); err != nil {
+		return fmt.Errorf("failed to unmarshal received value: %w", err)
+	}
+
+	for i, row := range jsonPaths.Rows {
+		if len(row.Cells) != 2 {
+			return fmt.Errorf("%w: 2 expected, %d received", ErrInvalidNumberOfColumns, len(row.Cells))
+		}
+
+		path := row.Cells[0].Value
+
+		op, operand, ok := jsonPathOperator(row.Cells[1].Value)
+		if !ok {
+			// Plain values are asserted with variables support of JSON comparer.
+			if _, err := l.VS.AssertJSONPaths(ctx, &godog.Table{Rows: jsonPaths.Rows[i : i+1]}, received, ignoreAddedJSONFields); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		val, err := jsonpath.Read(rcv, path)
+		if err != nil {
+			return fmt.Errorf("failed to read jsonpath %s: %w", path, err)
+		}
+
+		_, rv, err := l.VS.Replace(ctx, []byte(operand))
+		if err != nil {
+			return fmt.Errorf("failed to prepare expected value at jsonpath %s: %w", path, err)
+		}
+
+		if err := checkJSONPathCondition(val, op, string(rv)); err != nil {
+			return fmt.Errorf("failed to assert jsonpath %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// checkJSONPathCondition checks value with operator and operand.
+func checkJSONPathCondition(val interface{}, op, operand string) error {
+	switch op {
+	case "len":
+		n, ok := jsonLen(val)
+		if !ok {
+			return fmt.Errorf("%w: length of %s", ErrInvalidValue, jsonValue(val))
+		}
+
+		lop, loperand, ok := jsonPathOperator(operand)
+		if !ok || lop == "~=" || lop == "len" {
+			return fmt.Errorf("%w: length condition %q, e.g. \"len == 3\" expected", ErrInvalidValue, "len "+operand)
+		}
+
+		if err := checkJSONPathCondition(float64(n), lop, loperand); err != nil {
+			return fmt.Errorf("length %d: %w", n, err)
+		}
+
+		return nil
+	case "~=":
+		re, err := regexp.Compile(operand)
+		if err != nil {
+			return fmt.Errorf("%w: regular expression %q: %s", ErrInvalidValue, operand, err.Error())
+		}
+
+		s, ok := val.(string)
+		if !ok {
+			s = jsonValue(val)
+		}
+
+		if !re.MatchString(s) {
+			return fmt.Errorf("%w: %q does not match %q", ErrUnmetCondition, s, operand)
+		}
+
+		return nil
+	case "==", "!=":
+		var exp interface{}
+		if err := json.Unmarshal([]byte(operand), &exp); err != nil {
+			return fmt.Errorf("%w: JSON value %q: %s", ErrInvalidValue, operand, err.Error())
+		}
+
+		if equal := jsonValue(exp) == jsonValue(val); equal != (op == "==") {
+			return fmt.Errorf("%w: %s %s %s", ErrUnmetCondition, jsonValue(val), op, operand)
+		}
+
+		return nil
+	}
+
+	f, ok := val.(float64)
+	if !ok {
+		return fmt.Errorf("%w: %s is not a number", ErrUnmetCondition, jsonValue(val))
+	}
+
+	limit, err := strconv.ParseFloat(operand, 64)
+	if err != nil {
+		return fmt.Errorf("%w: number %q", ErrInvalidValue, operand)
+	}
+
+	var met bool
+
+	switch op {
+	case ">=":
+		met = f >= limit
+	case "<=":
+		met = f <= limit
+	case ">":
+		met = f > limit
+	case "<":
+		met = f < limit
+	}
+
+	if !met {
+		return fmt.Errorf("%w: %s %s %s", ErrUnmetCondition, jsonValue(val), op, operand)
+	}
+
+	return nil
+}
+
+// jsonLen returns length of array, object or string.
+func jsonLen(val interface{}) (int, bool) {
+	switch v := val.(type) {
+	case []interface{}:
+		return len(v), true
+	case map[string]interface{}:
+		return len(v), true
+	case string:
+		return len([]rune(v)), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,183 @@
+package httpsteps
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// jsonFromTable builds JSON document from a table of paths and values.
+//
+// Path is a dot-separated list of object keys with optional array indexes, e.g. `items[0].name`.
+// Path may have a type hint suffix, e.g. `zip:string`, supported hints are
+// `string`, `number`, `bool`, `null` and `json`, other suffixes after colon are a part of path.
+// Without type hint value is used as JSON if it is valid, or as string otherwise.
+func jsonFromTable(data *godog.Table) ([]byte, error) {
+	var doc interface{}
+
+	for _, r := range data.Rows {
+		if len(r.Cells) != 2 {
+			return nil, fmt.Errorf("%w, 2 expected, %d received",
+				ErrInvalidNumberOfColumns, len(r.Cells))
+		}
+
+		path, hint := tablePathHint(r.Cells[0].Value)
+
+		val, err := tableValue(r.Cells[1].Value, hint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare value of %s: %w", r.Cells[0].Value, err)
+		}
+
+		segments, err := parseTablePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc, err = setTablePath(doc, segments, val); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", path, err)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// tablePathHint splits path and type hint, suffix that is not a known hint is a part of path, e.g. `urn:id`.
+func tablePathHint(path string) (string, string) {
+	pos := strings.LastIndex(path, ":")
+	if pos == -1 {
+		return path, ""
+	}
+
+	switch hint := path[pos+1:]; hint {
+	case "string", "number", "bool", "null", "json":
+		return path[:pos], hint
+	default:
+		return path, ""
+	}
+}
+
+func tableValue(value, hint string) (interface{}, error) {
+	switch hint {
+	case "string":
+		return value, nil
+	case "number":
+		return json.Number(value), validJSON(value, func(v interface{}) bool {
+			_, ok := v.(float64)
+
+			return ok
+		})
+	case "bool":
+		return strconv.ParseBool(value)
+	case "null":
+		return nil, nil
+	case "json":
+		return json.RawMessage(value), validJSON(value, nil)
+	case "":
+		if json.Valid([]byte(value)) {
+			return json.RawMessage(value), nil
+		}
+
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTypeHint, hint)
+	}
+}
+
+func validJSON(value string, check func(v interface{}) bool) error {
+	var v interface{}
+
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return err
+	}
+
+	if check != nil && !check(v) {
+		return fmt.Errorf("%w: %s", ErrInvalidValue, value)
+	}
+
+	return nil
+}
+
+// parseTablePath splits path into object keys (string) and array indexes (int).
+func parseTablePath(path string) ([]interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	var segments []interface{}
+
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		if pos := strings.Index(part, "["); pos != -1 {
+			key = part[:pos]
+		}
+
+		if key != "" {
+			segments = append(segments, key)
+		}
+
+		for rest := part[len(key):]; rest != ""; {
+			end := strings.Index(rest, "]")
+			if rest[0] != '[' || end == -1 {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidTablePath, path)
+			}
+
+			idx, err := strconv.Atoi(rest[1:end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidTablePath, path)
+			}
+
+			segments = append(segments, idx)
+			rest = rest[end+1:]
+		}
+
+		if key == "" && part == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTablePath, path)
+		}
+	}
+
+	return segments, nil
+}
+
+func setTablePath(node interface{}, segments []interface{}, val interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return val, nil
+	}
+
+	var err error
+
+	switch s := segments[0].(type) {
+	case string:
+		if node == nil {
+			node = map[string]interface{}{}
+		}
+
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: object expected for key %s", ErrInvalidTablePath, s)
+		}
+
+		obj[s], err = setTablePath(obj[s], segments[1:], val)
+
+		return obj, err
+	case int:
+		if node == nil {
+			node = []interface{}{}
+		}
+
+		arr, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: array expected for index %d", ErrInvalidTablePath, s)
+		}
+
+		for len(arr) <= s {
+			arr = append(arr, nil)
+		}
+
+		arr[s], err = setTablePath(arr[s], segments[1:], val)
+
+		return arr, err
+	}
+
+	return node, nil
+}
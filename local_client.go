@@ -9,30 +9,28 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"net/http/httputil"
+	"net/textproto"
 	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bool64/httpmock"
 	"github.com/bool64/shared"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/cucumber/godog"
 	"github.com/godogx/vars"
+	"github.com/swaggest/assertjson"
 	"github.com/swaggest/assertjson/json5"
+	"github.com/yalp/jsonpath"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
-type sentinelError string
-
-// Error returns the error message.
-func (e sentinelError) Error() string {
-	return string(e)
-}
-
 // NewLocalClient creates an instance of step-driven HTTP service.
 func NewLocalClient(defaultBaseURL string, options ...func(*httpmock.Client)) *LocalClient {
 	if defaultBaseURL != "" &&
@@ -46,6 +44,7 @@ func NewLocalClient(defaultBaseURL string, options ...func(*httpmock.Client)) *L
 	l := LocalClient{
 		options:           options,
 		ExposeHTTPDetails: DefaultExposeHTTPDetails,
+		Fixtures:          &Fixtures{},
 	}
 
 	l.AddService(Default, defaultBaseURL)
@@ -55,8 +54,18 @@ func NewLocalClient(defaultBaseURL string, options ...func(*httpmock.Client)) *L
 
 // LocalClient is step-driven HTTP service for application local HTTP service.
 type LocalClient struct {
-	services map[string]*httpmock.Client
-	options  []func(*httpmock.Client)
+	mu         sync.RWMutex
+	services   map[string]*httpmock.Client
+	configs    map[string]ServiceConfig
+	providers  map[string]BodyProvider
+	streams    map[string]BodyStream
+	protoFiles *protoregistry.Files
+	profiles   map[string]ResponseProfile
+	saved      map[string]SavedRequest
+	observers  []func(req RequestInfo, resp ResponseInfo)
+	grouping   IdempotencyGrouping
+	options    []func(*httpmock.Client)
+	registered registry
 
 	// Deprecated: use VS.JSONComparer.Vars.
 	Vars *shared.Vars
@@ -67,6 +76,88 @@ type LocalClient struct {
 	// ExposeHTTPDetails enables godog.Attachment for request and response data.
 	// Has DefaultExposeHTTPDetails by default.
 	ExposeHTTPDetails func(ctx context.Context, d httpmock.HTTPValue) (context.Context, error)
+
+	// ConcurrentVars defines how variables captured from concurrent responses are merged.
+	// Has ConcurrentVarsStrict by default.
+	ConcurrentVars ConcurrentVarsPolicy
+
+	// TagHeaders maps scenario tag name to request header name, tag argument is used as header value.
+	// For example, with {"variant": "X-Experiment"} scenario tagged @variant(B) sends X-Experiment: B in all requests.
+	TagHeaders map[string]string
+
+	// Fixtures provides generated request bodies, can be shared with ExternalServer.
+	Fixtures *Fixtures
+
+	// CurlOutput receives an equivalent curl command of every sent request to reproduce it, optional.
+	// Values of headers, query parameters and body fields with secret-like names (e.g. Authorization, Cookie,
+	// password or token) are redacted.
+	CurlOutput io.Writer
+
+	// DebugOutput receives dumps of requests and responses of scenarios with "I enable HTTP debug logging" step,
+	// os.Stdout is used by default. Secrets are redacted as in ExposeHTTPDetails.
+	DebugOutput io.Writer
+
+	// OnWarning is called with failed response assertion that is reported as a warning, optional.
+	OnWarning func(ctx context.Context, err error)
+
+	// StreamFileBodyThreshold enables streaming of request body from file of this size or larger,
+	// so that large file is not read into memory. Variables are not replaced in streamed body.
+	// Streaming is disabled by default.
+	StreamFileBodyThreshold int64
+
+	// RetryConnectionReset enables one transparent retry of GET and HEAD requests that failed
+	// due to connection reset, e.g. on a shared CI network. Retries are attached to the scenario
+	// as godog.Attachment. Other methods and failures are never retried. Disabled by default.
+	RetryConnectionReset bool
+
+	// HealthCheck defines expectations of "I should have a healthy response" step.
+	// Has DefaultHealthCheck by default.
+	HealthCheck *HealthCheck
+
+	// StepPrefix is prepended to expressions of all steps, so that several instances can be registered
+	// in one suite, e.g. with "billing: " steps look like "billing: I request HTTP endpoint with ...".
+	StepPrefix string
+
+	// StepPatterns replaces default expressions of steps, e.g. with translations for feature files in other languages.
+	StepPatterns StepPatterns
+
+	// IsolatedVars enables separate storage of variables used in steps of this client, so that variables
+	// are not shared with other clients and vars steps. Values of VS.JSONComparer.Vars are used as initial values.
+	IsolatedVars bool
+
+	// StepUsage collects statistics of step usage, optional. Can be shared with ExternalServer.
+	StepUsage *StepUsage
+
+	// Deprecations collects endpoints that respond with Deprecation or Sunset header, optional.
+	Deprecations *Deprecations
+
+	// JSONOptions tunes comparison of JSON bodies, options can also be set for a scenario with steps.
+	JSONOptions JSONOptions
+
+	// SnapshotsDir is a directory of response snapshots, "__snapshots__" directory next to feature file by default.
+	SnapshotsDir string
+
+	// MaxDiffLength limits length of body assertion error message, no limit by default.
+	// Full received body is attached to the scenario as godog.Attachment on body assertion failure.
+	MaxDiffLength int
+
+	// DryRun disables sending of requests, so that feature files can be validated quickly, e.g. in CI.
+	// Steps that check responses only validate their arguments: services, status names, durations,
+	// variables, files and JSON5 documents. Steps that configure requests are run as usual.
+	DryRun bool
+
+	// StrictServices fails steps with unknown or malformed service qualifiers (e.g. `I request "billing HTTP endpoint`)
+	// with a list of registered services, instead of resolving text captured before the rest of step as a service name.
+	StrictServices bool
+
+	// BodyDiff enables reporting of JSON body mismatch as a list of differing paths instead of
+	// a full document diff, optional.
+	BodyDiff *BodyDiff
+
+	// NormalizeTextBodies enables comparison of non-JSON bodies (e.g. CSV or plain text) ignoring line endings
+	// and trailing whitespace, so that fixtures with CRLF line endings match LF responses and vice versa.
+	// Can be enabled for a single step with ", ignoring line endings and trailing whitespace" suffix.
+	NormalizeTextBodies bool
 }
 
 // HTTPValue grants access to a HTTP request and response.
@@ -78,12 +169,25 @@ type HTTPValue struct {
 }
 
 // AddService registers a URL for named service.
-func (l *LocalClient) AddService(name, baseURL string) {
+//
+// Options define default behavior of service requests, see ServiceConfig.
+func (l *LocalClient) AddService(name, baseURL string, options ...func(cfg *ServiceConfig)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	if l.services == nil {
 		l.services = make(map[string]*httpmock.Client)
+		l.configs = make(map[string]ServiceConfig)
+	}
+
+	cfg := ServiceConfig{}
+
+	for _, o := range options {
+		o(&cfg)
 	}
 
 	l.services[name] = l.makeClient(baseURL)
+	l.configs[name] = cfg
 }
 
 // RegisterSteps adds HTTP server steps to godog scenario context.
@@ -99,6 +203,10 @@ func (l *LocalClient) AddService(name, baseURL string) {
 //
 //	And I request "some-service" HTTP endpoint with header "X-Foo: bar"
 //
+// Service can also be registered for a scenario with a step, e.g. in a Background with base URL from a variable.
+//
+//	Given HTTP service "inventory" is available at "$inventoryURL"
+//
 // An additional header can be supplied. For multiple headers, call step multiple times.
 //
 //	And I request HTTP endpoint with header "X-Foo: bar"
@@ -107,6 +215,29 @@ func (l *LocalClient) AddService(name, baseURL string) {
 //
 //	And I request HTTP endpoint with cookie "name: value"
 //
+// Request trailer can be supplied, request body is then sent with chunked transfer encoding.
+//
+//	And I request HTTP endpoint with trailer "X-Checksum: abc"
+//
+// Bearer token can be supplied as Authorization header.
+//
+//	And I request HTTP endpoint with bearer token "$token"
+//
+// Or for all requests in scenario, token is resolved when request is configured.
+//
+//	Given all requests use bearer token "$token"
+//
+// Scenario can act as a different caller after client state is reset, default headers and cookies, scenario
+// bearer token and options of pending request are discarded.
+//
+//	When I reset HTTP client state
+//
+// Headers can be defined by scenario tags with LocalClient.TagHeaders mapping, e.g. @variant(B) for X-Experiment: B.
+//
+// Request can be signed with hex-encoded HMAC-SHA256 of final body in a header.
+//
+//	And I request HTTP endpoint signed with HMAC-SHA256 using key "$secret" in header "X-Signature"
+//
 // Optionally request body can be configured. If body is a valid JSON5 payload, it will be converted to JSON before use.
 // Otherwise, body is used as is.
 //
@@ -125,6 +256,28 @@ func (l *LocalClient) AddService(name, baseURL string) {
 //	path/to/file.json5
 //	"""
 //
+// Large files can be streamed without reading into memory, see LocalClient.StreamFileBodyThreshold.
+//
+// Request body can be streamed from a stream registered with AddBodyStream, e.g. GeneratedBody.
+//
+//	And I request HTTP endpoint with body streamed from "1GB"
+//
+// JSON request body can be built from a table of paths and values, path may have a type hint.
+//
+//	And I request HTTP endpoint with JSON body from table
+//	  | user.name       | John Doe |
+//	  | user.zip:string | 12345    |
+//	  | items[0].id     | 1        |
+//
+// Request body can be generated at run time by a fixture registered in LocalClient.Fixtures, e.g. a signed payload.
+// Fixture is generated once per scenario.
+//
+//	And I request HTTP endpoint with body from fixture "signedOrder"
+//
+// Whole request can be configured from a saved request registered with AddSavedRequest or LoadPostmanCollection.
+//
+//	When I send saved request "Create user"
+//
 // If endpoint is capable of handling duplicated requests, you can check it for idempotency. This would send multiple
 // requests simultaneously and check
 //   - if all responses are similar or (all successful like GET),
@@ -135,6 +288,20 @@ func (l *LocalClient) AddService(name, baseURL string) {
 //
 //	And I concurrently request idempotent HTTP endpoint
 //
+// Number of requests can also be defined in step.
+//
+//	And I concurrently request idempotent HTTP endpoint with 25 requests
+//
+// Responses are grouped by status code, body expectations can use custom grouping, see
+// LocalClient.WithIdempotencyGrouping.
+//
+// Concurrency can be ramped up in waves to find a threshold of failures, the last wave is checked by
+// regular response expectations.
+//
+//	And I concurrently request idempotent HTTP endpoint with ramp from 5 to 50 requests in 4 steps
+//	Then I should have ramp responses with status "OK" up to 20 concurrent requests
+//	And I should have first failed ramp response with status "Too Many Requests" at 35 or more concurrent requests
+//
 // # Response Expectations
 //
 // Response expectation has to be configured with at least one step about status, response body or other responses body
@@ -159,12 +326,192 @@ func (l *LocalClient) AddService(name, baseURL string) {
 //	path/to/file.json
 //	"""
 //
+// Match mode can be set explicitly with `exactly` or `ignoring extra fields`.
+//
+//	And I should have response with body from file, ignoring extra fields
+//	"""
+//	path/to/file.json5
+//	"""
+//
+// Array elements can be matched in any order.
+//
+//	And I should have response with body, that matches JSON ignoring array order
+//	"""
+//	{"items":[{"id":3},{"id":1},{"id":2}]}
+//	"""
+//
+// JSON comparison can be relaxed for a scenario, or for all scenarios with JSONOptions.
+//
+//	Given JSON numbers are compared with tolerance "0.001"
+//	And JSON arrays are compared ignoring order
+//	And JSON value "<any-time>" matches any value
+//
+// Body can be compared with a snapshot that is recorded on the first run or when UpdateSnapshotsEnv is set.
+//
+//	And I should have response matching snapshot "user-profile"
+//
+// Text body (e.g. CSV) can be compared ignoring line endings and trailing whitespace,
+// this can also be enabled for all steps with NormalizeTextBodies.
+//
+//	And I should have response with body from file, ignoring line endings and trailing whitespace
+//	"""
+//	path/to/report.csv
+//	"""
+//
+// Binary body (e.g. image or PDF) can be compared byte-exact with file, offset of first difference is reported.
+//
+//	And I should have response with binary body from file
+//	"""
+//	path/to/image.png
+//	"""
+//
+// Body can be checked to contain a substring, e.g. a fragment of HTML page.
+//
+//	And I should have response with body containing "<h1>Welcome!</h1>"
+//
+// Expected values of JSON paths can be conditions with operators: >=, <=, >, <, ==, !=, ~= (regular expression)
+// and len (length of array, object or string).
+//
+//	And I should have response with body, that matches JSON paths
+//	| $.total | >= 10        |
+//	| $.id    | ~= ^ord-\d+$ |
+//	| $.items | len == 3     |
+//
+// CSV body can be compared with a table, header row may have a subset of columns.
+//
+//	And I should have response with CSV body
+//	| id | name |
+//	| 1  | foo  |
+//	| 2  | $bar |
+//
+// Newline delimited JSON body is compared line by line, one expected JSON value per line.
+//
+//	And I should have response with NDJSON lines
+//	"""
+//	{"id":1,"createdAt":"<ignore-diff>"}
+//	{"id":2,"createdAt":"<ignore-diff>"}
+//	"""
+//
+// Protobuf bodies are written as JSON for message types registered with AddDescriptorSet.
+//
+//	And I request HTTP endpoint with protobuf "shop.Order" body
+//	"""
+//	{"id":"ord-1","total":21}
+//	"""
+//	Then I should have response with protobuf "shop.Order" body
+//	"""
+//	{"id":"ord-1","total":"42"}
+//	"""
+//
+// Body can be checked to not contain a substring or JSON paths (optionally with a value), e.g. to avoid leaking secrets.
+//
+//	And I should have response with body not containing
+//	"""
+//	s3cr3t
+//	"""
+//
+//	And I should have response with body not containing JSON paths
+//	  | $.password |         |
+//	  | $.role     | "admin" |
+//
+// Received JSON body may include additional fields and array elements with `including JSON`,
+// array elements are matched regardless of order.
+//
+//	And I should have response with body including JSON
+//	"""
+//	{"items":[{"id":2}]}
+//	"""
+//
+// Cross-field invariants can be checked with a CEL expression (https://github.com/google/cel-spec)
+// over parsed JSON body, status code and headers.
+//
+//	And I should have response satisfying expression "body.total == sum(body.items.map(i, i.price))"
+//
+// Body can be checked to match its Content-Type, e.g. to catch HTML error page served as JSON.
+//
+//	And I should have response whose body matches its Content-Type
+//
+// Expected body fragment can be supplied by a provider registered with AddBodyProvider, e.g. from database state.
+// Received body is expected to include provided JSON.
+//
+//	And I should have response with body matching provider "ordersFromDB"
+//
 // Status can be defined with either phrase or numeric code. Also, you can set response header expectations.
 //
 //	Then I should have response with status "OK"
 //	And I should have response with header "Content-Type: application/json"
 //	And I should have response with header "X-Header: abc"
 //
+// Cookies of Set-Cookie headers can be checked with attributes, value can be compared or captured as a variable.
+//
+//	And I should have response with cookie "session" having "HttpOnly", "Secure" and "SameSite=Strict"
+//	And I should have response with cookie "session: $sid"
+//
+// Response trailers (e.g. of gRPC-web gateways) can be checked in same way.
+//
+//	And I should have response with trailer "Grpc-Status: 0"
+//
+// Server-Timing metrics can be checked for presence and duration, diagnostic headers with duration
+// (e.g. "12ms" or "12.5" as milliseconds) can be checked too.
+//
+//	And I should have server timing "cache"
+//	And I should have server timing "db" under "50ms"
+//	And I should have response with header "X-Response-Time" under "100ms"
+//
+// Common expectations (status, headers and JSON schema of body) can be defined once as a named profile
+// with AddResponseProfile or LoadResponseProfiles and applied with a single step.
+//
+//	And I should have a "standard-error" response
+//
+// Response of health or readiness endpoint can be checked with a preset of expectations, see HealthCheck.
+//
+//	And I should have a healthy response
+//	And I should have a healthy "some-service" response
+//
+// Compressed response (gzip, br or deflate) is decoded before body assertions, encoding can be asserted.
+//
+//	And I should have response compressed with "gzip"
+//
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers can be asserted, sunset is a date or a full time.
+// Deprecated endpoints used in a suite can be reported with Deprecations.
+//
+//	And I should have response marked deprecated
+//	And I should have response marked deprecated with sunset "2025-01-01"
+//
+// Parts of a JSON batch or WebDAV multi-status response can be checked with a table.
+//
+//	And I should have response with parts
+//	  | id | status    | body             |
+//	  | a  | OK        | {"name":"Alice"} |
+//	  | b  | Not Found |                  |
+//
+// Asynchronous operation can be polled until response has expected status.
+//
+//	When I poll HTTP endpoint with method "GET" and URI "/job/$id" every "500ms" for up to "30s" until response has status "OK"
+//
+// Value of the latest received response can be used in subsequent requests.
+//
+//	And I use "$.id" from previous response as $orderID
+//	When I request HTTP endpoint with method "GET" and URI "/orders/$orderID"
+//
+// Request can be named to check its response after other requests.
+//
+//	When I request HTTP endpoint "login" with method "POST" and URI "/login"
+//	...
+//	Then response of "login" should have status "OK"
+//	And response of "login" should have header "X-Session: $session"
+//	And response of "login" should have body
+//	"""
+//	{"user":"$user"}
+//	"""
+//
+// Failed response assertions can be reported as warnings (attached to the scenario) instead of failing it,
+// for scenarios tagged with @warnings or after a step.
+//
+//	Given failed response assertions are warnings
+//	Then I should have response with header "X-New-Header: abc"
+//	And failed response assertions are errors
+//
 // In an idempotent mode you can set expectations for statuses of other responses.
 //
 //	Then I should have response with status "204"
@@ -186,54 +533,214 @@ func (l *LocalClient) AddService(name, baseURL string) {
 //	path/to/file.json
 //	"""
 //
+// Or all concurrent responses can be required to be identical, including headers (except Date).
+//
+//	Then I should have all responses identical including headers
+//
+// Requests and responses can be dumped to DebugOutput for the rest of a scenario.
+//
+//	Given I enable HTTP debug logging
+//
+// Several clients can be registered in one suite with different StepPrefix values,
+// variables of a client can be isolated from other clients with IsolatedVars.
+//
+//	When billing: I request HTTP endpoint with method "GET" and URI "/invoice"
+//
 // More information at https://github.com/godogx/httpsteps/#local-client.
 func (l *LocalClient) RegisterSteps(s *godog.ScenarioContext) {
-	s.Step(`^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`, l.iRequestWithMethodAndURI)
-	s.Step(`^I request(.*) HTTP endpoint with body$`, l.iRequestWithBody)
-	s.Step(`^I request(.*) HTTP endpoint with body from file$`, l.iRequestWithBodyFromFile)
-	s.Step(`^I request(.*) HTTP endpoint with header "([^"]*): ([^"]*)"$`, l.iRequestWithHeader)
-	s.Step(`^I request(.*) HTTP endpoint with cookie "([^"]*): ([^"]*)"$`, l.iRequestWithCookie)
-
-	s.Step(`^I request(.*) HTTP endpoint with cookies$`, l.iRequestWithCookies)
-	s.Step(`^I request(.*) HTTP endpoint with headers$`, l.iRequestWithHeaders)
-	s.Step(`^I request(.*) HTTP endpoint with query parameters$`, l.iRequestWithQueryParameters)
-	s.Step(`^I request(.*) HTTP endpoint with urlencoded form data$`, l.iRequestWithFormDataParameters)
-
-	s.Step(`^I follow redirects from(.*) HTTP endpoint$`, l.iFollowRedirects)
-	s.Step(`^I retry(.*) HTTP request up to (\d+ time[s]?|.*)$`, l.iRetry)
-	s.Step(`^I concurrently request idempotent(.*) HTTP endpoint$`, l.iRequestWithConcurrency)
-
-	s.Step(`^I request(.*) HTTP endpoint with attachment as field "([^"]*)" and file name "([^"]*)"$`, l.iRequestWithAttachment)
-	s.Step(`^I request(.*) HTTP endpoint with attachment as field "([^"]*)" from file$`, l.iRequestWithAttachmentFromFile)
-
-	s.Step(`^I should have(.*) response with status "([^"]*)"$`, l.iShouldHaveResponseWithStatus)
-	s.Step(`^I should have(.*) response with header "([^"]*): ([^"]*)"$`, l.iShouldHaveResponseWithHeader)
-	s.Step(`^I should have(.*) response with headers$`, l.iShouldHaveResponseWithHeaders)
-
-	s.Step(`^I should have(.*) response with body from file$`, l.iShouldHaveResponseWithBodyFromFile)
-	s.Step(`^I should have(.*) response with body$`, l.iShouldHaveResponseWithBody)
-	s.Step(`^I should have(.*) response with body, that contains$`, l.iShouldHaveResponseWithBodyThatContains)
-	s.Step(`^I should have(.*) response with body, that matches JSON from file$`, l.iShouldHaveResponseWithBodyThatMatchesJSONFromFile)
-	s.Step(`^I should have(.*) response with body, that matches JSON$`, l.iShouldHaveResponseWithBodyThatMatchesJSON)
-	s.Step(`^I should have(.*) response with body, that matches JSON paths$`, l.iShouldHaveResponseWithBodyThatMatchesJSONPaths)
-
-	s.Step(`^I should have(.*) other responses with status "([^"]*)"$`, l.iShouldHaveOtherResponsesWithStatus)
-	s.Step(`^I should have(.*) other responses with header "([^"]*): ([^"]*)"$`, l.iShouldHaveOtherResponsesWithHeader)
-	s.Step(`^I should have(.*) other responses with headers$`, l.iShouldHaveOtherResponsesWithHeaders)
-	s.Step(`^I should have(.*) other responses with body$`, l.iShouldHaveOtherResponsesWithBody)
-	s.Step(`^I should have(.*) other responses with body, that contains$`, l.iShouldHaveOtherResponsesWithBodyThatContains)
-	s.Step(`^I should have(.*) other responses with body from file$`, l.iShouldHaveOtherResponsesWithBodyFromFile)
-	s.Step(`^I should have(.*) other responses with body, that matches JSON$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSON)
-	s.Step(`^I should have(.*) other responses with body, that matches JSON from file$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSONFromFile)
-	s.Step(`^I should have(.*) other responses with body, that matches JSON paths$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSONPaths)
+	if err := l.RegisterStepsWithPatterns(s, nil); err != nil {
+		failScenarios(s, err)
+	}
+}
+
+// RegisterStepsWithPatterns adds HTTP-related steps to godog scenario context with custom expressions
+// that take precedence over StepPatterns, e.g. to change wording of individual steps to match house style.
+//
+// Steps are registered once per scenario context, so RegisterSteps has no effect after RegisterStepsWithPatterns.
+// Steps are not registered and error is returned if StepPatterns or custom expressions are invalid.
+// Handlers of StepDefinitions can be used to add aliases of steps that keep default wording.
+func (l *LocalClient) RegisterStepsWithPatterns(s *godog.ScenarioContext, custom StepPatterns) error {
+	if err := validatePatterns(l.StepPatterns, custom); err != nil {
+		return err
+	}
+
+	if !l.registered.add(s) {
+		return nil
+	}
+
+	l.steps(withPatterns(s, custom))
 
+	s.Before(withTagHeaders(l, func() map[string]string { return l.TagHeaders }))
+	s.Before(l.warningsTag)
+	s.Before(l.StepUsage.beforeScenario)
+	s.Before(l.Deprecations.beforeScenario)
+	s.Before(featureDir)
+	s.Before(l.beforeIsolatedScenario)
+	s.StepContext().Before(beforeStep)
 	s.After(l.afterScenario)
+	s.After(removeTemporaryFiles)
+
+	return nil
+}
+
+// StepDefinitions returns expressions and handlers of steps in order of registration.
+func (l *LocalClient) StepDefinitions() []StepDefinition {
+	var c stepCollector
+
+	l.steps(&c)
+
+	return c
+}
+
+func (l *LocalClient) steps(s stepContext) {
+	l.step(s, `^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`, l.iRequestWithMethodAndURI)
+	l.step(s, `^I request(.*) HTTP endpoint "([^"]*)" with method "([^"]*)" and URI (.*)$`, l.iRequestNamedWithMethodAndURI)
+	l.step(s, `^I send(.*) saved request "([^"]*)"$`, l.iSendSavedRequest)
+	l.step(s, `^I request(.*) HTTP endpoint with body$`, l.iRequestWithBody)
+	l.step(s, `^I request(.*) HTTP endpoint with protobuf "([^"]*)" body$`, l.iRequestWithProtobufBody)
+	l.step(s, `^I request(.*) HTTP endpoint with body from file$`, l.iRequestWithBodyFromFile)
+	l.step(s, `^I request(.*) HTTP endpoint with JSON body from table$`, l.iRequestWithJSONBodyFromTable)
+	l.step(s, `^I request(.*) HTTP endpoint with body from fixture "([^"]*)"$`, l.iRequestWithBodyFromFixture)
+	l.step(s, `^I request(.*) HTTP endpoint with body streamed from "([^"]*)"$`, l.iRequestWithBodyStreamedFrom)
+	l.step(s, `^I request(.*) HTTP endpoint with header "([^"]*): ([^"]*)"$`, l.iRequestWithHeader)
+	l.step(s, `^I request(.*) HTTP endpoint with cookie "([^"]*): ([^"]*)"$`, l.iRequestWithCookie)
+	l.step(s, `^I request(.*) HTTP endpoint with trailer "([^"]*): ([^"]*)"$`, l.iRequestWithTrailer)
+	l.step(s, `^I request(.*) HTTP endpoint with bearer token "([^"]*)"$`, l.iRequestWithBearerToken)
+	l.step(s, `^all requests use bearer token "([^"]*)"$`, l.allRequestsUseBearerToken)
+	l.step(s, `^I request(.*) HTTP endpoint signed with HMAC-SHA256 using key "([^"]*)" in header "([^"]*)"$`,
+		l.iRequestSignedWithHMACSHA256)
+
+	l.step(s, `^I request(.*) HTTP endpoint with cookies$`, l.iRequestWithCookies)
+	l.step(s, `^I request(.*) HTTP endpoint with headers$`, l.iRequestWithHeaders)
+	l.step(s, `^I request(.*) HTTP endpoint with query parameters$`, l.iRequestWithQueryParameters)
+	l.step(s, `^I request(.*) HTTP endpoint with urlencoded form data$`, l.iRequestWithFormDataParameters)
+	l.step(s, `^I request(.*) HTTP endpoint with multipart form data$`, l.iRequestWithMultipartFormData)
+
+	l.step(s, `^HTTP service "([^"]*)" is available at "([^"]*)"$`, l.iHTTPServiceIsAvailableAt)
+	l.step(s, `^I enable HTTP debug logging$`, l.iEnableHTTPDebugLogging)
+	l.step(s, `^I reset(.*) HTTP client state$`, l.iResetClientState)
+	l.step(s, `^I follow redirects from(.*) HTTP endpoint$`, l.iFollowRedirects)
+	l.step(s, `^I retry(.*) HTTP request up to (\d+ time[s]?|\S+)$`, l.iRetry)
+	l.step(s, `^I retry(.*) HTTP request up to (\d+ time[s]?|\S+) while response status is "([^"]*)"$`, l.iRetryWhileStatus)
+	l.step(s, `^I retry(.*) HTTP request up to (\d+ time[s]?|\S+) with (jittered )?(constant|exponential) backoff "([^"]*)"(?: while response status is "([^"]*)")?$`,
+		l.iRetryWithBackOff)
+	l.step(s, `^I poll(.*) HTTP endpoint with method "([^"]*)" and URI "([^"]*)" every "([^"]*)" for up to "([^"]*)" until response has status "([^"]*)"$`,
+		l.iPollUntilStatus)
+	l.step(s, `^I concurrently request idempotent(.*) HTTP endpoint$`, l.iRequestWithConcurrency)
+	l.step(s, `^I concurrently request idempotent(.*) HTTP endpoint with (\d+) requests$`, l.iRequestWithConcurrencyLevel)
+	l.step(s, `^I concurrently request idempotent(.*) HTTP endpoint with ramp from (\d+) to (\d+) requests in (\d+) steps$`,
+		l.iRequestWithConcurrencyRamp)
+
+	l.step(s, `^a temporary file "([^"]*)" with content$`, l.iHaveTemporaryFile)
+	l.step(s, `^I request(.*) HTTP endpoint with attachment as field "([^"]*)" and file name "([^"]*)"$`, l.iRequestWithAttachment)
+	l.step(s, `^I request(.*) HTTP endpoint with attachment as field "([^"]*)" from file$`, l.iRequestWithAttachmentFromFile)
+	l.step(s, `^I request(.*) HTTP endpoint with attachment as field "([^"]*)" and file name "([^"]*)" and content type "([^"]*)"$`,
+		l.iRequestWithAttachmentWithContentType)
+	l.step(s, `^I request(.*) HTTP endpoint with attachment as field "([^"]*)" and content type "([^"]*)" from file$`,
+		l.iRequestWithAttachmentWithContentTypeFromFile)
+
+	l.step(s, `^I should have(.*) response with status "([^"]*)"$`, l.iShouldHaveResponseWithStatus)
+	l.step(s, `^I should have(.*) response with header "([^"]*): ([^"]*)"$`, l.iShouldHaveResponseWithHeader)
+	l.step(s, `^I should have(.*) response with headers$`, l.iShouldHaveResponseWithHeaders)
+	l.step(s, `^I should have(.*) response with cookie "([^"]*)"$`, l.iShouldHaveResponseWithCookie)
+	l.step(s, `^I should have(.*) response with cookie "([^"]*)" having (.*)$`, l.iShouldHaveResponseWithCookieHaving)
+	l.step(s, `^I should have(.*) response with parts$`, l.iShouldHaveResponseWithParts)
+	l.step(s, `^I should have(.*) response with trailer "([^"]*): ([^"]*)"$`, l.iShouldHaveResponseWithTrailer)
+
+	l.step(s, `^I should have(.*) response with body from file$`, l.iShouldHaveResponseWithBodyFromFile)
+	l.step(s, `^I should have(.*) response with body from file, (exactly|ignoring extra fields)$`, l.iShouldHaveResponseWithBodyFromFileMatching)
+	l.step(s, `^I should have(.*) response with binary body from file$`, l.iShouldHaveResponseWithBinaryBodyFromFile)
+	l.step(s, `^I should have(.*) response with body$`, l.iShouldHaveResponseWithBody)
+	l.step(s, `^I should have(.*) response with body, ignoring line endings and trailing whitespace$`, l.iShouldHaveResponseWithNormalizedBody)
+	l.step(s, `^I should have(.*) response with body from file, ignoring line endings and trailing whitespace$`,
+		l.iShouldHaveResponseWithNormalizedBodyFromFile)
+	l.step(s, `^I should have(.*) response with body, that contains$`, l.iShouldHaveResponseWithBodyThatContains)
+	l.step(s, `^I should have(.*) response with body containing "(.*)"$`, l.iShouldHaveResponseWithBodyThatContains)
+	l.step(s, `^I should have(.*) response with body not containing$`, l.iShouldHaveResponseWithBodyNotContaining)
+	l.step(s, `^I should have(.*) response with body not containing JSON paths$`, l.iShouldHaveResponseWithBodyNotContainingJSONPaths)
+	l.step(s, `^I should have(.*) response with body, that matches JSON from file$`, l.iShouldHaveResponseWithBodyThatMatchesJSONFromFile)
+	l.step(s, `^I should have(.*) response with body, that matches JSON$`, l.iShouldHaveResponseWithBodyThatMatchesJSON)
+	l.step(s, `^I should have(.*) response with body, that matches JSON ignoring array order$`, l.iShouldHaveResponseWithBodyThatMatchesJSONIgnoringArrayOrder)
+	l.step(s, `^I should have(.*) response with body, that matches JSON paths$`, l.iShouldHaveResponseWithBodyThatMatchesJSONPaths)
+	l.step(s, `^I should have(.*) response with body including JSON$`, l.iShouldHaveResponseWithBodyIncludingJSON)
+	l.step(s, `^I should have(.*) response with CSV body$`, l.iShouldHaveResponseWithCSVBody)
+	l.step(s, `^I should have(.*) response with NDJSON lines$`, l.iShouldHaveResponseWithNDJSONLines)
+	l.step(s, `^I should have(.*) response with protobuf "([^"]*)" body$`, l.iShouldHaveResponseWithProtobufBody)
+	l.step(s, `^I should have(.*) response satisfying expression "(.*)"$`, l.iShouldHaveResponseSatisfyingExpression)
+	l.step(s, `^I should have(.*) response whose body matches its Content-Type$`, l.iShouldHaveResponseWhoseBodyMatchesContentType)
+	l.step(s, `^I should have(.*) response with body matching provider "([^"]*)"$`, l.iShouldHaveResponseWithBodyMatchingProvider)
+	l.step(s, `^I should have(.*) response matching snapshot "([^"]*)"$`, l.iShouldHaveResponseMatchingSnapshot)
+	l.step(s, `^I should have(.*) response compressed with "([^"]*)"$`, l.iShouldHaveResponseCompressedWith)
+	l.step(s, `^I should have(.*) response marked deprecated$`, l.iShouldHaveResponseMarkedDeprecated)
+	l.step(s, `^I should have(.*) response marked deprecated with sunset "([^"]*)"$`, l.iShouldHaveResponseMarkedDeprecatedWithSunset)
+	l.step(s, `^I should have an? "([^"]*)"(.*) response$`, l.iShouldHaveProfileResponse)
+	l.step(s, `^I should have a healthy(.*) response$`, l.iShouldHaveHealthyResponse)
+	l.step(s, `^I should have(.*) server timing "([^"]*)"$`, l.iShouldHaveServerTiming)
+	l.step(s, `^I should have(.*) server timing "([^"]*)" under "([^"]*)"$`, l.iShouldHaveServerTimingUnder)
+	l.step(s, `^I should have(.*) response with header "([^"]*)" under "([^"]*)"$`, l.iShouldHaveResponseWithHeaderUnder)
+
+	l.step(s, `^response of "([^"]*)" should have status "([^"]*)"$`, l.responseOfShouldHaveStatus)
+	l.step(s, `^response of "([^"]*)" should have header "([^"]*): ([^"]*)"$`, l.responseOfShouldHaveHeader)
+	l.step(s, `^response of "([^"]*)" should have body$`, l.responseOfShouldHaveBody)
+
+	l.step(s, `^I store "([^"]*)" from(.*) response body as (\S+)$`, l.iStoreFromResponseBodyAs)
+	l.step(s, `^I use "([^"]*)" from previous(.*) response as (\S+)$`, l.iUseFromPreviousResponseAs)
+	l.step(s, `^failed response assertions are (warnings|errors)$`, l.failedResponseAssertionsAre)
+	l.step(s, `^JSON numbers are compared with tolerance "([^"]*)"$`, l.jsonNumbersAreComparedWithTolerance)
+	l.step(s, `^JSON arrays are compared ignoring order$`, l.jsonArraysAreComparedIgnoringOrder)
+	l.step(s, `^JSON value "([^"]*)" matches any value$`, l.jsonValueMatchesAnyValue)
+	l.step(s, `^(\S+) is a fake ([\w ]+)$`, l.varIsAFake)
+
+	l.step(s, `^I should have(.*) other responses with status "([^"]*)"$`, l.iShouldHaveOtherResponsesWithStatus)
+	l.step(s, `^I should have(.*) other responses with header "([^"]*): ([^"]*)"$`, l.iShouldHaveOtherResponsesWithHeader)
+	l.step(s, `^I should have(.*) other responses with headers$`, l.iShouldHaveOtherResponsesWithHeaders)
+	l.step(s, `^I should have(.*) other responses with body$`, l.iShouldHaveOtherResponsesWithBody)
+	l.step(s, `^I should have(.*) other responses with body, ignoring line endings and trailing whitespace$`,
+		l.iShouldHaveOtherResponsesWithNormalizedBody)
+	l.step(s, `^I should have(.*) other responses with body from file, ignoring line endings and trailing whitespace$`,
+		l.iShouldHaveOtherResponsesWithNormalizedBodyFromFile)
+	l.step(s, `^I should have(.*) other responses with body, that contains$`, l.iShouldHaveOtherResponsesWithBodyThatContains)
+	l.step(s, `^I should have(.*) other responses with body containing "(.*)"$`, l.iShouldHaveOtherResponsesWithBodyThatContains)
+	l.step(s, `^I should have(.*) other responses with body not containing$`, l.iShouldHaveOtherResponsesWithBodyNotContaining)
+	l.step(s, `^I should have(.*) other responses with body not containing JSON paths$`,
+		l.iShouldHaveOtherResponsesWithBodyNotContainingJSONPaths)
+	l.step(s, `^I should have(.*) other responses with body from file$`, l.iShouldHaveOtherResponsesWithBodyFromFile)
+	l.step(s, `^I should have(.*) other responses with body from file, (exactly|ignoring extra fields)$`, l.iShouldHaveOtherResponsesWithBodyFromFileMatching)
+	l.step(s, `^I should have(.*) other responses with binary body from file$`, l.iShouldHaveOtherResponsesWithBinaryBodyFromFile)
+	l.step(s, `^I should have(.*) other responses with body, that matches JSON$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSON)
+	l.step(s, `^I should have(.*) other responses with body, that matches JSON ignoring array order$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSONIgnoringArrayOrder)
+	l.step(s, `^I should have(.*) other responses with body, that matches JSON from file$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSONFromFile)
+	l.step(s, `^I should have(.*) other responses with body, that matches JSON paths$`, l.iShouldHaveOtherResponsesWithBodyThatMatchesJSONPaths)
+	l.step(s, `^I should have(.*) other responses with body including JSON$`, l.iShouldHaveOtherResponsesWithBodyIncludingJSON)
+	l.step(s, `^I should have(.*) other responses with CSV body$`, l.iShouldHaveOtherResponsesWithCSVBody)
+	l.step(s, `^I should have(.*) other responses with NDJSON lines$`, l.iShouldHaveOtherResponsesWithNDJSONLines)
+	l.step(s, `^I should have(.*) other responses with protobuf "([^"]*)" body$`, l.iShouldHaveOtherResponsesWithProtobufBody)
+	l.step(s, `^I should have(.*) other responses satisfying expression "(.*)"$`, l.iShouldHaveOtherResponsesSatisfyingExpression)
+	l.step(s, `^I should have all(.*) responses identical including headers$`, l.iShouldHaveAllResponsesIdentical)
+
+	l.step(s, `^I should have(.*) ramp responses with status "([^"]*)" up to (\d+) concurrent requests$`,
+		l.iShouldHaveRampResponsesWithStatusUpTo)
+	l.step(s, `^I should have(.*) first failed ramp response with status "([^"]*)" at (\d+) or more concurrent requests$`,
+		l.iShouldHaveFirstFailedRampResponseWithStatus)
+	l.step(s, `^I should have(.*) other responses with body matching provider "([^"]*)"$`, l.iShouldHaveOtherResponsesWithBodyMatchingProvider)
 }
 
 func (l *LocalClient) afterScenario(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
 	var errs []string
 
+	l.mu.RLock()
+	services := make(map[string]struct{}, len(l.services))
+
 	for service := range l.services {
+		services[service] = struct{}{}
+	}
+	l.mu.RUnlock()
+
+	for service := range scenarioServices(ctx, l) {
+		services[service] = struct{}{}
+	}
+
+	for service := range services {
 		client, _, err := l.Service(ctx, service)
 		if err != nil {
 			errs = append(errs, service+": "+err.Error())
@@ -265,18 +772,83 @@ func (l *LocalClient) iRequestWithMethodAndURI(ctx context.Context, service, met
 
 	uri = strings.Trim(uri, `"`)
 
-	ctx, rv, err := l.VS.Replace(ctx, []byte(uri))
+	ctx, rv, err := replaceVars(ctx, l.VS, []byte(uri))
 	if err != nil {
 		return ctx, fmt.Errorf("failed to replace vars in URI: %w", err)
 	}
 
+	snapshotNamed(ctx, service, c)
 	c.Reset()
+	recorderOf(c).reset()
 	c.WithMethod(method)
 	c.WithURI(string(rv))
 
+	if l.RetryConnectionReset {
+		recorderOf(c).retryConnectionReset()
+	}
+
+	l.mu.RLock()
+	cfg := l.configs[serviceName(service)]
+	l.mu.RUnlock()
+
+	if ctx, err = cfg.apply(ctx, l, c); err != nil {
+		return ctx, err
+	}
+
+	for k, v := range tagHeaders(ctx, l) {
+		c.WithHeader(k, v)
+	}
+
+	if token, ok := ctx.Value(bearerTokenCtxKey{}).(string); ok {
+		return l.iRequestWithBearerToken(ctx, service, token)
+	}
+
+	return ctx, nil
+}
+
+type bearerTokenCtxKey struct{}
+
+func (l *LocalClient) iResetClientState(ctx context.Context, service string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	snapshotNamed(ctx, service, c)
+	c.Reset()
+	recorderOf(c).reset()
+
+	// Default headers and cookies are shared with base client, so they are replaced instead of being cleared.
+	c.Headers = nil
+	c.Cookies = nil
+
+	return context.WithValue(ctx, bearerTokenCtxKey{}, nil), nil
+}
+
+func (l *LocalClient) allRequestsUseBearerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, bearerTokenCtxKey{}, token)
+}
+
+func (l *LocalClient) iRequestSignedWithHMACSHA256(ctx context.Context, service, key, header string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, rv, err := replaceVars(ctx, l.VS, []byte(key))
+	if err != nil {
+		return ctx, fmt.Errorf("failed to replace vars in signature key: %w", err)
+	}
+
+	recorderOf(c).beforeRequest(signHMACSHA256(rv, header))
+
 	return ctx, nil
 }
 
+func (l *LocalClient) iRequestWithBearerToken(ctx context.Context, service, token string) (context.Context, error) {
+	return l.iRequestWithHeader(ctx, service, "Authorization", "Bearer "+token)
+}
+
 // LoadBodyFromFile loads body from file and replaces vars in it.
 //
 // Deprecated: use github.com/godogx/vars.(*Steps).ReplaceFile.
@@ -343,7 +915,26 @@ func (l *LocalClient) iRequestWithBodyFromFile(ctx context.Context, service stri
 		return ctx, err
 	}
 
-	ctx, body, err := l.VS.ReplaceFile(ctx, filePath)
+	ctx, filePath, err = resolveFilePath(ctx, l.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	if l.StreamFileBodyThreshold > 0 {
+		fi, err := os.Stat(filePath)
+		if err != nil {
+			return ctx, err
+		}
+
+		if fi.Size() >= l.StreamFileBodyThreshold {
+			c.WithBody(nil)
+			recorderOf(c).beforeRequest(streamFile(filePath, fi.Size()))
+
+			return ctx, nil
+		}
+	}
+
+	ctx, body, err := replaceFileVars(ctx, l.VS, filePath)
 	if err == nil {
 		c.WithBody(body)
 	}
@@ -351,14 +942,32 @@ func (l *LocalClient) iRequestWithBodyFromFile(ctx context.Context, service stri
 	return ctx, err
 }
 
-func (l *LocalClient) iRequestWithBody(ctx context.Context, service string, bodyDoc string) (context.Context, error) {
+// streamFile returns a function to send file contents as request body without reading it into memory.
+func streamFile(filePath string, size int64) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return os.Open(filePath) //nolint:gosec // File inclusion via variable during tests.
+		}
+
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+
+		req.Body = body
+		req.ContentLength = size
+
+		return nil
+	}
+}
+
+func (l *LocalClient) iRequestWithBodyFromFixture(ctx context.Context, service, name string) (context.Context, error) {
 	c, ctx, err := l.Service(ctx, service)
 	if err != nil {
 		return ctx, err
 	}
 
-	ctx, body, err := l.VS.Replace(ctx, []byte(bodyDoc))
-
+	ctx, body, err := l.Fixtures.body(ctx, name)
 	if err == nil {
 		c.WithBody(body)
 	}
@@ -366,40 +975,99 @@ func (l *LocalClient) iRequestWithBody(ctx context.Context, service string, body
 	return ctx, err
 }
 
-func (l *LocalClient) iRequestWithHeader(ctx context.Context, service, key, value string) (context.Context, error) {
-	c, ctx, err := l.Service(ctx, service)
-	if err != nil {
-		return ctx, err
-	}
+// resolveFilePath replaces vars in file path template and checks that file exists.
+//
+// Scenario outline placeholders (e.g. `_testdata/<case>/expected.json`) are substituted by godog,
+// vars (e.g. `_testdata/$case/expected.json`) are replaced with their current values.
+func resolveFilePath(ctx context.Context, vs *vars.Steps, filePath string) (context.Context, string, error) {
+	tpl := strings.TrimSpace(filePath)
 
-	ctx, rv, err := l.VS.Replace(ctx, []byte(value))
+	ctx, rv, err := vs.Replace(ctx, []byte(tpl))
 	if err != nil {
-		return ctx, fmt.Errorf("failed to replace vars in header %s: %w", key, err)
+		return ctx, "", fmt.Errorf("failed to replace vars in file path %s: %w", tpl, err)
 	}
 
-	c.WithHeader(key, string(rv))
+	filePath = string(rv)
 
-	return ctx, nil
+	if _, err := os.Stat(filePath); err != nil {
+		if filePath != tpl {
+			return ctx, "", fmt.Errorf("%w %s (resolved from %s): %v", ErrMissingFile, filePath, tpl, err) //nolint:errorlint
+		}
+
+		return ctx, "", fmt.Errorf("%w %s: %v", ErrMissingFile, filePath, err) //nolint:errorlint
+	}
+
+	return ctx, filePath, nil
 }
 
-func mapOfData(data *godog.Table) (url.Values, error) {
-	if len(data.Rows[0].Cells) != 2 {
-		return nil, fmt.Errorf("%w, 2 expected, %d received",
-			errInvalidNumberOfColumns, len(data.Rows[0].Cells))
+func (l *LocalClient) iRequestWithBody(ctx context.Context, service string, bodyDoc string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
 	}
 
-	res := make(url.Values, len(data.Rows))
+	ctx, body, err := replaceVars(ctx, l.VS, []byte(bodyDoc))
 
-	for _, r := range data.Rows {
-		k := r.Cells[0].Value
-		v := r.Cells[1].Value
-		res[k] = append(res[k], v)
+	if err == nil {
+		c.WithBody(body)
 	}
 
-	return res, nil
+	return ctx, err
 }
 
-func (l *LocalClient) tableSetup(
+func (l *LocalClient) iRequestWithJSONBodyFromTable(ctx context.Context, service string, data *godog.Table) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	body, err := jsonFromTable(data)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, body, err = replaceVars(ctx, l.VS, body)
+	if err == nil {
+		c.WithBody(body)
+	}
+
+	return ctx, err
+}
+
+func (l *LocalClient) iRequestWithHeader(ctx context.Context, service, key, value string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, rv, err := replaceVars(ctx, l.VS, []byte(value))
+	if err != nil {
+		return ctx, fmt.Errorf("failed to replace vars in header %s: %w", key, err)
+	}
+
+	c.WithHeader(key, string(rv))
+
+	return ctx, nil
+}
+
+func mapOfData(data *godog.Table) (url.Values, error) {
+	if len(data.Rows[0].Cells) != 2 {
+		return nil, fmt.Errorf("%w, 2 expected, %d received",
+			ErrInvalidNumberOfColumns, len(data.Rows[0].Cells))
+	}
+
+	res := make(url.Values, len(data.Rows))
+
+	for _, r := range data.Rows {
+		k := r.Cells[0].Value
+		v := r.Cells[1].Value
+		res[k] = append(res[k], v)
+	}
+
+	return res, nil
+}
+
+func (l *LocalClient) tableSetup(
 	ctx context.Context,
 	data *godog.Table,
 	receiverName string,
@@ -416,7 +1084,7 @@ func (l *LocalClient) tableSetup(
 
 	for key, values := range m {
 		for _, value := range values {
-			_, rv, err = l.VS.Replace(ctx, []byte(value))
+			_, rv, err = replaceVars(ctx, l.VS, []byte(value))
 			if err != nil {
 				return ctx, fmt.Errorf("failed to replace vars in %s %s: %w", receiverName, key, err)
 			}
@@ -434,6 +1102,11 @@ func (l *LocalClient) iRequestWithFormDataParameters(ctx context.Context, servic
 		return ctx, err
 	}
 
+	ctx, data, err = l.embedFiles(ctx, data)
+	if err != nil {
+		return ctx, err
+	}
+
 	return l.tableSetup(ctx, data, "form data parameter", c.WithURLEncodedFormDataParam)
 }
 
@@ -461,7 +1134,7 @@ func (l *LocalClient) iRequestWithCookie(ctx context.Context, service, name, val
 		return ctx, err
 	}
 
-	ctx, rv, err := l.VS.Replace(ctx, []byte(value))
+	ctx, rv, err := replaceVars(ctx, l.VS, []byte(value))
 	if err != nil {
 		return ctx, fmt.Errorf("failed to replace vars in cookie %s: %w", name, err)
 	}
@@ -481,12 +1154,16 @@ func (l *LocalClient) iRequestWithCookies(ctx context.Context, service string, d
 }
 
 func (l *LocalClient) iRequestWithAttachment(ctx context.Context, service, fieldName, fileName string, fileContent string) (context.Context, error) {
+	return l.iRequestWithAttachmentWithContentType(ctx, service, fieldName, fileName, "", fileContent)
+}
+
+func (l *LocalClient) iRequestWithAttachmentWithContentType(ctx context.Context, service, fieldName, fileName, partContentType string, fileContent string) (context.Context, error) {
 	c, ctx, err := l.Service(ctx, service)
 	if err != nil {
 		return ctx, err
 	}
 
-	ctx, body, contentType, err := l.appendAttachmentFileIntoBody(ctx, strings.NewReader(fileContent), fieldName, fileName)
+	ctx, body, contentType, err := l.appendAttachmentFileIntoBody(ctx, strings.NewReader(fileContent), fieldName, fileName, partContentType)
 	if err == nil {
 		c.WithBody(body)
 		c.WithContentType(contentType)
@@ -496,18 +1173,27 @@ func (l *LocalClient) iRequestWithAttachment(ctx context.Context, service, field
 }
 
 func (l *LocalClient) iRequestWithAttachmentFromFile(ctx context.Context, service, fieldName string, filePath string) (context.Context, error) {
+	return l.iRequestWithAttachmentWithContentTypeFromFile(ctx, service, fieldName, "", filePath)
+}
+
+func (l *LocalClient) iRequestWithAttachmentWithContentTypeFromFile(ctx context.Context, service, fieldName, partContentType string, filePath string) (context.Context, error) {
 	c, ctx, err := l.Service(ctx, service)
 	if err != nil {
 		return ctx, err
 	}
 
+	ctx, filePath, err = resolveFilePath(ctx, l.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
 	file, err := os.Open(filePath) //nolint: gosec
 	if err != nil {
 		return ctx, err
 	}
 	defer file.Close() //nolint:errcheck
 
-	ctx, body, contentType, err := l.appendAttachmentFileIntoBody(ctx, file, fieldName, filepath.Base(filePath))
+	ctx, body, contentType, err := l.appendAttachmentFileIntoBody(ctx, file, fieldName, filepath.Base(filePath), partContentType)
 	if err == nil {
 		c.WithBody(body)
 		c.WithContentType(contentType)
@@ -516,11 +1202,29 @@ func (l *LocalClient) iRequestWithAttachmentFromFile(ctx context.Context, servic
 	return ctx, err
 }
 
-func (l *LocalClient) appendAttachmentFileIntoBody(ctx context.Context, file io.Reader, fieldName, fileName string) (context.Context, []byte, string, error) {
+// quoteEscaper escapes field and file names in Content-Disposition, same as multipart.Writer.CreateFormFile.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// appendAttachmentFileIntoBody creates multipart body with a file, part content type is
+// application/octet-stream unless partContentType is provided.
+func (l *LocalClient) appendAttachmentFileIntoBody(
+	ctx context.Context,
+	file io.Reader,
+	fieldName, fileName, partContentType string,
+) (context.Context, []byte, string, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
-	part, err := writer.CreateFormFile(fieldName, fileName)
+	if partContentType == "" {
+		partContentType = "application/octet-stream"
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(fieldName), quoteEscaper.Replace(fileName)))
+	h.Set("Content-Type", partContentType)
+
+	part, err := writer.CreatePart(h)
 	if err != nil {
 		return ctx, nil, "", err
 	}
@@ -535,7 +1239,7 @@ func (l *LocalClient) appendAttachmentFileIntoBody(ctx context.Context, file io.
 		return ctx, nil, "", err
 	}
 
-	ctx, resBody, err := l.VS.Replace(ctx, body.Bytes())
+	ctx, resBody, err := replaceVars(ctx, l.VS, body.Bytes())
 	if err != nil {
 		return ctx, nil, "", err
 	}
@@ -546,16 +1250,6 @@ func (l *LocalClient) appendAttachmentFileIntoBody(ctx context.Context, file io.
 const (
 	// Default is the name of default service.
 	Default = "default"
-
-	errUnknownStatusCode      = sentinelError("unknown http status")
-	errNoMockForService       = sentinelError("no mock for service")
-	errUndefinedRequest       = sentinelError("undefined request (missing `receives <METHOD> request` step)")
-	errUndefinedResponse      = sentinelError("undefined response (missing `responds with status <STATUS>` step)")
-	errUnknownService         = sentinelError("unknown service")
-	errUnexpectedExpectations = sentinelError("unexpected existing expectations")
-	errInvalidNumberOfColumns = sentinelError("invalid number of columns")
-	errUnexpectedBody         = sentinelError("unexpected body")
-	errDoesNotContain         = sentinelError("does not contain")
 )
 
 func statusCode(statusOrCode string) (int, error) {
@@ -565,7 +1259,7 @@ func statusCode(statusOrCode string) (int, error) {
 	}
 
 	if code == 0 {
-		return 0, fmt.Errorf("%w: %q", errUnknownStatusCode, statusOrCode)
+		return 0, fmt.Errorf("%w: %q", ErrUnknownStatusCode, statusOrCode)
 	}
 
 	return int(code), nil
@@ -582,20 +1276,15 @@ func (l *LocalClient) iShouldHaveOtherResponsesWithStatus(ctx context.Context, s
 		return ctx, err
 	}
 
-	return ctx, c.ExpectOtherResponsesStatus(code)
+	return l.assertion(ctx, c.ExpectOtherResponsesStatus(code))
 }
 
 // DefaultExposeHTTPDetails instruments context with godog.Attachment items of HTTP transaction.
+//
+// JSON bodies are pretty-printed, values of headers, query parameters and body fields with secret-like names
+// (e.g. Authorization, Cookie, password or token) are redacted.
 func DefaultExposeHTTPDetails(ctx context.Context, d httpmock.HTTPValue) (context.Context, error) {
-	d.Req.Body = io.NopCloser(bytes.NewReader(d.ReqBody))
-
-	if s, ok := d.Req.Body.(io.Seeker); ok {
-		if _, err := s.Seek(0, io.SeekStart); err != nil {
-			return ctx, err
-		}
-	}
-
-	req, err := httputil.DumpRequest(d.Req, true)
+	req, err := dumpRequest(d.Req, d.ReqBody)
 	if err != nil {
 		return ctx, err
 	}
@@ -607,9 +1296,7 @@ func DefaultExposeHTTPDetails(ctx context.Context, d httpmock.HTTPValue) (contex
 	})
 
 	if d.Resp != nil {
-		d.Resp.Body = io.NopCloser(bytes.NewReader(d.RespBody))
-
-		resp, err := httputil.DumpResponse(d.Resp, true)
+		resp, err := dumpResponse(d.Resp, d.RespBody)
 		if err != nil {
 			return ctx, err
 		}
@@ -622,9 +1309,7 @@ func DefaultExposeHTTPDetails(ctx context.Context, d httpmock.HTTPValue) (contex
 	}
 
 	if d.OtherResp != nil {
-		d.OtherResp.Body = io.NopCloser(bytes.NewReader(d.OtherRespBody))
-
-		resp, err := httputil.DumpResponse(d.OtherResp, true)
+		resp, err := dumpResponse(d.OtherResp, d.OtherRespBody)
 		if err != nil {
 			return ctx, err
 		}
@@ -648,6 +1333,13 @@ func DefaultExposeHTTPDetails(ctx context.Context, d httpmock.HTTPValue) (contex
 }
 
 func (l *LocalClient) expectResponse(ctx context.Context, service string, expect func(c *httpmock.Client) error) (context.Context, error) {
+	ctx, err := l.checkResponse(ctx, service, expect)
+
+	return l.assertion(ctx, err)
+}
+
+// checkResponse runs response expectation and exposes HTTP details, failure is always returned as error.
+func (l *LocalClient) checkResponse(ctx context.Context, service string, expect func(c *httpmock.Client) error) (context.Context, error) {
 	c, ctx, err := l.Service(ctx, service)
 	if err != nil {
 		return ctx, err
@@ -661,6 +1353,26 @@ func (l *LocalClient) expectResponse(ctx context.Context, service string, expect
 		ctx, err = l.ExposeHTTPDetails(ctx, d)
 	}
 
+	if l.CurlOutput != nil && d.Req != nil && !d.AlreadyRequested {
+		_, _ = fmt.Fprintln(l.CurlOutput, curlCommand(d.Req, d.ReqBody)) //nolint:errcheck // Best effort logging.
+	}
+
+	if d.Req != nil && !d.AlreadyRequested && debugLogging(ctx) {
+		l.logHTTPDetails(service, d)
+	}
+
+	if !d.AlreadyRequested {
+		l.Deprecations.responseReceived(ctx, service, d)
+	}
+
+	if resets := recorderOf(c).connectionResets(); len(resets) > 0 && !d.AlreadyRequested {
+		ctx = godog.Attach(ctx, godog.Attachment{
+			FileName:  "connection resets",
+			Body:      []byte(strings.Join(resets, "\n")),
+			MediaType: "text/plain",
+		})
+	}
+
 	if expErr != nil {
 		if err == nil {
 			err = expErr
@@ -734,12 +1446,8 @@ func (l *LocalClient) iShouldHaveResponseWithHeaders(ctx context.Context, servic
 }
 
 func (l *LocalClient) iShouldHaveResponseWithBody(ctx context.Context, service, bodyDoc string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
-
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectResponseBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.Assert(ctx, []byte(bodyDoc), received, false))
-		})
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.assertText(ctx, []byte(bodyDoc), received, false)
 	})
 }
 
@@ -749,130 +1457,338 @@ func (l *LocalClient) contains(ctx context.Context, received []byte, bodyDoc str
 		return err
 	}
 
+	if l.normalizesText(false, received) {
+		received, rv = normalizeText(received), normalizeText(rv)
+	}
+
 	s, substr := string(received), string(rv)
 	if !strings.Contains(s, substr) {
-		return augmentBodyErr(ctx, fmt.Errorf("%w %q in %q", errDoesNotContain, substr, s))
+		return augmentBodyErr(ctx, fmt.Errorf("%w %q in %q", ErrDoesNotContain, substr, s))
 	}
 
 	return nil
 }
 
 func (l *LocalClient) iShouldHaveResponseWithBodyThatContains(ctx context.Context, service, bodyDoc string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
-
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectResponseBodyCallback(func(received []byte) error {
-			return l.contains(ctx, received, bodyDoc)
-		})
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.contains(ctx, received, bodyDoc)
 	})
 }
 
 func (l *LocalClient) iShouldHaveOtherResponsesWithBodyThatContains(ctx context.Context, service, bodyDoc string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.contains(ctx, received, bodyDoc)
+	})
+}
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
-			return l.contains(ctx, received, bodyDoc)
+func (l *LocalClient) iShouldHaveResponseWithBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+	return l.expectBodyFromFile(ctx, service, false, filePath, false)
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyFromFileMatching(ctx context.Context, service, mode, filePath string) (context.Context, error) {
+	return l.expectBodyFromFile(ctx, service, false, filePath, mode == ignoringExtraFields)
+}
+
+// expectBodyFromFile checks response body with JSON5 file contents, variables in file are replaced.
+func (l *LocalClient) expectBodyFromFile(ctx context.Context, service string, other bool, filePath string, ignoreAddedJSONFields bool) (context.Context, error) {
+	ctx, filePath, err := resolveFilePath(ctx, l.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	return l.expectBody(ctx, service, other, func(ctx context.Context, received []byte) error {
+		return l.assertFile(ctx, filePath, received, ignoreAddedJSONFields, false)
+	})
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyIncludingJSON(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, l.includesJSON(bodyDoc))
+}
+
+// includesJSON checks that received JSON has all fields and array elements of expected JSON.
+func (l *LocalClient) includesJSON(bodyDoc string) func(ctx context.Context, received []byte) error {
+	return func(ctx context.Context, received []byte) error {
+		ctx, expected, err := l.VS.Replace(ctx, []byte(bodyDoc))
+		if err != nil {
+			return err
+		}
+
+		ignoreDiff := assertjson.IgnoreDiff
+		if l.VS != nil {
+			ignoreDiff = l.VS.JSONComparer.IgnoreDiff
+		}
+
+		ctx, v := l.VS.Vars(ctx)
+		projected, _ := includedJSON(expected, received, func(s string) bool {
+			return (ignoreDiff != "" && s == ignoreDiff) || v.IsVar(s)
 		})
+
+		return augmentBodyErr(l.VS.Assert(ctx, expected, projected, false))
+	}
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSON(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return augmentBodyErr(l.assertJSON(ctx, []byte(bodyDoc), received, true))
 	})
 }
 
-func (l *LocalClient) iShouldHaveResponseWithBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+// expectBody checks response body, all of concurrent responses are checked with isolated variables.
+func (l *LocalClient) expectBody(
+	ctx context.Context,
+	service string,
+	other bool,
+	check func(ctx context.Context, received []byte) error,
+) (context.Context, error) {
 	ctx = l.VS.PrepareContext(ctx)
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
+	var failed []byte
+
+	checkBody := func(ctx context.Context, received []byte) error {
+		err := check(ctx, received)
+		if err != nil {
+			failed = received
+		}
+
+		return err
+	}
+
+	group := l.idempotencyGrouping()
+
+	ctx, err := l.checkResponse(ctx, service, func(c *httpmock.Client) error {
+		if group != nil && recorderOf(c).concurrent() {
+			// Responses may have the same status, so that other responses are not detected by status code.
+			return c.ExpectResponseBodyCallback(func(_ []byte) error {
+				one, others, err := groupedBodies(c, group)
+				if err != nil {
+					return err
+				}
+
+				bodies := one
+
+				if other {
+					if len(others) == 0 {
+						return ErrNoOtherResponses
+					}
+
+					bodies = others
+				}
+
+				return l.checkBodies(ctx, bodies, bodies[0], checkBody)
+			})
+		}
+
+		if other {
+			return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
+				return l.checkBodies(ctx, concurrentBodies(c, c.Details().OtherResp), received, checkBody)
+			})
+		}
+
 		return c.ExpectResponseBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.AssertFile(ctx, filePath, received, false))
+			return l.checkBodies(ctx, concurrentBodies(c, c.Details().Resp), received, checkBody)
 		})
 	})
+
+	if err != nil && failed != nil {
+		ctx, err = l.bodyMismatch(ctx, failed, err)
+	}
+
+	return l.assertion(ctx, err)
 }
 
-func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSON(ctx context.Context, service, bodyDoc string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
+// bodyMismatch attaches received body to the scenario and limits length of error message.
+func (l *LocalClient) bodyMismatch(ctx context.Context, received []byte, err error) (context.Context, error) {
+	mediaType := "text/plain"
+	if json.Valid(received) {
+		mediaType = "application/json"
+	} else if !utf8.Valid(received) {
+		mediaType = "application/octet-stream"
+	}
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectResponseBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.Assert(ctx, []byte(bodyDoc), received, true))
+	ctx = godog.Attach(ctx, godog.Attachment{
+		Body:      received,
+		FileName:  "received body",
+		MediaType: mediaType,
+	})
+
+	if l.MaxDiffLength > 0 && len(err.Error()) > l.MaxDiffLength {
+		err = truncatedError{err: err, maxLength: l.MaxDiffLength}
+	}
+
+	return ctx, err
+}
+
+// truncatedError limits length of error message.
+type truncatedError struct {
+	err       error
+	maxLength int
+}
+
+// Error returns truncated error message.
+func (e truncatedError) Error() string {
+	msg := e.err.Error()
+	n := e.maxLength
+
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+
+	return fmt.Sprintf("%s...\n(%d bytes truncated, full received body is attached)", msg[:n], len(msg)-n)
+}
+
+// Unwrap returns original error.
+func (e truncatedError) Unwrap() error {
+	return e.err
+}
+
+// expectResponseDetails checks details of HTTP transaction, request is sent if it was not yet.
+func (l *LocalClient) expectResponseDetails(ctx context.Context, service string, check func(d httpmock.HTTPValue) error) (context.Context, error) {
+	return l.checkResponse(ctx, service, func(c *httpmock.Client) error {
+		checked := false
+
+		// Body callback is used to have check as a retry condition, it is not invoked for empty body.
+		err := c.ExpectResponseBodyCallback(func(_ []byte) error {
+			checked = true
+
+			return check(c.Details())
 		})
+		if err != nil || checked {
+			return err
+		}
+
+		return check(c.Details())
 	})
 }
 
-func augmentBodyErr(_ context.Context, err error) error {
+func (l *LocalClient) iUseFromPreviousResponseAs(ctx context.Context, path, service, name string) (context.Context, error) {
+	ctx = l.VS.PrepareContext(ctx)
+
+	c, ctx, err := l.Service(ctx, service)
 	if err != nil {
-		return fmt.Errorf("%w %s", errUnexpectedBody, err.Error())
+		return ctx, err
 	}
 
-	return nil
+	e, found := recorderOf(c).latest()
+	if !found {
+		return ctx, fmt.Errorf("%w: no previous request of %s", ErrNoResponse, serviceName(service))
+	}
+
+	val, err := jsonPathValue(e.respBody, path)
+	if err != nil {
+		return ctx, err
+	}
+
+	_, v := l.VS.Vars(ctx)
+	v.Set(name, val)
+
+	return ctx, nil
 }
 
-func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSONPaths(ctx context.Context, service string, jsonPaths *godog.Table) (context.Context, error) {
+func (l *LocalClient) iStoreFromResponseBodyAs(ctx context.Context, path, service, name string) (context.Context, error) {
 	ctx = l.VS.PrepareContext(ctx)
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectResponseBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.AssertJSONPaths(ctx, jsonPaths, received, true))
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	return l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		return l.checkBodies(ctx, concurrentBodies(c, d.Resp), d.RespBody, func(ctx context.Context, received []byte) error {
+			val, err := jsonPathValue(received, path)
+			if err != nil {
+				return err
+			}
+
+			_, v := l.VS.Vars(ctx)
+			v.Set(name, val)
+
+			return nil
 		})
 	})
 }
 
-func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSONFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
+func (l *LocalClient) varIsAFake(ctx context.Context, name, kind string) (context.Context, error) {
+	val, err := fakeValue(kind)
+	if err != nil {
+		return ctx, err
+	}
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectResponseBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.AssertFile(ctx, filePath, received, true))
-		})
+	ctx, v := l.VS.Vars(ctx)
+	v.Set(name, val)
+
+	return ctx, nil
+}
+
+// jsonPathValue reads a value from JSON payload.
+func jsonPathValue(data []byte, path string) (interface{}, error) {
+	var rcv interface{}
+	if err := json.Unmarshal(data, &rcv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal received value: %w", err)
+	}
+
+	val, err := jsonpath.Read(rcv, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jsonpath %s: %w", path, err)
+	}
+
+	// Integer numbers are stored as int64, same as with variables collected by JSON comparison.
+	if f, ok := val.(float64); ok && f == float64(int64(f)) {
+		val = int64(f)
+	}
+
+	return val, nil
+}
+
+func augmentBodyErr(_ context.Context, err error) error {
+	if err != nil {
+		return unexpectedBodyError{err: err}
+	}
+
+	return nil
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSONPaths(ctx context.Context, service string, jsonPaths *godog.Table) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return augmentBodyErr(ctx, l.assertJSONPaths(ctx, jsonPaths, received, true))
 	})
 }
 
-func (l *LocalClient) iShouldHaveOtherResponsesWithBody(ctx context.Context, service, bodyDoc string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
+func (l *LocalClient) iShouldHaveResponseWithBodyThatMatchesJSONFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+	return l.expectBodyFromFile(ctx, service, false, filePath, true)
+}
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.Assert(ctx, []byte(bodyDoc), received, false))
-		})
+func (l *LocalClient) iShouldHaveOtherResponsesWithBody(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.assertText(ctx, []byte(bodyDoc), received, false)
 	})
 }
 
 func (l *LocalClient) iShouldHaveOtherResponsesWithBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
+	return l.expectBodyFromFile(ctx, service, true, filePath, false)
+}
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.AssertFile(ctx, filePath, received, false))
-		})
-	})
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyFromFileMatching(ctx context.Context, service, mode, filePath string) (context.Context, error) {
+	return l.expectBodyFromFile(ctx, service, true, filePath, mode == ignoringExtraFields)
 }
 
-func (l *LocalClient) iShouldHaveOtherResponsesWithBodyThatMatchesJSON(ctx context.Context, service, bodyDoc string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyIncludingJSON(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, l.includesJSON(bodyDoc))
+}
 
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.Assert(ctx, []byte(bodyDoc), received, true))
-		})
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyThatMatchesJSON(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return augmentBodyErr(l.assertJSON(ctx, []byte(bodyDoc), received, true))
 	})
 }
 
 func (l *LocalClient) iShouldHaveOtherResponsesWithBodyThatMatchesJSONPaths(ctx context.Context, service string, jsonPaths *godog.Table) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
-
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.AssertJSONPaths(ctx, jsonPaths, received, true))
-		})
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return augmentBodyErr(ctx, l.assertJSONPaths(ctx, jsonPaths, received, true))
 	})
 }
 
 func (l *LocalClient) iShouldHaveOtherResponsesWithBodyThatMatchesJSONFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
-	ctx = l.VS.PrepareContext(ctx)
-
-	return l.expectResponse(ctx, service, func(c *httpmock.Client) error {
-		return c.ExpectOtherResponsesBodyCallback(func(received []byte) error {
-			return augmentBodyErr(l.VS.AssertFile(ctx, filePath, received, false))
-		})
-	})
+	return l.expectBodyFromFile(ctx, service, true, filePath, true)
 }
 
 func (l *LocalClient) iFollowRedirects(ctx context.Context, service string) (context.Context, error) {
@@ -892,7 +1808,11 @@ func (l *LocalClient) retrier(ctx context.Context, maxElapsed time.Duration) (co
 	}
 
 	eb := backoff.NewExponentialBackOff()
-	eb.MaxElapsedTime = maxElapsed
+	eb.MaxElapsedTime = 0
+
+	if maxElapsed > 0 {
+		eb.MaxElapsedTime = maxElapsed
+	}
 
 	if maxElapsed > 0 {
 		start := time.Now()
@@ -919,10 +1839,84 @@ func (l *LocalClient) iRetry(ctx context.Context, service string, tries string)
 		return ctx, err
 	}
 
+	ctx, b, err := l.retryBackOff(ctx, tries)
+	if err != nil {
+		return ctx, err
+	}
+
+	c.AllowRetries(b)
+
+	return ctx, nil
+}
+
+func (l *LocalClient) iRetryWhileStatus(ctx context.Context, service, tries, statuses string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, b, err := l.retryBackOff(ctx, tries)
+	if err != nil {
+		return ctx, err
+	}
+
+	if b, err = retryWhileStatus(c, b, statuses); err != nil {
+		return ctx, err
+	}
+
+	c.AllowRetries(b)
+
+	return ctx, nil
+}
+
+// retryWhileStatus limits retries to attempts that failed with one of comma-separated statuses.
+func retryWhileStatus(c *httpmock.Client, b httpmock.RetryBackOff, statuses string) (httpmock.RetryBackOff, error) {
+	retryable := make(map[int]bool)
+
+	for _, s := range strings.Split(statuses, ",") {
+		code, err := statusCode(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+
+		retryable[code] = true
+	}
+
+	rt := recorderOf(c)
+
+	// Failed attempt is only retried if all its responses have one of retryable statuses.
+	return httpmock.RetryBackOffFunc(func() time.Duration {
+		last := rt.last()
+		if len(last) == 0 {
+			return backoff.Stop
+		}
+
+		for _, e := range last {
+			if e.resp == nil || !retryable[e.resp.StatusCode] {
+				return backoff.Stop
+			}
+		}
+
+		return b.NextBackOff()
+	}), nil
+}
+
+// retryBackOff creates retry strategy for a limit of tries ("5 times") or elapsed time ("10s").
+func (l *LocalClient) retryBackOff(ctx context.Context, tries string) (context.Context, httpmock.RetryBackOff, error) {
+	return limitedBackOff(ctx, tries, l.retrier)
+}
+
+// limitedBackOff applies a limit of tries ("5 times") or elapsed time ("10s") to retry strategy.
+func limitedBackOff(
+	ctx context.Context,
+	tries string,
+	retrier func(ctx context.Context, maxElapsed time.Duration) (context.Context, httpmock.RetryBackOff),
+) (context.Context, httpmock.RetryBackOff, error) {
 	tries = strings.TrimSuffix(strings.TrimSuffix(tries, " times"), " time")
 	if maxTries, err := strconv.Atoi(tries); err == nil && maxTries > 0 {
-		ctx, eb := l.RetryBackOff(ctx, -1)
-		b := httpmock.RetryBackOffFunc(func() time.Duration {
+		ctx, eb := retrier(ctx, -1)
+
+		return ctx, httpmock.RetryBackOffFunc(func() time.Duration {
 			maxTries--
 
 			if maxTries <= 0 {
@@ -930,36 +1924,61 @@ func (l *LocalClient) iRetry(ctx context.Context, service string, tries string)
 			}
 
 			return eb.NextBackOff()
-		})
-
-		c.AllowRetries(b)
-
-		return ctx, nil
+		}), nil
 	}
 
 	dur, err := time.ParseDuration(tries)
 	if err != nil {
-		return ctx, fmt.Errorf("parsing retry limit: %w", err)
+		return ctx, nil, fmt.Errorf("parsing retry limit: %w", err)
 	}
 
-	ctx, eb := l.retrier(ctx, dur)
+	ctx, eb := retrier(ctx, dur)
 
-	c.AllowRetries(eb)
+	return ctx, eb, nil
+}
+
+func (l *LocalClient) iRequestWithConcurrency(ctx context.Context, service string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	concurrently(c, 0)
 
 	return ctx, nil
 }
 
-func (l *LocalClient) iRequestWithConcurrency(ctx context.Context, service string) (context.Context, error) {
+func (l *LocalClient) iRequestWithConcurrencyLevel(ctx context.Context, service string, n int) (context.Context, error) {
 	c, ctx, err := l.Service(ctx, service)
 	if err != nil {
 		return ctx, err
 	}
 
-	c.Concurrently()
+	if n < 1 {
+		return ctx, fmt.Errorf("%w: number of concurrent requests %d", ErrInvalidValue, n)
+	}
+
+	concurrently(c, n)
 
 	return ctx, nil
 }
 
+// concurrently enables concurrent requests, ConcurrencyLevel of client is used if n is 0.
+//
+// ConcurrencyLevel is kept intact, so that following requests of scenario are not affected.
+func concurrently(c *httpmock.Client, n int) {
+	level := c.ConcurrencyLevel
+
+	if n > 0 {
+		c.ConcurrencyLevel = n
+	}
+
+	c.Concurrently()
+	recorderOf(c).setConcurrency(c.ConcurrencyLevel)
+
+	c.ConcurrencyLevel = level
+}
+
 func (l *LocalClient) makeClient(baseURL string) *httpmock.Client {
 	c := httpmock.NewClient(baseURL)
 
@@ -976,9 +1995,12 @@ func (l *LocalClient) SetBaseURL(baseURL string, service string) error {
 		service = Default
 	}
 
+	l.mu.RLock()
 	s, ok := l.services[service]
+	l.mu.RUnlock()
+
 	if !ok {
-		return fmt.Errorf("%w: %s", errUnknownService, service)
+		return fmt.Errorf("%w: %s", ErrUnknownService, service)
 	}
 
 	s.SetBaseURL(baseURL)
@@ -986,24 +2008,107 @@ func (l *LocalClient) SetBaseURL(baseURL string, service string) error {
 	return nil
 }
 
+func (l *LocalClient) iHTTPServiceIsAvailableAt(ctx context.Context, service, baseURL string) (context.Context, error) {
+	ctx, rv, err := replaceVars(ctx, l.VS, []byte(baseURL))
+	if err != nil {
+		return ctx, fmt.Errorf("failed to replace vars in base URL: %w", err)
+	}
+
+	baseURL = string(rv)
+
+	if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return ctx, fmt.Errorf("%w: base URL %q", ErrInvalidValue, baseURL)
+	}
+
+	// Service is only available in current scenario, registered service with the same name is not changed.
+	services := map[string]*httpmock.Client{}
+
+	for name, c := range scenarioServices(ctx, l) {
+		services[name] = c
+	}
+
+	services[service] = l.makeClient(baseURL)
+
+	return context.WithValue(ctx, scenarioServicesCtxKey{l: l}, services), nil
+}
+
+type scenarioServicesCtxKey struct {
+	l *LocalClient
+}
+
+// scenarioServices returns clients of services registered with a step in current scenario.
+func scenarioServices(ctx context.Context, l *LocalClient) map[string]*httpmock.Client {
+	services, _ := ctx.Value(scenarioServicesCtxKey{l: l}).(map[string]*httpmock.Client) //nolint:errcheck // Nil map is empty.
+
+	return services
+}
+
+// Reset discards state of service clients, step registrations and statistics of StepUsage and Deprecations.
+//
+// It can be used between runs of test suites that share LocalClient instance.
+// Services, their configuration and other settings are kept.
+func (l *LocalClient) Reset() {
+	l.mu.Lock()
+	for _, c := range l.services {
+		c.Reset()
+	}
+	l.mu.Unlock()
+
+	l.registered.reset()
+	l.StepUsage.reset()
+	l.Deprecations.reset()
+}
+
 // Service returns named service client or fails for undefined service.
 func (l *LocalClient) Service(ctx context.Context, service string) (*httpmock.Client, context.Context, error) {
-	service = strings.Trim(service, `" `)
+	service = serviceName(service)
+	usesService(ctx, service)
 
-	if service == "" {
-		service = Default
+	c, found := scenarioServices(ctx, l)[service]
+
+	if !found {
+		l.mu.RLock()
+		c, found = l.services[service]
+		l.mu.RUnlock()
 	}
 
-	c, found := l.services[service]
 	if !found {
-		return nil, ctx, fmt.Errorf("%w: %s", errUnknownService, service)
+		return nil, ctx, fmt.Errorf("%w: %s", ErrUnknownService, service)
 	}
 
+	_, forked := ctx.Value(c).(*httpmock.Client)
+
 	ctx, c = c.Fork(ctx)
 
+	// Responses of a forked client are recorded to check all of concurrent responses.
+	if !forked {
+		rt := newRecordingTransport(c.Transport)
+		rt.service = service
+
+		l.mu.RLock()
+		rt.observers = l.observers
+		l.mu.RUnlock()
+
+		c.Transport = rt
+	}
+
 	return c, ctx, nil
 }
 
+// serviceName normalizes service name captured by a step.
+func serviceName(service string) string {
+	service = strings.Trim(service, `" `)
+
+	if service == "" {
+		return Default
+	}
+
+	return service
+}
+
+// ignoringExtraFields is a match mode of body assertion that allows additional fields in received JSON.
+const ignoringExtraFields = "ignoring extra fields"
+
 var statusMap = map[string]int{}
 
 //nolint:gochecknoinits // Init is better than extra runtime complexity to lock the statuses.
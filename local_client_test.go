@@ -2,17 +2,45 @@ package httpsteps_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/bool64/httpmock"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/cucumber/godog"
 	httpsteps "github.com/godogx/httpsteps"
+	"github.com/godogx/vars"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func TestLocal_RegisterSteps(t *testing.T) {
@@ -246,6 +274,22 @@ func TestLocal_RegisterSteps_dynamic(t *testing.T) {
 
 func TestLocal_RegisterSteps_AttachmentFile(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/file-content-type" {
+			require.NoError(t, r.ParseMultipartForm(10<<20))
+
+			_, header, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			_, err = w.Write([]byte(`{"fileName":"` + header.Filename + `","contentType":"` + header.Header.Get("Content-Type") + `"}`))
+			require.NoError(t, err)
+
+			return
+		}
+
 		if r.URL.Path == "/file-attached" {
 			// Maximum upload of 10 MB files
 			require.NoError(t, r.ParseMultipartForm(10<<20))
@@ -372,14 +416,25 @@ func TestLocal_RegisterSteps_tableSetup(t *testing.T) {
 		},
 	})
 
+	mock.Expect(httpmock.Expectation{
+		Method:     http.MethodPost,
+		RequestURI: "/json-table",
+		RequestBody: []byte(`{"comment":null,"items":[{"id":123},{"id":2,"note":"quoted"}],"links":{"urn:self":"/users/1"},` +
+			`"user":{"active":true,"name":"John Doe","tags":["a"],"zip":"01234"}}`),
+	})
+
 	local := httpsteps.NewLocalClient(srvURL)
 	local.AddService("some-service", srvURL)
 
+	vs := &vars.Steps{}
+	local.VS = vs
+
 	out := bytes.NewBuffer(nil)
 
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(s *godog.ScenarioContext) {
 			local.RegisterSteps(s)
+			vs.Register(s)
 		},
 		Options: &godog.Options{
 			Output:   out,
@@ -393,4 +448,3504 @@ func TestLocal_RegisterSteps_tableSetup(t *testing.T) {
 	if !assert.Equal(t, 0, suite.Run()) {
 		fmt.Println(out.String())
 	}
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLocal_RegisterSteps_outlineFiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"greeting":"` + r.URL.Path[1:] + `","name":"` + r.URL.Query().Get("name") + `"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Outline.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 passed, 1 failed")
+	assert.Contains(t, out.String(), "missing file _testdata/outline/missing.json")
+}
+
+func TestLocal_RegisterSteps_generators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		_, err = w.Write([]byte(`{"query":{"id":"` + r.URL.Query().Get("id") + `"},` +
+			`"year":"` + r.Header.Get("X-Year") + `","body":` + string(body) + `}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	collected := map[string]interface{}{}
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			s.After(func(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
+				ctx, v := vs.Vars(ctx)
+
+				for k, val := range v.GetAll() {
+					collected[k] = val
+				}
+
+				return ctx, nil
+			})
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Strict: true,
+			Paths:  []string{"_testdata/Generators.feature"},
+		},
+	}
+
+	require.Equal(t, 0, suite.Run())
+	assert.Contains(t, collected["$email"], "@example.")
+	assert.Len(t, collected["$id"], 36)
+	assert.InDelta(t, time.Now().Unix(), collected["$ts"], 5)
+	assert.Equal(t, time.Now().Format("2006"), collected["$year"])
+	assert.Equal(t, time.Now().Format("Jan 2, 2006"), collected["$date"])
+}
+
+func TestLocal_RegisterSteps_concurrentVars(t *testing.T) {
+	var cnt int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := `"abc"`
+		if r.URL.Path == "/unique" {
+			id = strconv.Itoa(int(atomic.AddInt64(&cnt, 1)))
+		}
+
+		_, err := w.Write([]byte(`{"id":` + id + `}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	run := func(policy httpsteps.ConcurrentVarsPolicy) (int, string, map[string]interface{}) {
+		atomic.StoreInt64(&cnt, 0)
+
+		local := httpsteps.NewLocalClient(srv.URL, func(client *httpmock.Client) {
+			client.ConcurrencyLevel = 3
+		})
+		local.ConcurrentVars = policy
+		vs := &vars.Steps{}
+		local.VS = vs
+
+		out := bytes.NewBuffer(nil)
+		collected := map[string]interface{}{}
+
+		suite := godog.TestSuite{
+			ScenarioInitializer: func(s *godog.ScenarioContext) {
+				local.RegisterSteps(s)
+				s.After(func(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
+					ctx, v := vs.Vars(ctx)
+
+					for k, val := range v.GetAll() {
+						collected[k] = val
+					}
+
+					return ctx, nil
+				})
+			},
+			Options: &godog.Options{
+				Output:   out,
+				Format:   "pretty",
+				NoColors: true,
+				Strict:   true,
+				Paths:    []string{"_testdata/ConcurrentVars.feature"},
+			},
+		}
+
+		return suite.Run(), out.String(), collected
+	}
+
+	status, out, collected := run(httpsteps.ConcurrentVarsStrict)
+	assert.Equal(t, 1, status)
+	assert.Contains(t, out, "conflicting values of variables in concurrent responses: $unique: 1, 2, 3")
+	assert.Equal(t, "abc", collected["$same"])
+
+	status, out, collected = run(httpsteps.ConcurrentVarsCollect)
+	if !assert.Equal(t, 0, status) {
+		fmt.Println(out)
+	}
+
+	assert.Equal(t, []interface{}{"abc", "abc", "abc"}, collected["$same"])
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, collected["$unique"])
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, collected["$stored"])
+}
+
+func TestLocal_RegisterSteps_bearerToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"auth":"` + r.Header.Get("Authorization") + `"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("another", srv.URL)
+
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/BearerToken.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+}
+
+func TestLocal_RegisterSteps_serviceDefaults(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = map[string]int{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.URL.String()]++
+		attempt := attempts[r.URL.String()]
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+
+			return
+		case "/flaky":
+			if attempt < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+		case "/slow":
+			if attempt < 2 {
+				time.Sleep(500 * time.Millisecond)
+			}
+		}
+
+		_, err := w.Write([]byte(strconv.Itoa(attempt)))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("configured", srv.URL,
+		httpsteps.WithRetry("5 times"),
+		httpsteps.WithTimeout(100*time.Millisecond),
+		httpsteps.WithFollowRedirects(),
+	)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ServiceDefaults.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+}
+
+func TestLocal_RegisterSteps_transportTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("limited", srv.URL)
+
+	require.NoError(t, local.WithTimeouts("limited",
+		httpsteps.DialTimeout(time.Second),
+		httpsteps.TLSHandshakeTimeout(time.Second),
+		httpsteps.ResponseHeaderTimeout(50*time.Millisecond),
+	))
+	assert.ErrorIs(t, local.WithTimeouts("unknown"), httpsteps.ErrUnknownService)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Timeouts.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "timeout awaiting response headers")
+}
+
+func TestLocal_RegisterSteps_temporaryFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("file")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		content, err := io.ReadAll(f)
+		assert.NoError(t, err)
+
+		_, err = w.Write([]byte(h.Filename + ": " + string(content)))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	before, err := filepath.Glob(filepath.Join(os.TempDir(), "httpsteps-*"))
+	require.NoError(t, err)
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.VS = &vars.Steps{}
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.VS.Register(s)
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/TemporaryFile.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run(), out.String())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), `invalid value: temporary file name "../data.csv", name without directories expected`)
+
+	after, err := filepath.Glob(filepath.Join(os.TempDir(), "httpsteps-*"))
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestLocal_RegisterSteps_contentTypeSniffing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			body = `{"ok":true}`
+		case "/html-as-json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+			body = "<!DOCTYPE html><html><body>Bad Gateway</body></html>"
+		case "/png":
+			w.Header().Set("Content-Type", "image/png")
+			body = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+		case "/empty":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ContentTypeSniffing.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "4 scenarios (3 passed, 1 failed)")
+	assert.Contains(t, out.String(), `body does not match content type application/json: invalid JSON, `+
+		`body looks like text/html; charset=utf-8: "<!DOCTYPE html><html><body>Bad Gateway</body></html>"`)
+}
+
+func TestLocal_RegisterSteps_expression(t *testing.T) {
+	var deleted int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodDelete {
+			if atomic.AddInt64(&deleted, 1) == 1 {
+				w.WriteHeader(http.StatusNoContent)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error":"not found"}`))
+			assert.NoError(t, err)
+
+			return
+		}
+
+		_, err := w.Write([]byte(`{"customer":"c1","total":60,"items":[{"price":10},{"price":20.5},{"price":29.5}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Expression.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+
+	out.Reset()
+
+	suite.Options.Paths = []string{"_testdata/ExpressionFail.feature"}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "expression is not satisfied: body.total > 100")
+}
+
+func TestLocal_RegisterSteps_tagHeaders(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	external.TagHeaders = map[string]string{"variant": "X-Experiment"}
+
+	backend := external.Add("backend", func(mock *httpmock.Server) {
+		mock.OnError = func(_ error) {}
+	})
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.TagHeaders = map[string]string{"variant": "X-Experiment"}
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/TagHeaders.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+}
+
+func TestLocal_RegisterSteps_bodyProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"orders":[{"id":1,"amount":10,"status":"paid"},{"id":2,"amount":20,"status":"new"}]}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	local.AddBodyProvider("ordersFromDB", func(ctx context.Context) (interface{}, error) {
+		return map[string]interface{}{
+			"orders": []map[string]interface{}{{"id": 2, "amount": 20}, {"id": 1, "amount": 10}},
+		}, nil
+	})
+	local.AddBodyProvider("lastOrder", func(ctx context.Context) (interface{}, error) {
+		return []byte(`{"orders":[{"id":"$id","status":"new"}]}`), nil
+	})
+	local.AddBodyProvider("missingOrder", func(ctx context.Context) (interface{}, error) {
+		return json.RawMessage(`{"orders":[{"id":3}]}`), nil
+	})
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Provider.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+
+	out.Reset()
+
+	suite.Options.Paths = []string{"_testdata/ProviderFail.feature"}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (2 failed)")
+	assert.Contains(t, out.String(), `"id": 3`)
+	assert.Contains(t, out.String(), "unknown body provider: unknown")
+}
+
+func TestLocal_RegisterSteps_warnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"strict":false}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+
+	var (
+		mu       sync.Mutex
+		warnings []string
+	)
+
+	local.OnWarning = func(ctx context.Context, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		warnings = append(warnings, err.Error())
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output: out,
+			Format: "cucumber",
+			Strict: true,
+			Paths:  []string{"_testdata/Warnings.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+
+	require.Len(t, warnings, 4)
+	assert.Contains(t, warnings[0], "unexpected response status, expected: 201 (Created), received: 200 (OK)")
+	assert.Contains(t, out.String(), `"name": "warning"`)
+}
+
+func TestLocal_RegisterSteps_compression(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			body = []byte(`{"items":[1,2,3]}`)
+			buf  bytes.Buffer
+			wc   io.WriteCloser
+		)
+
+		encoding := r.Header.Get("Accept-Encoding")
+
+		switch encoding {
+		case "gzip":
+			wc = gzip.NewWriter(&buf)
+		case "br":
+			wc = brotli.NewWriter(&buf)
+		case "deflate":
+			wc = zlib.NewWriter(&buf)
+		default:
+			_, err := w.Write(body)
+			assert.NoError(t, err)
+
+			return
+		}
+
+		_, err := wc.Write(body)
+		assert.NoError(t, err)
+		assert.NoError(t, wc.Close())
+
+		w.Header().Set("Content-Encoding", encoding)
+		_, err = w.Write(buf.Bytes())
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Compression.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+}
+
+func TestLocal_RegisterSteps_stepError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`foo`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("another", srv.URL)
+
+	var (
+		mu     sync.Mutex
+		errs   = map[string]error{}
+		failed []*httpsteps.StepError
+	)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+
+			s.StepContext().After(func(ctx context.Context, st *godog.Step, _ godog.StepResultStatus, err error) (context.Context, error) {
+				if err == nil {
+					return ctx, nil
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				var se *httpsteps.StepError
+				if assert.True(t, errors.As(err, &se)) {
+					failed = append(failed, se)
+				}
+
+				errs[st.Text] = err
+
+				return ctx, nil
+			})
+		},
+		Options: &godog.Options{
+			Output:      out,
+			Format:      "pretty",
+			Strict:      true,
+			Concurrency: 1,
+			Paths:       []string{"_testdata/StepError.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+
+	// Step definitions are not hidden behind reflection.
+	assert.NotContains(t, out.String(), "makeFuncStub")
+
+	defs := local.StepDefinitions()
+	defs = append(defs, httpsteps.NewExternalServer().StepDefinitions()...)
+	defs = append(defs, httpsteps.NewRoundTrip(local, httpsteps.NewExternalServer()).StepDefinitions()...)
+
+	for _, d := range defs {
+		name := runtime.FuncForPC(reflect.ValueOf(d.Handler).Pointer()).Name()
+		assert.Contains(t, name, "httpsteps.stepHandler.func", d.Expression)
+	}
+
+	require.Len(t, failed, 2)
+
+	assert.Equal(t, "missing-service", failed[0].Service)
+	assert.Equal(t, `I request "missing-service" HTTP endpoint with method "GET" and URI "/"`, failed[0].Step)
+	assert.True(t, errors.Is(failed[0], httpsteps.ErrUnknownService))
+
+	assert.Equal(t, "another", failed[1].Service)
+	assert.Equal(t, `I should have "another" response with body, that contains`, failed[1].Step)
+	assert.True(t, errors.Is(failed[1], httpsteps.ErrDoesNotContain))
+	assert.Len(t, errs, 2)
+}
+
+func TestLocal_RegisterSteps_fixtures(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend", func(mock *httpmock.Server) {
+		mock.OnError = func(_ error) {}
+	})
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+	external.VS = vs
+	local.Fixtures = external.Fixtures
+
+	var calls int64
+
+	external.Fixtures.Add("signedOrder", func(ctx context.Context) ([]byte, error) {
+		n := atomic.AddInt64(&calls, 1)
+
+		return []byte(`{"order":` + strconv.Itoa(int(n)) + `,"ts":` + strconv.Itoa(int(time.Now().UnixNano())) + `}`), nil
+	})
+	external.Fixtures.AddCommand("receipt", "echo", `{"receipt":$id}`)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Fixtures.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+}
+
+func TestLocal_RegisterSteps_binaryBody(t *testing.T) {
+	sample, err := os.ReadFile("_testdata/sample.bin")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := append([]byte(nil), sample...)
+
+		if r.URL.Path == "/corrupted" {
+			body[10] = 0xaa
+		}
+
+		_, err := w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Binary.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "unexpected body bytes differ at offset 10 (0xa), expected 49 bytes, received 49 bytes")
+	assert.Contains(t, out.String(), "expected: 00000002: 4e 47 0d 0a 1a 0a 00 07 [0e] 15 1c 23 2a 31 38 3f ...")
+	assert.Contains(t, out.String(), "received: 00000002: 4e 47 0d 0a 1a 0a 00 07 [aa] 15 1c 23 2a 31 38 3f ...")
+}
+
+func TestLocal_RegisterSteps_streamFileBody(t *testing.T) {
+	var lengths []int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lengths = append(lengths, r.ContentLength)
+
+		_, err := io.Copy(w, r.Body)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.StreamFileBodyThreshold = 7
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/StreamBody.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
+
+	// Streamed body is sent with known length.
+	require.Len(t, lengths, 2)
+	assert.Equal(t, int64(7), lengths[0])
+}
+
+func TestLocal_RegisterSteps_responseProfiles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+
+		body := `{"items":[],"next":null}`
+
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(http.StatusBadRequest)
+
+			body = `{"error":{"code":"invalid","message":"invalid input"}}`
+		case "/bad-error":
+			w.WriteHeader(http.StatusBadRequest)
+
+			body = `{"error":{"code":"invalid","message":""}}`
+		}
+
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	require.NoError(t, local.LoadResponseProfiles("_testdata/profiles.yaml"))
+	local.AddResponseProfile("ok-json", httpsteps.ResponseProfile{
+		Status:  "200",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Schema:  json.RawMessage(`{"type":"object"}`),
+	})
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Profile.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out.String(), "standard-error response: unexpected body jsonschema: '/error/message' does not validate")
+}
+
+func TestLocal_RegisterSteps_serverTiming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Server-Timing", `db;dur=12.5;desc="Database, primary", cache;desc=hit`)
+		w.Header().Add("Server-Timing", "total;dur=80")
+		w.Header().Set("X-Response-Time", "15ms")
+		w.Header().Set("X-Runtime-Ms", "15.2")
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ServerTiming.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)")
+	assert.Contains(t, out.String(), "duration exceeds limit: server timing total is 80ms, expected under 50ms")
+	assert.Contains(t, out.String(), "missing server timing: queue, received: [db, cache, total]")
+}
+
+func TestLocal_RegisterSteps_trailers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		w.Header().Set("Trailer", "Grpc-Status")
+
+		_, err = w.Write([]byte(string(body) + " " + r.Trailer.Get("X-Checksum")))
+		assert.NoError(t, err)
+
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Trailers.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), `unexpected trailer Grpc-Status, expected: "1", received: "0"`)
+}
+
+func TestLocal_RegisterSteps_connectionReset(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = map[string]int{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.Method]++
+		n := attempts[r.Method]
+		mu.Unlock()
+
+		if n == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.NoError(t, err)
+			assert.NoError(t, conn.(*net.TCPConn).SetLinger(0))
+			assert.NoError(t, conn.Close())
+
+			return
+		}
+
+		_, err := w.Write([]byte(fmt.Sprintf("attempt %d", n)))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.RetryConnectionReset = true
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ConnectionReset.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run(), out.String())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "connection reset by peer")
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, map[string]int{http.MethodGet: 2, http.MethodPost: 1}, attempts)
+}
+
+func TestLocal_RegisterSteps_isolatedVars(t *testing.T) {
+	handler := func(id string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body := []byte(`{"id":"` + id + `"}`)
+			if r.URL.Path == "/echo" {
+				body = []byte(r.URL.Query().Get("id"))
+			}
+
+			_, err := w.Write(body)
+			assert.NoError(t, err)
+		}
+	}
+
+	orders := httptest.NewServer(handler("ord-1"))
+	defer orders.Close()
+
+	billing := httptest.NewServer(handler("inv-1"))
+	defer billing.Close()
+
+	local := httpsteps.NewLocalClient(orders.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	billingClient := httpsteps.NewLocalClient(billing.URL)
+	billingClient.VS = &vars.Steps{}
+	billingClient.StepPrefix = "billing: "
+	billingClient.IsolatedVars = true
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			billingClient.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Namespace.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+}
+
+func TestLocal_RegisterSteps_stepPrefix(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	external.StepPrefix = "api "
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.StepPrefix = "api "
+
+	rt := httpsteps.NewRoundTrip(local, external)
+	rt.StepPrefix = "api "
+
+	legacy := 0
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			rt.RegisterSteps(s)
+
+			// Step of another package with the same wording.
+			s.Step(`^I should have response with status "([^"]*)"$`, func(string) { legacy++ })
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/StepPrefix.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+	assert.Equal(t, 1, legacy)
+}
+
+func TestLocal_RegisterSteps_stepPatterns(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	external.StepPatterns = httpsteps.StepPatterns{
+		`^"([^"]*)" receives "([^"]*)" request "([^"]*)"$`:    `^"([^"]*)" erhält "([^"]*)" Anfrage "([^"]*)"$`,
+		`^"([^"]*)" responds with status "([^"]*)" and body$`: `^"([^"]*)" antwortet mit Status "([^"]*)" und Inhalt$`,
+	}
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.StepPatterns = httpsteps.StepPatterns{
+		`^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`: `^ich(.*) eine "([^"]*)" Anfrage an (.*) sende$`,
+		`^I should have(.*) response with status "([^"]*)"$`:               `^sollte ich(.*) eine Antwort mit Status "([^"]*)" erhalten$`,
+		`^I should have(.*) response with body$`:                           `^sollte ich(.*) eine Antwort mit Inhalt erhalten$`,
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Localized.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	// Custom expression must capture the same values.
+	local.StepPatterns = httpsteps.StepPatterns{
+		`^I should have(.*) response with status "([^"]*)"$`: `^sollte ich eine Antwort mit Status "([^"]*)" erhalten$`,
+	}
+
+	assert.EqualError(t, local.StepPatterns.Validate(), `invalid step pattern: `+
+		`^sollte ich eine Antwort mit Status "([^"]*)" erhalten$ has 1 capturing groups, `+
+		`2 expected by ^I should have(.*) response with status "([^"]*)"$`)
+
+	out.Reset()
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), `invalid step pattern: ^sollte ich eine Antwort mit Status "([^"]*)" erhalten$`)
+
+	local.StepPatterns = httpsteps.StepPatterns{
+		`^I should have(.*) response with status "([^"]*)"$`: `^sollte ich(.*) eine Antwort mit Status "([^"]*" erhalten$`,
+	}
+
+	assert.ErrorIs(t, local.StepPatterns.Validate(), httpsteps.ErrInvalidStepPattern)
+}
+
+func TestLocal_RegisterStepsWithPatterns(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+
+	var bodyHandler interface{}
+
+	for _, d := range local.StepDefinitions() {
+		if d.Expression == `^I should have(.*) response with body$` {
+			bodyHandler = d.Handler
+		}
+	}
+
+	require.NotNil(t, bodyHandler)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			assert.NoError(t, local.RegisterStepsWithPatterns(s, httpsteps.StepPatterns{
+				`^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`: `^I call(.*) "([^"]*)" (.*)$`,
+			}))
+			assert.NoError(t, external.RegisterStepsWithPatterns(s, httpsteps.StepPatterns{
+				`^"([^"]*)" receives "([^"]*)" request "([^"]*)"$`: `^"([^"]*)" is expected to receive "([^"]*)" request "([^"]*)"$`,
+			}))
+
+			// Alias keeps default wording of the step available.
+			s.Step(`^the(.*) response body should be$`, bodyHandler)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/CustomPatterns.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	suite.ScenarioInitializer = func(s *godog.ScenarioContext) {
+		err := local.RegisterStepsWithPatterns(s, httpsteps.StepPatterns{
+			`^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`: `^I call(.*) "([^"]*)"$`,
+		})
+		assert.EqualError(t, err, `invalid step pattern: ^I call(.*) "([^"]*)"$ has 2 capturing groups, `+
+			`3 expected by ^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`)
+
+		err = external.RegisterStepsWithPatterns(s, httpsteps.StepPatterns{
+			`^"([^"]*)" receives "([^"]*)" request "([^"]*)"$`: `^"([^"]*)" receives "([^"]*)" request "([^"]*)"($`,
+		})
+		assert.ErrorIs(t, err, httpsteps.ErrInvalidStepPattern)
+	}
+
+	assert.Equal(t, 1, suite.Run())
+}
+
+func TestLocal_RegisterSteps_resetState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{
+			"auth":   r.Header.Get("Authorization"),
+			"caller": r.Header.Get("X-Caller"),
+			"cookie": r.Header.Get("Cookie"),
+		}))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL, func(c *httpmock.Client) {
+		c.Headers = map[string]string{"X-Caller": "admin-console"}
+		c.Cookies = map[string]string{"session": "abc"}
+	})
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ResetState.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+}
+
+func TestLocal_RegisterSteps_namedRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Session", "s1")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"path": r.URL.Path, "user": "john"}))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/NamedRequests.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out.String(), "no response for request login, it was not sent before next request")
+}
+
+func TestLocal_RegisterSteps_chaining(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]interface{}{"id": 42, "path": r.URL.Path}
+
+		if o := r.Header.Get("X-Order"); o != "" {
+			resp["order"] = o
+		}
+
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Chaining.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out.String(), "no response: no previous request of default")
+}
+
+func TestLocal_RegisterSteps_polling(t *testing.T) {
+	var polls int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/job/1" && atomic.AddInt64(&polls, 1) > 2 {
+			_, err := w.Write([]byte(`{"status":"done"}`))
+			assert.NoError(t, err)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+
+		_, err := w.Write([]byte(`{"status":"pending"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Polling.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "polling every 10ms for up to 50ms: unexpected response status, expected: 200 (OK), received: 202 (Accepted)")
+	assert.Equal(t, int64(3), atomic.LoadInt64(&polls))
+}
+
+func TestLocal_RegisterSteps_retryWhileStatus(t *testing.T) {
+	var flaky, broken int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if atomic.AddInt64(&flaky, 1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+		case "/broken":
+			atomic.AddInt64(&broken, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.RetryBackOff = func(ctx context.Context, _ time.Duration) (context.Context, httpmock.RetryBackOff) {
+		return ctx, backoff.NewConstantBackOff(time.Millisecond)
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RetryStatus.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "unexpected response status, expected: 200 (OK), received: 500 (Internal Server Error)")
+	assert.Equal(t, int64(3), atomic.LoadInt64(&flaky))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&broken))
+}
+
+func TestLocal_RegisterSteps_retryBackOff(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = map[string][]time.Time{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts[r.URL.Path] = append(attempts[r.URL.Path], time.Now())
+
+		if len(attempts[r.URL.Path]) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Backoff.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out.String(), `invalid value: backoff interval: "soon"`)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	constant := attempts["/flaky/constant"]
+	require.Len(t, constant, 3)
+
+	for i := 1; i < len(constant); i++ {
+		assert.GreaterOrEqual(t, constant[i].Sub(constant[i-1]), 20*time.Millisecond)
+	}
+
+	assert.Len(t, attempts["/flaky/exponential"], 3)
+}
+
+func TestLocal_RegisterSteps_concurrencyLevel(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests[r.URL.Path]++
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ConcurrencyLevel.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, map[string]int{"/custom": 25, "/default": 10}, requests)
+}
+
+func TestLocal_RegisterSteps_identicalResponses(t *testing.T) {
+	var cnt int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&cnt, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/request-id":
+			w.Header().Set("X-Request-Id", strconv.Itoa(int(n)))
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "/counter":
+			_, _ = w.Write([]byte(`{"counter":` + strconv.Itoa(int(n)) + `}`))
+		default:
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/IdenticalResponses.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)")
+	assert.Contains(t, out.String(), "concurrent responses differ: response ")
+	assert.Contains(t, out.String(), " has header X-Request-Id: ")
+	assert.Contains(t, out.String(), " has body \"{\\\"counter\\\":")
+}
+
+func TestLocal_RegisterSteps_textBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+
+		if r.URL.Path == "/report-lf.csv" {
+			_, _ = w.Write([]byte("id,name\n1,foo  \n2,bar\n"))
+
+			return
+		}
+
+		_, _ = w.Write([]byte("id,name\r\n1,foo\r\n2,bar \r\n"))
+	}))
+	defer srv.Close()
+
+	run := func(normalize bool) string {
+		local := httpsteps.NewLocalClient(srv.URL)
+		local.NormalizeTextBodies = normalize
+
+		out := bytes.NewBuffer(nil)
+
+		suite := godog.TestSuite{
+			ScenarioInitializer: func(s *godog.ScenarioContext) {
+				local.RegisterSteps(s)
+			},
+			Options: &godog.Options{
+				Output:   out,
+				Format:   "pretty",
+				NoColors: true,
+				Strict:   true,
+				Paths:    []string{"_testdata/TextBodies.feature"},
+			},
+		}
+
+		suite.Run()
+
+		return out.String()
+	}
+
+	out := run(false)
+	assert.Contains(t, out, "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out, `expected: "id,name\n1,foo\n2,bar", received: "id,name\r\n1,foo\r\n2,bar \r\n"`)
+
+	out = run(true)
+	assert.Contains(t, out, "3 scenarios (3 passed)", out)
+}
+
+func TestLocal_RegisterSteps_jsonOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"ord-1","total":10.3349,"tags":["c","a","b"],"createdAt":"2023-01-02T03:04:05Z"}`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/JSONOptions.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)", out.String())
+	assert.Contains(t, out.String(), `-  "total": 10.33`)
+	assert.Contains(t, out.String(), `+  "total": 10.3349`)
+
+	assert.Contains(t, out.String(), "_testdata/JSONOptions.feature:5")
+
+	// Options of client apply to all scenarios, tolerance of scenario takes precedence.
+	local.JSONOptions = httpsteps.JSONOptions{Tolerance: 0.01, IgnoreArrayOrder: true, IgnoreMarkers: []string{"<any-time>"}}
+
+	out.Reset()
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)", out.String())
+	assert.NotContains(t, out.String(), "_testdata/JSONOptions.feature:5")
+	assert.Contains(t, out.String(), "_testdata/JSONOptions.feature:25")
+}
+
+func TestLocal_RegisterSteps_arrayOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"items":[{"id":3,"name":"c"},{"id":1,"tags":["a","b"]},{"id":2}],"total":3}`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ArrayOrder.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)", out.String())
+	assert.Contains(t, out.String(), "_testdata/ArrayOrder.feature:5")
+	assert.Contains(t, out.String(), "_testdata/ArrayOrder.feature:19")
+	assert.Contains(t, out.String(), `-      "id": 4`)
+}
+
+func TestLocal_RegisterSteps_jsonPathOperators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"ord-123","total":42.5,"discount":5,"status":"paid","items":[{"sku":"a"},{"sku":"b"},{"sku":"c"}]}`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/JSONPathOperators.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "4 scenarios (1 passed, 3 failed)", out.String())
+	assert.Contains(t, out.String(), "failed to assert jsonpath $.total: condition is not met: 42.5 > 50")
+	assert.Contains(t, out.String(), "failed to assert jsonpath $.items: length 3: condition is not met: 3 < 3")
+	assert.Contains(t, out.String(), `failed to assert jsonpath $.id: condition is not met: "ord-123" does not match "^inv-"`)
+}
+
+func TestLocal_RegisterSteps_csvBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, []string{"", "bar"}, r.URL.Query().Get("name"))
+
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("id,name,created_at,comment\r\n1,foo,2023-01-01,\"a, b\"\r\n2,bar,2023-01-02,\r\n"))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/CSVBody.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)", out.String())
+	assert.Contains(t, out.String(), `-    "name": "baz"`)
+	assert.Contains(t, out.String(), `+    "name": "bar"`)
+	assert.Contains(t, out.String(), "missing column email, received: [id name created_at comment]")
+}
+
+func TestLocal_RegisterSteps_protobuf(t *testing.T) {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, repeated bool) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+
+		return &descriptorpb.FieldDescriptorProto{
+			Name: proto.String(name), JsonName: proto.String(name), Number: proto.Int32(number), Type: typ.Enum(), Label: label.Enum(),
+		}
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("shop/order.proto"),
+		Package: proto.String("shop"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false),
+				field("total", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, false),
+				field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, true),
+			},
+		}},
+	}}}
+
+	fd, err := protodesc.NewFile(fds.File[0], nil)
+	require.NoError(t, err)
+
+	md := fd.Messages().ByName("Order")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, httpsteps.ProtobufContentType, r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		m := dynamicpb.NewMessage(md)
+		assert.NoError(t, proto.Unmarshal(body, m))
+
+		// Total is doubled in response.
+		total := md.Fields().ByName("total")
+		m.Set(total, protoreflect.ValueOfInt64(2*m.Get(total).Int()))
+
+		body, err = proto.Marshal(m)
+		assert.NoError(t, err)
+
+		w.Header().Set("Content-Type", httpsteps.ProtobufContentType)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	data, err := proto.Marshal(fds)
+	require.NoError(t, err)
+
+	descriptorFile := filepath.Join(t.TempDir(), "order.pb")
+	require.NoError(t, os.WriteFile(descriptorFile, data, 0o600))
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	require.NoError(t, local.AddDescriptorSetFile(descriptorFile))
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Protobuf.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)", out.String())
+	assert.Contains(t, out.String(), `-  "total": "1"`)
+	assert.Contains(t, out.String(), `+  "total": "2"`)
+	assert.Contains(t, out.String(), "unknown protobuf message type: shop.Invoice")
+}
+
+func TestLocal_RegisterSteps_ndjson(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"id":1,"status":"done","createdAt":"2023-01-01T00:00:00Z"}` + "\n" +
+			`{"id":2,"status":"done","createdAt":"2023-01-01T00:00:01Z"}` + "\n" +
+			`{"id":3,"status":"failed","createdAt":"2023-01-01T00:00:02Z"}` + "\n"))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/NDJSON.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)", out.String())
+	assert.Contains(t, out.String(), "line 3: not equal")
+	assert.Contains(t, out.String(), "unexpected number of lines: 1 expected, 3 received")
+}
+
+func TestLocal_RegisterSteps_formFiles(t *testing.T) {
+	type file struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Size int64  `json:"size"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := struct {
+			Fields url.Values      `json:"fields"`
+			Files  map[string]file `json:"files,omitempty"`
+		}{}
+
+		if r.URL.Path == "/form" {
+			assert.NoError(t, r.ParseForm())
+			res.Fields = r.PostForm
+		} else {
+			assert.NoError(t, r.ParseMultipartForm(1<<20))
+			res.Fields = r.MultipartForm.Value
+			res.Files = map[string]file{}
+
+			for name, fh := range r.MultipartForm.File {
+				res.Files[name] = file{Name: fh[0].Filename, Type: fh[0].Header.Get("Content-Type"), Size: fh[0].Size}
+			}
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/FormFiles.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "missing file _testdata/missing.png")
+}
+
+func TestLocal_Reset(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/users" {
+			w.Header().Set("Deprecation", "@1688169599")
+		}
+
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.Deprecations = &httpsteps.Deprecations{}
+	local.StepUsage = &httpsteps.StepUsage{}
+
+	run := func() {
+		suite := godog.TestSuite{
+			ScenarioInitializer: local.RegisterSteps,
+			Options: &godog.Options{
+				Output: io.Discard,
+				Format: "pretty",
+				Paths:  []string{"_testdata/Deprecation.feature"},
+			},
+		}
+
+		suite.Run()
+	}
+
+	uses := func() int {
+		for _, st := range local.StepUsage.Stats() {
+			if st.Expression == `^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$` {
+				return st.Uses
+			}
+		}
+
+		return 0
+	}
+
+	run()
+	require.Len(t, local.Deprecations.Endpoints(), 1)
+	assert.Equal(t, 2, local.Deprecations.Endpoints()[0].Uses)
+	assert.Equal(t, 3, uses())
+
+	local.Reset()
+	assert.Empty(t, local.Deprecations.Endpoints())
+	assert.Empty(t, local.StepUsage.Stats())
+
+	// Second suite does not see state of the first one.
+	run()
+	require.Len(t, local.Deprecations.Endpoints(), 1)
+	assert.Equal(t, 2, local.Deprecations.Endpoints()[0].Uses)
+	assert.Equal(t, 3, uses())
+}
+
+func TestLocal_RegisterSteps_deprecation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/users" {
+			w.Header().Set("Deprecation", "@1688169599")
+			w.Header().Set("Sunset", "Wed, 01 Jan 2025 00:00:00 GMT")
+		}
+
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.Deprecations = &httpsteps.Deprecations{}
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Deprecation.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)")
+	assert.Contains(t, out.String(), "unexpected sunset: expected 2026-01-01, received Wed, 01 Jan 2025 00:00:00 GMT")
+	assert.Contains(t, out.String(), "response is not marked deprecated: missing Deprecation header")
+
+	endpoints := local.Deprecations.Endpoints()
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "GET", endpoints[0].Method)
+	assert.Equal(t, "/v1/users", endpoints[0].Path)
+	assert.Equal(t, 2, endpoints[0].Uses)
+	assert.Equal(t, time.Unix(1688169599, 0).UTC(), endpoints[0].Deprecation)
+
+	report := bytes.NewBuffer(nil)
+	require.NoError(t, local.Deprecations.Report(report))
+	assert.Equal(t, `Deprecated endpoints in use:
+     2 default: GET /v1/users (sunset 2025-01-01T00:00:00Z)
+       _testdata/Deprecation.feature
+`, report.String())
+}
+
+func TestLocal_RegisterSteps_responseCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/me" {
+			c, err := r.Cookie("session")
+			assert.NoError(t, err)
+
+			_, _ = w.Write([]byte(`{"session":"` + c.Value + `"}`))
+
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name: "session", Value: "abc123", Path: "/", MaxAge: 3600,
+			HttpOnly: true, Secure: true, SameSite: http.SameSiteStrictMode,
+		})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark"})
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ResponseCookies.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)")
+	assert.Contains(t, out.String(), "unexpected cookie: HttpOnly is missing in cookie theme")
+	assert.Contains(t, out.String(), "missing cookie token, received: [session, theme]")
+}
+
+func TestLocal_RegisterSteps_snapshot(t *testing.T) {
+	var name atomic.Value
+
+	name.Store("Jane")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/page" {
+			_, _ = w.Write([]byte("<h1>Hello, " + name.Load().(string) + "!</h1>"))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"name":"` + name.Load().(string) + `","id":1}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.SnapshotsDir = dir
+
+	run := func() string {
+		out := bytes.NewBuffer(nil)
+
+		suite := godog.TestSuite{
+			ScenarioInitializer: func(s *godog.ScenarioContext) {
+				local.RegisterSteps(s)
+			},
+			Options: &godog.Options{
+				Output:   out,
+				Format:   "pretty",
+				NoColors: true,
+				Strict:   true,
+				Paths:    []string{"_testdata/Snapshot.feature"},
+			},
+		}
+
+		suite.Run()
+
+		return out.String()
+	}
+
+	t.Setenv(httpsteps.UpdateSnapshotsEnv, "yes")
+	assert.Contains(t, run(), `invalid value: HTTPSTEPS_UPDATE_SNAPSHOTS="yes"`)
+
+	// Missing snapshots are recorded.
+	t.Setenv(httpsteps.UpdateSnapshotsEnv, "false")
+	assert.Contains(t, run(), "2 scenarios (2 passed)")
+
+	profile, err := os.ReadFile(filepath.Join(dir, "profile.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"Jane\",\n  \"id\": 1\n}\n", string(profile))
+
+	page, err := os.ReadFile(filepath.Join(dir, "page.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Hello, Jane!</h1>", string(page))
+
+	assert.Contains(t, run(), "2 scenarios (2 passed)")
+
+	name.Store("John")
+
+	out := run()
+	assert.Contains(t, out, "2 scenarios (2 failed)")
+	assert.Contains(t, out, `"name": "Jane"`)
+
+	t.Setenv(httpsteps.UpdateSnapshotsEnv, "1")
+	assert.Contains(t, run(), "2 scenarios (2 passed)")
+
+	page, err = os.ReadFile(filepath.Join(dir, "page.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Hello, John!</h1>", string(page))
+}
+
+func TestLocal_RegisterSteps_bodyContaining(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body><h1>Welcome, "Jane"!</h1></body></html>`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/BodyContaining.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), `unexpected body does not contain "Goodbye" in`)
+}
+
+func TestLocal_RegisterSteps_notContaining(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("debug") != "" {
+			_, _ = w.Write([]byte(`{"name":"Jane","role":"user","groups":["staff","root"],"passwordHash":"$2a$10$abc"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"name":"Jane","role":"user","groups":["staff"],"items":[1,2]}`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/NotContaining.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "4 scenarios (1 passed, 3 failed)")
+	assert.Contains(t, out.String(), `unexpected body contains $.passwordHash: "$2a$10$abc"`)
+	assert.Contains(t, out.String(), `unexpected body contains $.groups: "root"`)
+	assert.Contains(t, out.String(), `unexpected body contains "$2a$10$" in `)
+}
+
+func TestLocal_RegisterSteps_streamedBody(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	local := httpsteps.NewLocalClient(backend)
+	local.AddBodyStream("10MB", httpsteps.GeneratedBody(10<<20))
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/StreamedBody.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "unexpected body: backend received request body of 10485760 bytes, expected 100")
+
+	progress, err := external.RequestBodyProgress("backend")
+	require.NoError(t, err)
+	require.NotEmpty(t, progress)
+	assert.Equal(t, int64(10<<20), progress[len(progress)-1].Bytes)
+}
+
+func TestLocal_RegisterSteps_idempotencyGrouping(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		winners, err := strconv.Atoi(r.URL.Query().Get("winners"))
+		assert.NoError(t, err)
+
+		mu.Lock()
+		requests[r.URL.RawQuery]++
+		created := requests[r.URL.RawQuery] <= winners
+		mu.Unlock()
+
+		_, err = w.Write([]byte(fmt.Sprintf(`{"created":%t}`, created)))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.WithIdempotencyGrouping(httpsteps.GroupByJSONPath("$.created"))
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/IdempotencyGrouping.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), `operation is not idempotent: groups of responses: "false" x8, "true" x2`)
+}
+
+func TestLocal_RegisterSteps_concurrencyRamp(t *testing.T) {
+	var inFlight, okRequests int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			atomic.AddInt64(&okRequests, 1)
+
+			return
+		}
+
+		defer atomic.AddInt64(&inFlight, -1)
+
+		if atomic.AddInt64(&inFlight, 1) > 25 {
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Ramp.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out.String(), "unexpected status at 35 concurrent requests, expected: 200, received: 5: 200 x5; 20: 200 x20; 35: ")
+	assert.Equal(t, int64(9), atomic.LoadInt64(&okRequests))
+}
+
+func TestLocal_RegisterSteps_batch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+
+		var err error
+
+		if r.URL.Path == "/dav" {
+			_, err = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/dav/a.txt</d:href>
+    <d:propstat>
+      <d:prop><d:getcontentlength>12</d:getcontentlength></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/dav/b.txt</d:href>
+    <d:status>HTTP/1.1 403 Forbidden</d:status>
+  </d:response>
+</d:multistatus>`))
+		} else {
+			_, err = w.Write([]byte(`{"responses":[
+  {"id":"a","status":200,"body":{"name":"Alice","id":"u1"}},
+  {"id":"b","status":404,"body":{"error":"not found"}}
+]}`))
+		}
+
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Batch.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)")
+	assert.Contains(t, out.String(), "part b: unexpected status, expected: 200, received: 404")
+}
+
+func TestLocal_RegisterSteps_health(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "ok"
+
+		switch r.URL.Path {
+		case "/ready":
+			status = "starting"
+		case "/slow":
+			time.Sleep(150 * time.Millisecond)
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"status": status}))
+	}))
+	defer srv.Close()
+
+	hc := httpsteps.DefaultHealthCheck()
+	hc.MaxLatency = 100 * time.Millisecond
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.HealthCheck = &hc
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Health.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)")
+	assert.Contains(t, out.String(), "healthy response: unexpected body")
+	assert.Contains(t, out.String(), "healthy response: duration exceeds limit: latency is")
+}
+
+func TestLocal_RegisterSteps_includingJSON(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend", func(mock *httpmock.Server) {
+		mock.OnError = func(_ error) {}
+	})
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+	external.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/IncludingJSON.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (1 passed, 2 failed)")
+	assert.Contains(t, out.String(), "unexpected response status, expected: 200 (OK), received: 500 (Internal Server Error)")
+	assert.Contains(t, out.String(), `"id": 4`)
+}
+
+func TestLocal_RegisterSteps_requestJSONPaths(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend", func(mock *httpmock.Server) {
+		mock.OnError = func(_ error) {}
+	})
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+	external.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RequestJSONPaths.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "unexpected response status, expected: 200 (OK), received: 500 (Internal Server Error)")
+}
+
+func TestLocal_RegisterSteps_requestForm(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend", func(mock *httpmock.Server) {
+		mock.OnError = func(_ error) {}
+	})
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+	external.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RequestForm.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "unexpected response status, expected: 200 (OK), received: 500 (Internal Server Error)")
+}
+
+func TestLocal_RegisterSteps_responseDelay(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("limited", srv.URL)
+	require.NoError(t, local.WithTimeouts("limited", httpsteps.ResponseHeaderTimeout(100*time.Millisecond)))
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ResponseDelay.feature"},
+		},
+	}
+
+	start := time.Now()
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "timeout awaiting response headers")
+
+	// Delay of the first response is interrupted by canceled request.
+	elapsed := time.Since(start)
+	assert.Greater(t, elapsed, 200*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestLocal_RegisterSteps_responseSequence(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ResponseSequence.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "expectations were not met for backend: there are remaining expectations that were not met: GET /flaky")
+}
+
+func TestLocal_RegisterSteps_pathParams(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/PathParams.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "3 scenarios (2 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "remaining expectations that were not met: GET /users/{id}/orders")
+}
+
+func TestLocal_RegisterSteps_queryOrder(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/QueryOrder.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "remaining expectations that were not met: GET /things?a=1&b=2")
+}
+
+func TestLocal_RegisterSteps_requestRegex(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RequestRegex.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), `remaining expectations that were not met: GET ^/reports/\d{4}-\d{2}$`)
+}
+
+func TestLocal_RegisterSteps_requestMatchesJSON(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RequestMatchesJSON.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "_testdata/RequestMatchesJSON.feature:29")
+}
+
+func TestLocal_RegisterSteps_receivedRequests(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ReceivedRequests.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "unexpected requests: backend received 1 requests to GET /things, expected 3")
+}
+
+func TestLocal_RegisterSteps_receivedRequestVars(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	vs := &vars.Steps{}
+	local.VS = vs
+	external.VS = vs
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ReceivedRequestVars.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "missing request with body: backend")
+}
+
+func TestLocal_RegisterSteps_responseTemplate(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ResponseTemplate.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+}
+
+func TestLocal_RegisterSteps_conditionalResponse(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/ConditionalResponse.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+}
+
+func TestLocal_RegisterSteps_savedRequest(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	require.NoError(t, local.LoadPostmanCollection("_testdata/collection.postman.json"))
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/SavedRequest.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)", out.String())
+	assert.Contains(t, out.String(), "unknown request: Delete user")
+}
+
+func TestLocal_RegisterSteps_curlOutput(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	curl := bytes.NewBuffer(nil)
+	local.CurlOutput = curl
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Curl.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+	assert.Equal(t, "curl -X POST '"+srv.URL+"/login?api_key=REDACTED&lang=en' "+
+		"-H 'Authorization: REDACTED' -H 'X-Name: it'\\''s me' "+
+		`--data-raw '{"password":"REDACTED","user":"john"}'`+"\n", curl.String())
+}
+
+func TestLocal_RegisterSteps_debugLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_, err := w.Write([]byte(`{"status":"ok"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.ExposeHTTPDetails = nil
+	debug := bytes.NewBuffer(nil)
+	local.DebugOutput = debug
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/DebugLogging.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	logged := debug.String()
+	assert.NotContains(t, logged, "/quiet")
+	assert.Contains(t, logged, ">>> default request\nPOST /loud HTTP/1.1\r\n")
+	assert.Contains(t, logged, "Authorization: REDACTED")
+	assert.NotContains(t, logged, "t0ps3cret")
+	assert.Contains(t, logged, "<<< default response\nHTTP/1.1 200 OK\r\n")
+	assert.Contains(t, logged, `"status": "ok"`)
+	assert.Equal(t, 1, strings.Count(logged, ">>> default request"), logged)
+}
+
+func TestLocal_RegisterSteps_strictServices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("users", srv.URL)
+	local.StrictServices = true
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: local.RegisterSteps,
+		Options: &godog.Options{
+			Output: out,
+			Format: "cucumber",
+			Strict: true,
+			Paths:  []string{"_testdata/StrictServices.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+
+	var report []struct {
+		Elements []struct {
+			Name  string `json:"name"`
+			Steps []struct {
+				Result struct {
+					Status string `json:"status"`
+					Error  string `json:"error_message"`
+				} `json:"result"`
+			} `json:"steps"`
+		} `json:"elements"`
+	}
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report, 1)
+
+	errs := map[string]string{}
+
+	for _, e := range report[0].Elements {
+		for _, st := range e.Steps {
+			if st.Result.Status == "failed" {
+				errs[e.Name] = st.Result.Error
+			}
+		}
+	}
+
+	assert.NotContains(t, errs, "Registered services")
+	assert.Contains(t, errs["Malformed service"], `malformed service qualifier: "\"users", `+
+		`expected a quoted service name, registered services: "default", "users"`)
+	assert.Contains(t, errs["Unknown service"], `unknown service: user, registered services: "default", "users"`)
+}
+
+func TestLocal_RegisterSteps_dynamicService(t *testing.T) {
+	inventory := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`["apple"]`))
+		assert.NoError(t, err)
+	}))
+	defer inventory.Close()
+
+	discovery := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"inventory": inventory.URL}))
+	}))
+	defer discovery.Close()
+
+	local := httpsteps.NewLocalClient(discovery.URL)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: local.RegisterSteps,
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/DynamicService.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run(), out.String())
+	assert.Contains(t, out.String(), `invalid value: base URL "warehouse"`)
+	assert.Contains(t, out.String(), "4 scenarios (3 passed, 1 failed)")
+
+	// Service of scenario is not registered in client.
+	_, _, err := local.Service(context.Background(), "inventory")
+	assert.ErrorIs(t, err, httpsteps.ErrUnknownService)
+}
+
+func TestLocal_RegisterSteps_dryRun(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	external.Add("backend")
+	external.DryRun = true
+
+	// Requests would fail if they were sent.
+	local := httpsteps.NewLocalClient("http://127.0.0.1:1")
+	local.DryRun = true
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/DryRun.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	out.Reset()
+
+	suite.Options.Paths = []string{"_testdata/DryRunFail.feature"}
+	suite.Options.Format = "cucumber"
+
+	assert.Equal(t, 1, suite.Run())
+
+	var report []struct {
+		Elements []struct {
+			Name  string `json:"name"`
+			Steps []struct {
+				Result struct {
+					Status string `json:"status"`
+					Error  string `json:"error_message"`
+				} `json:"result"`
+			} `json:"steps"`
+		} `json:"elements"`
+	}
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report, 1)
+
+	errs := map[string]string{}
+
+	for _, e := range report[0].Elements {
+		for _, st := range e.Steps {
+			if st.Result.Status == "failed" {
+				errs[e.Name] = st.Result.Error
+			}
+		}
+	}
+
+	assert.Contains(t, errs["Unknown status"], `unknown http status: "Fine"`)
+	assert.Contains(t, errs["Unknown service"], "unknown service: billing")
+	assert.Contains(t, errs["Invalid JSON"], "invalid value: JSON5 document")
+	assert.Contains(t, errs["Missing file"], "no such file or directory")
+	assert.Contains(t, errs["Undefined response of external service"], "undefined response")
+}
+
+func TestLocal_RegisterSteps_onRoundTrip(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+
+	var (
+		mu       sync.Mutex
+		sent     []httpsteps.RequestInfo
+		received []httpsteps.RequestInfo
+		statuses []int
+	)
+
+	local.OnRoundTrip(func(req httpsteps.RequestInfo, resp httpsteps.ResponseInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		sent = append(sent, req)
+		statuses = append(statuses, resp.Status)
+	})
+
+	external.OnRoundTrip(func(req httpsteps.RequestInfo, resp httpsteps.ResponseInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, req)
+		statuses = append(statuses, resp.Status)
+	})
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Curl.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	require.Len(t, sent, 1)
+	assert.Equal(t, httpsteps.Default, sent[0].Service)
+	assert.Equal(t, http.MethodPost, sent[0].Method)
+	assert.Equal(t, srv.URL+"/login?api_key=abc&lang=en", sent[0].URL)
+	assert.Equal(t, "Bearer t0ps3cret", sent[0].Header.Get("Authorization"))
+	assert.Equal(t, `{"user":"john","password":"pass"}`, string(sent[0].Body))
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "backend", received[0].Service)
+	assert.Equal(t, "/login?api_key=abc&lang=en", received[0].URL)
+	assert.Equal(t, `{"user":"john","password":"pass"}`, string(received[0].Body))
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK}, statuses)
+}
+
+func TestLocal_RegisterSteps_attachments(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output: out,
+			Format: "cucumber",
+			Strict: true,
+			Paths:  []string{"_testdata/Attachments.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+
+	var report []struct {
+		Elements []struct {
+			Steps []struct {
+				Embeddings []struct {
+					Name string `json:"name"`
+					Data []byte `json:"data"`
+				} `json:"embeddings"`
+			} `json:"steps"`
+		} `json:"elements"`
+	}
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report, 1)
+	require.Len(t, report[0].Elements, 1)
+
+	steps := report[0].Elements[0].Steps
+	attachments := map[string]string{}
+
+	for _, e := range steps[len(steps)-1].Embeddings {
+		attachments[e.Name] = string(e.Data)
+	}
+
+	assert.Contains(t, attachments["request"], "Authorization: REDACTED")
+	assert.NotContains(t, attachments["request"], "t0ps3cret")
+	assert.Contains(t, attachments["request"], "{\n  \"password\": \"REDACTED\",\n  \"user\": \"john\"\n}")
+	assert.Contains(t, attachments["response"], "200 OK")
+	assert.Contains(t, attachments["response"], "{\n  \"token\": \"REDACTED\",\n  \"user\": \"john\"\n}")
+}
+
+func TestLocal_RegisterSteps_outage(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend")
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Outage.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "unexpected requests: backend received ")
+	assert.Contains(t, out.String(), " requests during outage, expected at most 3")
+
+	_, err = external.Outage("unknown")
+	assert.ErrorIs(t, err, httpsteps.ErrUnknownService)
+}
+
+func TestLocal_RegisterSteps_criticalSection(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	backend := external.Add("backend", func(mock *httpmock.Server) {
+		mock.OnError = func(_ error) {}
+	})
+
+	u, err := url.Parse(backend)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/CriticalSection.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	assert.Contains(t, out.String(), "backend: interleaved requests between /start and /commit: POST /audit")
+}
+
+func TestLocal_RegisterSteps_requestSignature(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	external.AddSigningKey("webhook-secret", httpsteps.SigningKey{
+		Secret: []byte("topsecret"),
+		Header: "X-Hub-Signature",
+	})
+
+	var mockErr error
+
+	webhooks := external.Add("webhooks", func(mock *httpmock.Server) {
+		mock.OnError = func(err error) { mockErr = err }
+	})
+
+	local := httpsteps.NewLocalClient(webhooks)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RequestSignature.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), "2 scenarios (1 passed, 1 failed)")
+	require.Error(t, mockErr)
+	assert.Contains(t, mockErr.Error(), "<valid signature of webhook-secret>")
+}
+
+func TestLocal_RegisterSteps_bodyMismatch(t *testing.T) {
+	items := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, strconv.Itoa(i))
+	}
+
+	body := []byte(`{"items":[` + strings.Join(items, ",") + `]}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write(body)
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.ExposeHTTPDetails = nil
+	local.MaxDiffLength = 100
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output: out,
+			Format: "cucumber",
+			Strict: true,
+			Paths:  []string{"_testdata/BodyMismatch.feature"},
+		},
+	}
+
+	assert.Equal(t, 1, suite.Run())
+
+	var report []struct {
+		Elements []struct {
+			Steps []struct {
+				Result struct {
+					Error string `json:"error_message"`
+				} `json:"result"`
+				Embeddings []struct {
+					Name     string `json:"name"`
+					MimeType string `json:"mime_type"`
+					Data     []byte `json:"data"`
+				} `json:"embeddings"`
+			} `json:"steps"`
+		} `json:"elements"`
+	}
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report, 1)
+	require.Len(t, report[0].Elements, 1)
+	require.Len(t, report[0].Elements[0].Steps, 2)
+
+	step := report[0].Elements[0].Steps[1]
+	assert.Contains(t, step.Result.Error, "bytes truncated, full received body is attached")
+	assert.Less(t, len(step.Result.Error), 200)
+
+	require.Len(t, step.Embeddings, 1)
+	assert.Equal(t, "received body", step.Embeddings[0].Name)
+	assert.Equal(t, "application/json", step.Embeddings[0].MimeType)
+	assert.Equal(t, string(body), string(step.Embeddings[0].Data))
+}
+
+func TestLocal_RegisterSteps_bodyDiff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(`{"id":1,"name":"Jane","tags":["user"],` +
+			`"address":{"city":"Paris","zip":"123"},"score":10,"extra":true}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	for _, color := range []bool{false, true} {
+		local := httpsteps.NewLocalClient(srv.URL)
+		local.ExposeHTTPDetails = nil
+		local.BodyDiff = &httpsteps.BodyDiff{Color: color, MaxPaths: 3}
+
+		out := bytes.NewBuffer(nil)
+
+		suite := godog.TestSuite{
+			ScenarioInitializer: func(s *godog.ScenarioContext) {
+				local.RegisterSteps(s)
+			},
+			Options: &godog.Options{
+				Output: out,
+				Format: "cucumber",
+				Strict: true,
+				Paths:  []string{"_testdata/BodyDiff.feature"},
+			},
+		}
+
+		assert.Equal(t, 1, suite.Run())
+
+		var report []struct {
+			Elements []struct {
+				Steps []struct {
+					Result struct {
+						Error string `json:"error_message"`
+					} `json:"result"`
+				} `json:"steps"`
+			} `json:"elements"`
+		}
+
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		require.Len(t, report, 1)
+		require.Len(t, report[0].Elements, 1)
+		require.Len(t, report[0].Elements[0].Steps, 2)
+
+		msg := report[0].Elements[0].Steps[1].Result.Error
+
+		if color {
+			assert.Contains(t, msg, "$.name: expected \x1b[31m\"John\"\x1b[0m, received \x1b[32m\"Jane\"\x1b[0m")
+
+			continue
+		}
+
+		assert.Contains(t, msg, "unexpected body with 4 differing paths:\n"+
+			"  $.address.city: expected \"Berlin\", received \"Paris\"\n"+
+			"  $.extra: unexpected, received true\n"+
+			"  $.name: expected \"John\", received \"Jane\"\n"+
+			"  ... and 1 more\n")
+	}
+}
+
+func TestLocal_RegisterSteps_signature(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte("top-secret"))
+		_, err = mac.Write(body)
+		assert.NoError(t, err)
+
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if len(body) == 0 {
+			body = []byte("null")
+		}
+
+		_, err = w.Write([]byte(`{"valid":true,"body":` + string(body) + `}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	vs := &vars.Steps{}
+	local.VS = vs
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			vs.Register(s)
+		},
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Signature.feature"},
+		},
+	}
+
+	if !assert.Equal(t, 0, suite.Run()) {
+		fmt.Println(out.String())
+	}
 }
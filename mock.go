@@ -0,0 +1,546 @@
+package httpsteps
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bool64/httpmock"
+	"github.com/bool64/shared"
+	"github.com/swaggest/assertjson/json5"
+)
+
+type mock struct {
+	exp *exp
+	srv *httpmock.Server
+
+	// server serves requests to srv, it is closed with ExternalServer.
+	server *httptest.Server
+
+	// tls enables HTTPS server.
+	tls bool
+
+	// err is a failure to set up server, it fails steps of the service.
+	err error
+
+	// service is a name of mocked service.
+	service string
+
+	// observers returns observers of received requests.
+	observers func() []func(req RequestInfo, resp ResponseInfo)
+
+	// last is the last defined expectation, it is a template for a sequence of responses.
+	last *exp
+
+	mu       sync.Mutex
+	includes []inclusion
+
+	// expectations and async mirror expectations of srv to expose pending ones.
+	expectations []PendingExpectation
+	async        []PendingExpectation
+
+	// received is a history of requests in current scenario.
+	received []receivedRequest
+
+	// signatures are verified before request is served.
+	signatures []requestSignature
+
+	// sections are critical sections of requests that must not be interleaved with other requests.
+	sections []criticalSection
+
+	// outage is a window when requests are served with status "Service Unavailable".
+	outage *Outage
+
+	// streamBodies enables counting of request bodies without keeping them in memory.
+	streamBodies bool
+
+	// delay is a delay of response to current request.
+	delay time.Duration
+
+	// fault is a fault of response to current request.
+	fault *fault
+
+	// chunking is a chunking of response to current request.
+	chunking *chunking
+
+	// handler is a handler of current request.
+	handler *requestHandler
+
+	// stubs serve unmatched requests.
+	stubs []Stub
+
+	// passthrough forwards unmatched requests to upstream.
+	passthrough *requestHandler
+
+	// release is closed on reset to release requests that never get response.
+	release chan struct{}
+
+	// vars are initial variables of srv JSON comparer.
+	vars *shared.Vars
+
+	// branchSeq is a sequence number of conditional responses to make their request URIs unique.
+	branchSeq int
+}
+
+// criticalSection is defined by request URIs of the first and the last requests.
+type criticalSection struct {
+	start, end string
+}
+
+// receivedRequest describes a request received by mock.
+type receivedRequest struct {
+	method     string
+	requestURI string
+	header     http.Header
+	body       []byte
+	progress   []BodyProgress
+}
+
+// PendingExpectation describes an expectation of external service that is not met yet.
+//
+// Repeated is a remaining number of requests, zero value means one request.
+type PendingExpectation struct {
+	httpmock.Expectation
+	Async bool
+
+	// Delay is a delay of response.
+	Delay time.Duration
+
+	// uriRegex matches request URI instead of RequestURI.
+	uriRegex *regexp.Regexp
+
+	// branches are conditional responses.
+	branches []responseBranch
+
+	// fault is a network-level fault of response.
+	fault *fault
+
+	// chunking streams response body in chunks.
+	chunking *chunking
+
+	// handler serves response instead of httpmock.Server.
+	handler *requestHandler
+}
+
+// reset removes expectations.
+func (m *mock) reset() {
+	m.exp = nil
+	m.last = nil
+	m.srv.ResetExpectations()
+
+	// Server lock is held while onRequest is called, so mock lock is acquired after server lock is released.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.includes = nil
+	m.expectations = nil
+	m.async = nil
+	m.received = nil
+	m.sections = nil
+	m.signatures = nil
+	m.outage = nil
+	m.streamBodies = false
+	m.delay = 0
+	m.fault = nil
+	m.chunking = nil
+	m.handler = nil
+	m.branchSeq = 0
+
+	if m.release != nil {
+		close(m.release)
+		m.release = nil
+	}
+}
+
+// expect adds expectation to the server.
+func (m *mock) expect(e exp) {
+	if e.async {
+		m.srv.ExpectAsync(e.Expectation)
+	} else {
+		m.srv.Expect(e.Expectation)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.signatures = append(m.signatures, e.signatures...)
+
+	pe := PendingExpectation{
+		Expectation: e.Expectation, Async: e.async, Delay: e.delay,
+		uriRegex: e.uriRegex, branches: e.branches, fault: e.fault, chunking: e.chunking, handler: e.handler,
+	}
+
+	if e.include || e.jsonPaths || e.form || e.matchJSON {
+		inc := inclusion{
+			method:     e.Method,
+			requestURI: e.RequestURI,
+			body:       e.RequestBody,
+			jsonPaths:  e.jsonPaths,
+			form:       e.form,
+			matchJSON:  e.matchJSON,
+			remaining:  1,
+		}
+
+		if e.Unlimited {
+			inc.remaining = -1
+		} else if e.Repeated > 0 {
+			inc.remaining = e.Repeated
+		}
+
+		m.includes = append(m.includes, inc)
+	}
+
+	if e.async {
+		m.async = append(m.async, pe)
+	} else {
+		m.expectations = append(m.expectations, pe)
+	}
+}
+
+// pending returns expectations that are not met yet.
+func (m *mock) pending() []PendingExpectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make([]PendingExpectation, 0, len(m.expectations)+len(m.async))
+	res = append(res, m.expectations...)
+	res = append(res, m.async...)
+
+	return res
+}
+
+// history returns requests received in current scenario.
+func (m *mock) history() []receivedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]receivedRequest(nil), m.received...)
+}
+
+// expectCriticalSection adds expectation of no other requests between start and end requests.
+func (m *mock) expectCriticalSection(start, end string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sections = append(m.sections, criticalSection{start: start, end: end})
+}
+
+// checkCriticalSections fails if requests of a critical section were interleaved with other requests.
+func (m *mock) checkCriticalSections() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, cs := range m.sections {
+		started := -1
+
+		for i, r := range m.received {
+			if started == -1 {
+				if r.matches(cs.start) {
+					started = i
+				}
+
+				continue
+			}
+
+			if r.matches(cs.end) {
+				started = -1
+
+				continue
+			}
+
+			return fmt.Errorf("%w between %s and %s: %s %s",
+				ErrInterleavedRequests, cs.start, cs.end, r.method, r.requestURI)
+		}
+
+		if started != -1 {
+			return fmt.Errorf("%w: %s after %s", ErrMissingRequest, cs.end, cs.start)
+		}
+	}
+
+	return nil
+}
+
+// matches checks if request has the URI or the path.
+func (r receivedRequest) matches(uri string) bool {
+	path, _, _ := strings.Cut(r.requestURI, "?")
+
+	return r.requestURI == uri || path == uri
+}
+
+// onRequest prepares request body and tracks served expectations, it is called before request is served by srv.
+func (m *mock) onRequest(req *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.received = append(m.received, receivedRequest{
+		method:     req.Method,
+		requestURI: req.RequestURI,
+		header:     req.Header.Clone(),
+	})
+
+	m.srv.JSONComparer.Vars = m.vars
+
+	if m.outage != nil && m.outage.serve(time.Now()) {
+		req.RequestURI = outageURI
+
+		return
+	}
+
+	params := m.routeRequestURI(req)
+
+	if req.Body == nil {
+		m.routeBranch(req, nil)
+		m.srv.JSONComparer.Vars = m.requestVars(req, nil, params)
+
+		return
+	}
+
+	var (
+		body []byte
+		err  error
+		pr   = &progressReader{r: req.Body, start: time.Now()}
+	)
+
+	// Streamed body is only counted, so that expectations can not match its contents.
+	if m.streamBodies {
+		_, err = io.Copy(io.Discard, pr)
+	} else {
+		body, err = io.ReadAll(pr)
+	}
+
+	m.received[len(m.received)-1].progress = pr.progress
+	m.received[len(m.received)-1].body = body
+
+	if err != nil {
+		return
+	}
+
+	m.routeBranch(req, body)
+	m.srv.JSONComparer.Vars = m.requestVars(req, body, params)
+
+	m.verifySignatures(req, body)
+
+	body = m.projectRequestBody(req, body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Same order of matching as in httpmock.Server.
+	for i, e := range m.async {
+		if m.matches(req, body, e.Expectation) {
+			m.serve(e)
+			m.async = consume(m.async, i)
+
+			return
+		}
+	}
+
+	if len(m.expectations) > 0 && m.matches(req, body, m.expectations[0].Expectation) {
+		m.serve(m.expectations[0])
+		m.expectations = consume(m.expectations, 0)
+
+		return
+	}
+
+	// Unmatched request is served by stub or forwarded to upstream instead of failing.
+	if h := m.stub(req, body); h != nil {
+		m.handler = h
+	} else if m.passthrough != nil {
+		m.handler = m.passthrough
+	}
+}
+
+// serve prepares response of current request for expectation.
+func (m *mock) serve(e PendingExpectation) {
+	m.delay = e.Delay
+	m.fault = e.fault
+	m.chunking = e.chunking
+	m.handler = e.handler
+}
+
+// consume decrements remaining number of requests and removes met expectation.
+func consume(expectations []PendingExpectation, i int) []PendingExpectation {
+	e := expectations[i]
+
+	if e.Unlimited {
+		return expectations
+	}
+
+	if e.Repeated > 0 {
+		e.Repeated--
+		expectations[i] = e
+
+		if e.Repeated > 0 {
+			return expectations
+		}
+	}
+
+	return append(expectations[:i], expectations[i+1:]...)
+}
+
+// matches checks request against expectation in same way as httpmock.Server.
+func (m *mock) matches(req *http.Request, body []byte, e httpmock.Expectation) bool {
+	if e.Method != "" && e.Method != req.Method {
+		return false
+	}
+
+	if e.RequestURI != "" && e.RequestURI != req.RequestURI {
+		return false
+	}
+
+	for k, v := range e.RequestHeader {
+		if req.Header.Get(k) != v {
+			return false
+		}
+	}
+
+	for n, v := range e.RequestCookie {
+		if c, err := req.Cookie(n); err != nil || c.Value != v {
+			return false
+		}
+	}
+
+	if e.RequestBody == nil {
+		return true
+	}
+
+	if !json5.Valid(e.RequestBody) || !json5.Valid(body) {
+		return bytes.Equal(e.RequestBody, body)
+	}
+
+	expected, err := json5.Downgrade(e.RequestBody)
+	if err != nil {
+		return false
+	}
+
+	// Comparing with a copy of variables to avoid side effects.
+	jc := m.srv.JSONComparer
+	if jc.Vars != nil {
+		v := &shared.Vars{VarPrefix: jc.Vars.VarPrefix}
+
+		for k, val := range jc.Vars.GetAll() {
+			v.Set(k, val)
+		}
+
+		jc.Vars = v
+	}
+
+	return jc.FailNotEqual(expected, body) == nil
+}
+
+// inclusion is an expectation of request body that may have additional fields and array elements,
+// an expectation of values by JSON paths, or an expectation of urlencoded form in any order.
+type inclusion struct {
+	method     string
+	requestURI string
+	body       []byte
+
+	// jsonPaths means body is an object of expected values by JSON paths.
+	jsonPaths bool
+
+	// form means body is an urlencoded form with sorted keys.
+	form bool
+
+	// matchJSON means arrays of body are matched in order.
+	matchJSON bool
+
+	// remaining is a number of requests to match, negative for unlimited.
+	remaining int
+}
+
+// projectRequestBody returns projection of request body on a matching inclusion.
+func (m *mock) projectRequestBody(req *http.Request, body []byte) []byte {
+	if len(m.includes) == 0 {
+		return body
+	}
+
+	jc := m.srv.JSONComparer
+	wildcard := func(s string) bool {
+		return (jc.IgnoreDiff != "" && s == jc.IgnoreDiff) || (jc.Vars != nil && jc.Vars.IsVar(s))
+	}
+
+	for i, inc := range m.includes {
+		if (inc.method != "" && inc.method != req.Method) || inc.requestURI != req.RequestURI {
+			continue
+		}
+
+		project := includedJSON
+
+		switch {
+		case inc.jsonPaths:
+			project = jsonPathsProjection
+		case inc.form:
+			project = formProjection
+		case inc.matchJSON:
+			project = matchedJSON
+		}
+
+		projected, ok := project(inc.body, body, wildcard)
+		if !ok {
+			continue
+		}
+
+		if inc.remaining > 0 {
+			m.includes[i].remaining--
+
+			if m.includes[i].remaining == 0 {
+				m.includes = append(m.includes[:i], m.includes[i+1:]...)
+			}
+		}
+
+		return projected
+	}
+
+	return body
+}
+
+// String describes expectation.
+func (p PendingExpectation) String() string {
+	res := p.Method + " " + p.RequestURI
+
+	if p.Async {
+		res += ", async"
+	}
+
+	switch {
+	case p.Unlimited:
+		res += ", unlimited"
+	case p.Repeated > 1:
+		res += fmt.Sprintf(", %d times", p.Repeated)
+	}
+
+	if len(p.RequestHeader) > 0 {
+		res += fmt.Sprintf(", headers: %v", p.RequestHeader)
+	}
+
+	if p.Delay > 0 {
+		res += ", delay: " + p.Delay.String()
+	}
+
+	if p.fault != nil {
+		res += ", fault: " + p.fault.String()
+	}
+
+	if p.chunking != nil {
+		res += ", chunked"
+	}
+
+	if p.handler != nil {
+		res += ", handler"
+	}
+
+	if p.RequestBody != nil {
+		res += ", body: " + string(p.RequestBody)
+	}
+
+	status := p.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return res + fmt.Sprintf(", responds with status %d", status)
+}
@@ -0,0 +1,129 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bool64/httpmock"
+)
+
+type namedRequestsCtxKey struct{}
+
+// namedRequests keeps named requests of a scenario.
+type namedRequests struct {
+	mu sync.Mutex
+
+	// byName has forked client of a pending named request or a snapshot of client with received response.
+	byName map[string]*namedRequest
+
+	// current has name of pending request by service.
+	current map[string]string
+}
+
+type namedRequest struct {
+	service  string
+	snapshot *httpmock.Client
+}
+
+func (l *LocalClient) iRequestNamedWithMethodAndURI(ctx context.Context, service, name, method, uri string) (context.Context, error) {
+	ctx, err := l.iRequestWithMethodAndURI(ctx, service, method, uri)
+	if err != nil {
+		return ctx, err
+	}
+
+	nr, ok := ctx.Value(namedRequestsCtxKey{}).(*namedRequests)
+	if !ok {
+		nr = &namedRequests{
+			byName:  make(map[string]*namedRequest),
+			current: make(map[string]string),
+		}
+		ctx = context.WithValue(ctx, namedRequestsCtxKey{}, nr)
+	}
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	service = serviceName(service)
+	nr.byName[name] = &namedRequest{service: service}
+	nr.current[service] = name
+
+	return ctx, nil
+}
+
+// snapshotNamed keeps state of pending named request of a service before client is reset for another request.
+func snapshotNamed(ctx context.Context, service string, c *httpmock.Client) {
+	nr, ok := ctx.Value(namedRequestsCtxKey{}).(*namedRequests)
+	if !ok {
+		return
+	}
+
+	nr.mu.Lock()
+	defer nr.mu.Unlock()
+
+	service = serviceName(service)
+
+	name, ok := nr.current[service]
+	if !ok {
+		return
+	}
+
+	snapshot := *c
+	nr.byName[name].snapshot = &snapshot
+
+	delete(nr.current, service)
+}
+
+// expectNamedResponse checks response of a named request.
+func (l *LocalClient) expectNamedResponse(ctx context.Context, name string, expect func(c *httpmock.Client) error) (context.Context, error) {
+	nr, _ := ctx.Value(namedRequestsCtxKey{}).(*namedRequests)
+	if nr == nil {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownRequest, name)
+	}
+
+	nr.mu.Lock()
+	r, found := nr.byName[name]
+	nr.mu.Unlock()
+
+	if !found {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownRequest, name)
+	}
+
+	// Pending request is checked with regular expectations, so that it is sent if necessary.
+	if r.snapshot == nil {
+		return l.expectResponse(ctx, r.service, expect)
+	}
+
+	if r.snapshot.Details().Resp == nil {
+		return ctx, fmt.Errorf("%w for request %s, it was not sent before next request", ErrNoResponse, name)
+	}
+
+	return l.assertion(ctx, expect(r.snapshot))
+}
+
+func (l *LocalClient) responseOfShouldHaveStatus(ctx context.Context, name, statusOrCode string) (context.Context, error) {
+	code, err := statusCode(statusOrCode)
+	if err != nil {
+		return ctx, err
+	}
+
+	return l.expectNamedResponse(ctx, name, func(c *httpmock.Client) error {
+		return c.ExpectResponseStatus(code)
+	})
+}
+
+func (l *LocalClient) responseOfShouldHaveHeader(ctx context.Context, name, key, value string) (context.Context, error) {
+	return l.expectNamedResponse(ctx, name, func(c *httpmock.Client) error {
+		return c.ExpectResponseHeader(key, value)
+	})
+}
+
+func (l *LocalClient) responseOfShouldHaveBody(ctx context.Context, name, bodyDoc string) (context.Context, error) {
+	ctx = l.VS.PrepareContext(ctx)
+
+	return l.expectNamedResponse(ctx, name, func(c *httpmock.Client) error {
+		return c.ExpectResponseBodyCallback(func(received []byte) error {
+			return augmentBodyErr(l.assertJSON(ctx, []byte(bodyDoc), received, false))
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package httpsteps
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// step adds step definition of local client with StepPatterns, StepPrefix, StrictServices and IsolatedVars applied.
+func (l *LocalClient) step(s stepContext, expr string, handler interface{}) {
+	if l.DryRun && dryRunChecks.MatchString(expr) {
+		handler = dryRunStep(expr, false, handler, l.VS, l.knownService)
+	}
+
+	if l.StrictServices {
+		handler = l.strictServicesStep(expr, handler)
+	}
+
+	expr = prefixed(l.StepPrefix, stepExpression(s, l.StepPatterns, expr))
+
+	if l.IsolatedVars {
+		handler = l.withIsolatedVars(handler)
+	}
+
+	l.StepUsage.register(expr)
+	step(s, expr, handler)
+}
+
+// step adds step definition of external server with StepPatterns and StepPrefix applied.
+func (e *ExternalServer) step(s stepContext, expr string, handler interface{}) {
+	if e.DryRun && dryRunChecks.MatchString(expr) {
+		handler = dryRunStep(expr, true, handler, e.VS, func(_ context.Context, service string) bool {
+			return e.knownService(service)
+		})
+	}
+
+	expr = prefixed(e.StepPrefix, stepExpression(s, e.StepPatterns, expr))
+
+	e.StepUsage.register(expr)
+	step(s, expr, handler)
+}
+
+// step adds step definition of round trip with StepPatterns, StepPrefix and DryRun of local client
+// or external server applied, args are kinds of captured values.
+func (r *RoundTrip) step(s stepContext, expr string, handler interface{}, args []dryRunArg) {
+	if r.local.DryRun || r.external.DryRun {
+		handler = r.dryRunStep(expr, handler, args)
+	}
+
+	step(s, prefixed(r.StepPrefix, stepExpression(s, r.StepPatterns, expr)), handler)
+}
+
+// prefixed returns step expression that starts with prefix.
+func prefixed(prefix, expr string) string {
+	if prefix == "" {
+		return expr
+	}
+
+	return "^" + regexp.QuoteMeta(prefix) + strings.TrimPrefix(expr, "^")
+}
+
+type isolatedVarsCtxKey struct {
+	l *LocalClient
+}
+
+// beforeIsolatedScenario creates storage of variables of local client for a new scenario.
+func (l *LocalClient) beforeIsolatedScenario(ctx context.Context, _ *godog.Scenario) (context.Context, error) {
+	if !l.IsolatedVars {
+		return ctx, nil
+	}
+
+	own, _ := l.VS.Vars(context.Background())
+
+	return context.WithValue(ctx, isolatedVarsCtxKey{l: l}, own), nil
+}
+
+// withIsolatedVars wraps step handler to use storage of variables of local client.
+//
+// Storage is not accessible by its context key, so the key is detected with a probe context
+// that has nothing but the storage.
+func (l *LocalClient) withIsolatedVars(handler interface{}) interface{} {
+	h := reflect.ValueOf(handler)
+
+	return reflect.MakeFunc(h.Type(), func(args []reflect.Value) []reflect.Value {
+		outer, ok := args[0].Interface().(context.Context)
+		if !ok || outer == nil {
+			return h.Call(args)
+		}
+
+		own, ok := outer.Value(isolatedVarsCtxKey{l: l}).(context.Context)
+		if !ok {
+			return h.Call(args)
+		}
+
+		args[0] = reflect.ValueOf(varsLayer{Context: outer, probe: own, vars: own}).Convert(args[0].Type())
+		res := h.Call(args)
+
+		// Storage of outer context is restored for following steps.
+		if ctx, ok := res[0].Interface().(context.Context); ok && ctx != nil {
+			res[0] = reflect.ValueOf(varsLayer{Context: ctx, probe: own, vars: outer}).Convert(res[0].Type())
+		}
+
+		return res
+	}).Interface()
+}
+
+// varsLayer overrides storage of variables in context.
+type varsLayer struct {
+	context.Context
+	probe context.Context
+	vars  context.Context
+}
+
+// Value returns values of vars from overriding context and other values from the parent.
+func (c varsLayer) Value(key interface{}) interface{} {
+	if c.probe.Value(key) != nil {
+		return c.vars.Value(key)
+	}
+
+	return c.Context.Value(key)
+}
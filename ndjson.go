@@ -0,0 +1,53 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// ndjsonLines splits newline delimited JSON into non-empty lines.
+func ndjsonLines(body []byte) [][]byte {
+	var lines [][]byte
+
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// assertNDJSON compares every received line with expected line as JSON.
+func (l *LocalClient) assertNDJSON(ctx context.Context, bodyDoc string, received []byte) error {
+	expected, rcv := ndjsonLines([]byte(bodyDoc)), ndjsonLines(received)
+
+	if len(expected) != len(rcv) {
+		return augmentBodyErr(ctx, fmt.Errorf("%w: %d expected, %d received", ErrUnexpectedLines, len(expected), len(rcv)))
+	}
+
+	var err error
+
+	// Variables collected in a line are available in following lines.
+	for i, exp := range expected {
+		if ctx, err = l.assertJSON(ctx, exp, rcv[i], false); err != nil {
+			return augmentBodyErr(ctx, fmt.Errorf("line %d: %w", i+1, err))
+		}
+	}
+
+	return nil
+}
+
+func (l *LocalClient) iShouldHaveResponseWithNDJSONLines(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.assertNDJSON(ctx, bodyDoc, received)
+	})
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithNDJSONLines(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.assertNDJSON(ctx, bodyDoc, received)
+	})
+}
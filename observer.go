@@ -0,0 +1,146 @@
+package httpsteps
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes a request sent by LocalClient or received by ExternalServer.
+type RequestInfo struct {
+	// Service is a name of service.
+	Service string
+
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// ResponseInfo describes a response of request.
+type ResponseInfo struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Duration is an elapsed time from sending request to receiving response body.
+	Duration time.Duration
+
+	// Err is a failure to send request or to receive response, other fields are empty then.
+	Err error
+}
+
+// OnRoundTrip registers an observer of requests sent by client, e.g. for custom logging or metrics.
+//
+// Observer is called for every sent request, including concurrent requests and retries,
+// it must be safe for concurrent use. Request body is read into memory if there are observers,
+// including a body that is streamed from file.
+func (l *LocalClient) OnRoundTrip(observer func(req RequestInfo, resp ResponseInfo)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.observers = append(l.observers, observer)
+}
+
+// OnRoundTrip registers an observer of requests received by mocked services and of mocked responses.
+//
+// Request body is read into memory if there are observers, including a body that is received as a stream.
+// Observer must be safe for concurrent use.
+func (e *ExternalServer) OnRoundTrip(observer func(req RequestInfo, resp ResponseInfo)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.observers = append(e.observers, observer)
+}
+
+// roundTripObservers returns registered observers.
+func (e *ExternalServer) roundTripObservers() []func(req RequestInfo, resp ResponseInfo) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.observers
+}
+
+// observe calls observers with request and response.
+func observe(observers []func(req RequestInfo, resp ResponseInfo), req RequestInfo, resp ResponseInfo) {
+	for _, o := range observers {
+		o(req, resp)
+	}
+}
+
+// requestBody returns a copy of request body that is not sent yet, body without GetBody is read into memory.
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	if req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		return body
+	}
+
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+
+	defer rc.Close() //nolint:errcheck // Body is in memory.
+
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return nil
+	}
+
+	return body
+}
+
+// observedWriter keeps status and body of a mocked response.
+type observedWriter struct {
+	http.ResponseWriter
+
+	status int
+	body   bytes.Buffer
+}
+
+func (w *observedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *observedWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.body.Write(p)
+
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends buffered response to client.
+func (w *observedWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over connection to inject a fault.
+func (w *observedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, http.ErrNotSupported
+}
@@ -0,0 +1,156 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bool64/httpmock"
+)
+
+// outageURI is a request URI that requests received during outage are rewritten to, so that they are served
+// with an unlimited async expectation instead of regular expectations.
+const outageURI = "/.httpsteps/service-unavailable"
+
+// Outage describes a window of unavailability of an external service.
+type Outage struct {
+	// Duration is a length of the window.
+	Duration time.Duration
+
+	// Start is a time of the first request, window starts with it.
+	// Zero value means no requests were received.
+	Start time.Time
+
+	// End is a time when service recovers.
+	End time.Time
+
+	// Failed has times of requests that were served with status "Service Unavailable".
+	Failed []time.Time
+
+	// Recovered is a time of the first request after the window, zero value means no such request.
+	Recovered time.Time
+}
+
+// serve returns true if request received at given time falls into outage window.
+func (o *Outage) serve(now time.Time) bool {
+	if o.Start.IsZero() {
+		o.Start = now
+		o.End = now.Add(o.Duration)
+	}
+
+	if now.Before(o.End) {
+		o.Failed = append(o.Failed, now)
+
+		return true
+	}
+
+	if o.Recovered.IsZero() {
+		o.Recovered = now
+	}
+
+	return false
+}
+
+// Outage returns state of outage window of a service.
+func (e *ExternalServer) Outage(service string) (Outage, error) {
+	e.mu.RLock()
+	m, found := e.mocks[service]
+	e.mu.RUnlock()
+
+	if !found {
+		return Outage{}, fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	return m.outageState(service)
+}
+
+// outageState returns a copy of outage window.
+func (m *mock) outageState(service string) (Outage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.outage == nil {
+		return Outage{}, fmt.Errorf("%w: %s", ErrNoOutage, service)
+	}
+
+	o := *m.outage
+	o.Failed = append([]time.Time(nil), o.Failed...)
+
+	return o, nil
+}
+
+func (e *ExternalServer) serviceIsUnavailableThenRecovers(ctx context.Context, service, duration string) (context.Context, error) {
+	d, err := time.ParseDuration(duration)
+	if err != nil || d <= 0 {
+		return ctx, fmt.Errorf("%w: outage duration %q", ErrInvalidValue, duration)
+	}
+
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.srv.ExpectAsync(httpmock.Expectation{
+		RequestURI:     outageURI,
+		Status:         http.StatusServiceUnavailable,
+		ResponseHeader: map[string]string{"Retry-After": strconv.Itoa(int(math.Ceil(d.Seconds())))},
+		Unlimited:      true,
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.outage = &Outage{Duration: d}
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivesRequestWithinAfterRecovery(ctx context.Context, service, within string) (context.Context, error) {
+	d, err := time.ParseDuration(within)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %q", ErrInvalidValue, within)
+	}
+
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	o, err := m.outageState(service)
+	if err != nil {
+		return ctx, err
+	}
+
+	if o.Recovered.IsZero() {
+		return ctx, fmt.Errorf("%w: no requests to %s after recovery", ErrMissingRequest, service)
+	}
+
+	if late := o.Recovered.Sub(o.End); late > d {
+		return ctx, fmt.Errorf("%w: first request to %s after recovery is late by %s, expected within %s",
+			ErrDurationExceeded, service, late.Truncate(time.Millisecond), d)
+	}
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivesAtMostRequestsDuringOutage(ctx context.Context, service string, n int) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	o, err := m.outageState(service)
+	if err != nil {
+		return ctx, err
+	}
+
+	if len(o.Failed) > n {
+		return ctx, fmt.Errorf("%w: %s received %d requests during outage, expected at most %d",
+			ErrUnexpectedRequests, service, len(o.Failed), n)
+	}
+
+	return ctx, nil
+}
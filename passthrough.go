@@ -0,0 +1,82 @@
+package httpsteps
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/bool64/httpmock"
+)
+
+// passthroughConfig is an upstream and a recorder of forwarded requests.
+type passthroughConfig struct {
+	upstream *url.URL
+	record   func(exchange httpmock.Expectation)
+}
+
+// WithPassthrough is an option of Add to forward requests that do not match expectations
+// to a real upstream instead of failing, so that large dependencies can be mocked incrementally.
+//
+// Optional record function receives every forwarded request with the response of upstream,
+// it must be safe for concurrent use.
+func (e *ExternalServer) WithPassthrough(upstream *url.URL, record func(exchange httpmock.Expectation)) func(mock *httpmock.Server) {
+	return func(*httpmock.Server) {
+		e.configure(func(m *mock) {
+			m.passthrough = passthroughHandler(passthroughConfig{upstream: upstream, record: record})
+		})
+	}
+}
+
+// passthroughHandler creates a handler that forwards requests to upstream.
+func passthroughHandler(cfg passthroughConfig) *requestHandler {
+	director := httputil.NewSingleHostReverseProxy(cfg.upstream).Director
+
+	return &requestHandler{handler: func(rw http.ResponseWriter, req *http.Request) {
+		p := &httputil.ReverseProxy{
+			Director: func(r *http.Request) {
+				director(r)
+				r.Host = cfg.upstream.Host
+			},
+		}
+
+		if cfg.record != nil {
+			exchange := httpmock.Expectation{Method: req.Method, RequestURI: req.RequestURI}
+
+			if req.Body != nil {
+				exchange.RequestBody, _ = io.ReadAll(req.Body) //nolint:errcheck // Body is already received.
+				req.Body = io.NopCloser(bytes.NewReader(exchange.RequestBody))
+			}
+
+			p.ModifyResponse = func(resp *http.Response) error {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return err
+				}
+
+				if err := resp.Body.Close(); err != nil {
+					return err
+				}
+
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+
+				exchange.Status = resp.StatusCode
+				exchange.ResponseBody = body
+				exchange.ResponseHeader = make(map[string]string, len(resp.Header))
+
+				for k := range resp.Header {
+					if k != "Content-Length" && k != "Date" {
+						exchange.ResponseHeader[k] = resp.Header.Get(k)
+					}
+				}
+
+				cfg.record(exchange)
+
+				return nil
+			}
+		}
+
+		p.ServeHTTP(rw, req)
+	}}
+}
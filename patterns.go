@@ -0,0 +1,147 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/cucumber/godog"
+)
+
+// StepPatterns maps default step expressions to custom expressions, e.g. translations of steps
+// for feature files in other languages (# language: de).
+//
+// Custom expression must have the same capturing groups in the same order as default expression,
+// because captured values are passed to the same step handler. Steps that are not in the map keep
+// their default expressions.
+//
+//	local.StepPatterns = httpsteps.StepPatterns{
+//		`^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`: `^ich sende(.*) eine "([^"]*)" Anfrage an (.*)$`,
+//		`^I should have(.*) response with status "([^"]*)"$`:               `^ich sollte(.*) eine Antwort mit Status "([^"]*)" erhalten$`,
+//	}
+type StepPatterns map[string]string
+
+// Validate checks that custom expressions compile and have the same number of capturing groups
+// as default expressions.
+//
+// Steps are not registered with invalid patterns, scenarios fail with this error instead.
+func (p StepPatterns) Validate() error {
+	exprs := make([]string, 0, len(p))
+
+	for expr := range p {
+		exprs = append(exprs, expr)
+	}
+
+	sort.Strings(exprs)
+
+	for _, expr := range exprs {
+		if err := p.check(expr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// check returns error if custom expression of a step is invalid.
+func (p StepPatterns) check(expr string) error {
+	custom := p[expr]
+
+	re, err := regexp.Compile(custom)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidStepPattern, custom, err)
+	}
+
+	def, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("%w: default expression %s: %v", ErrInvalidStepPattern, expr, err)
+	}
+
+	if n, expected := re.NumSubexp(), def.NumSubexp(); n != expected {
+		return fmt.Errorf("%w: %s has %d capturing groups, %d expected by %s",
+			ErrInvalidStepPattern, custom, n, expected, expr)
+	}
+
+	return nil
+}
+
+// expression returns custom expression of a step or default expression if there is no valid custom one.
+func (p StepPatterns) expression(expr string) string {
+	if _, ok := p[expr]; !ok || p.check(expr) != nil {
+		return expr
+	}
+
+	return p[expr]
+}
+
+// validatePatterns returns the first error of step patterns.
+func validatePatterns(patterns ...StepPatterns) error {
+	for _, p := range patterns {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failScenarios makes scenarios of context fail with error.
+func failScenarios(s *godog.ScenarioContext, err error) {
+	s.Before(func(ctx context.Context, _ *godog.Scenario) (context.Context, error) {
+		return ctx, err
+	})
+}
+
+// stepContext adds step definitions, it is implemented by *godog.ScenarioContext.
+type stepContext interface {
+	Step(expr, stepFunc interface{})
+}
+
+// patternsContext is a scenario context with custom step patterns.
+type patternsContext struct {
+	stepContext
+	patterns StepPatterns
+}
+
+// withPatterns returns scenario context with custom step patterns if there are any.
+func withPatterns(s *godog.ScenarioContext, custom StepPatterns) stepContext {
+	if len(custom) == 0 {
+		return s
+	}
+
+	return patternsContext{stepContext: s, patterns: custom}
+}
+
+// stepExpression returns custom expression of a step, patterns of scenario context take precedence over default patterns.
+func stepExpression(s stepContext, patterns StepPatterns, expr string) string {
+	if c, ok := s.(patternsContext); ok {
+		if _, found := c.patterns[expr]; found {
+			return c.patterns.expression(expr)
+		}
+	}
+
+	return patterns.expression(expr)
+}
+
+// StepDefinition is a step expression with its handler.
+//
+// Handler can be registered with another expression to add an alias of a step.
+//
+//	for _, d := range local.StepDefinitions() {
+//		if d.Expression == `^I should have(.*) response with status "([^"]*)"$` {
+//			s.Step(`^the(.*) response status should be "([^"]*)"$`, d.Handler)
+//		}
+//	}
+type StepDefinition struct {
+	Expression string
+	Handler    interface{}
+}
+
+// stepCollector collects step definitions instead of adding them to scenario context.
+type stepCollector []StepDefinition
+
+// Step adds step definition to collection.
+func (c *stepCollector) Step(expr, stepFunc interface{}) {
+	*c = append(*c, StepDefinition{Expression: fmt.Sprint(expr), Handler: stepFunc})
+}
@@ -0,0 +1,53 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bool64/httpmock"
+	"github.com/cenkalti/backoff/v4"
+)
+
+func (l *LocalClient) iPollUntilStatus(ctx context.Context, service, method, uri, interval, limit, statusOrCode string) (context.Context, error) {
+	every, err := time.ParseDuration(interval)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: polling interval: %s", ErrInvalidValue, err.Error())
+	}
+
+	upTo, err := time.ParseDuration(limit)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: polling limit: %s", ErrInvalidValue, err.Error())
+	}
+
+	if ctx, err = l.iRequestWithMethodAndURI(ctx, service, method, uri); err != nil {
+		return ctx, err
+	}
+
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	c.AllowRetries(pollingBackOff(every, upTo))
+
+	ctx, err = l.iShouldHaveResponseWithStatus(ctx, service, statusOrCode)
+	if err != nil {
+		return ctx, fmt.Errorf("polling every %s for up to %s: %w", every, upTo, err)
+	}
+
+	return ctx, nil
+}
+
+// pollingBackOff repeats requests with a fixed interval until time limit is reached.
+func pollingBackOff(every, upTo time.Duration) httpmock.RetryBackOff {
+	deadline := time.Now().Add(upTo)
+
+	return httpmock.RetryBackOffFunc(func() time.Duration {
+		if time.Now().Add(every).After(deadline) {
+			return backoff.Stop
+		}
+
+		return every
+	})
+}
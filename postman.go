@@ -0,0 +1,194 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// SavedRequest is a named request that can be sent with a step, e.g. imported from Postman collection.
+//
+// Variables are replaced in URI, header values and body when request is sent.
+type SavedRequest struct {
+	Method  string
+	URI     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// AddSavedRequest registers named request.
+func (l *LocalClient) AddSavedRequest(name string, request SavedRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.saved == nil {
+		l.saved = make(map[string]SavedRequest)
+	}
+
+	l.saved[name] = request
+}
+
+// postmanItem is a request or a folder of Postman collection (format v2.1).
+type postmanItem struct {
+	Name    string        `json:"name"`
+	Item    []postmanItem `json:"item"`
+	Request *struct {
+		Method string          `json:"method"`
+		URL    json.RawMessage `json:"url"`
+		Header []postmanValue  `json:"header"`
+		Body   *struct {
+			Mode       string         `json:"mode"`
+			Raw        string         `json:"raw"`
+			URLEncoded []postmanValue `json:"urlencoded"`
+		} `json:"body"`
+	} `json:"request"`
+}
+
+type postmanValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// postmanVar matches Postman variable reference, e.g. {{userId}}.
+var postmanVar = regexp.MustCompile(`{{\s*([^{}$\s]+)\s*}}`)
+
+// postmanVars replaces Postman variable references with variables of scenario, e.g. {{userId}} with $userId.
+func postmanVars(s string) string {
+	return postmanVar.ReplaceAllString(s, "$$$1")
+}
+
+// LoadPostmanCollection registers requests of Postman collection (format v2.1) as saved requests by item name,
+// requests in folders are registered by their own names.
+//
+// Host of request URL (e.g. {{baseUrl}}) is replaced with base URL of service, other variable references
+// (e.g. {{userId}}) become variables of scenario (e.g. $userId). Raw and urlencoded bodies are supported.
+func (l *LocalClient) LoadPostmanCollection(filePath string) error {
+	data, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return err
+	}
+
+	var collection postmanItem
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return fmt.Errorf("failed to load Postman collection %s: %w", filePath, err)
+	}
+
+	return l.addPostmanItems(collection.Item)
+}
+
+func (l *LocalClient) addPostmanItems(items []postmanItem) error {
+	for _, it := range items {
+		if it.Request == nil {
+			if err := l.addPostmanItems(it.Item); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		uri, err := postmanURI(it.Request.URL)
+		if err != nil {
+			return fmt.Errorf("failed to load URL of Postman request %s: %w", it.Name, err)
+		}
+
+		r := SavedRequest{Method: it.Request.Method, URI: uri, Headers: map[string]string{}}
+
+		for _, h := range it.Request.Header {
+			if !h.Disabled {
+				r.Headers[h.Key] = postmanVars(h.Value)
+			}
+		}
+
+		if b := it.Request.Body; b != nil {
+			switch b.Mode {
+			case "raw":
+				r.Body = []byte(postmanVars(b.Raw))
+			case "urlencoded":
+				form := url.Values{}
+
+				for _, v := range b.URLEncoded {
+					if !v.Disabled {
+						form.Add(v.Key, v.Value)
+					}
+				}
+
+				// Variable references are kept unescaped to be replaced.
+				body := strings.NewReplacer("%7B%7B", "{{", "%7D%7D", "}}").Replace(form.Encode())
+				r.Body = []byte(postmanVars(body))
+				r.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+			default:
+				return fmt.Errorf("%w: body mode %s of Postman request %s", ErrInvalidValue, b.Mode, it.Name)
+			}
+		}
+
+		l.AddSavedRequest(it.Name, r)
+	}
+
+	return nil
+}
+
+// postmanURI returns request URI of Postman URL without host.
+func postmanURI(u json.RawMessage) (string, error) {
+	var raw string
+
+	if err := json.Unmarshal(u, &raw); err != nil {
+		var structured struct {
+			Raw string `json:"raw"`
+		}
+
+		if err := json.Unmarshal(u, &structured); err != nil {
+			return "", err
+		}
+
+		raw = structured.Raw
+	}
+
+	// Scheme and host are dropped, with or without variable references.
+	if i := strings.Index(raw, "://"); i != -1 {
+		raw = raw[i+3:]
+	}
+
+	if i := strings.IndexAny(raw, "/?"); i != -1 {
+		raw = raw[i:]
+	} else {
+		raw = "/"
+	}
+
+	if !strings.HasPrefix(raw, "/") {
+		raw = "/" + raw
+	}
+
+	return postmanVars(raw), nil
+}
+
+func (l *LocalClient) iSendSavedRequest(ctx context.Context, service, name string) (context.Context, error) {
+	l.mu.RLock()
+	r, found := l.saved[name]
+	l.mu.RUnlock()
+
+	if !found {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownRequest, name)
+	}
+
+	ctx, err := l.iRequestWithMethodAndURI(ctx, service, r.Method, r.URI)
+	if err != nil {
+		return ctx, err
+	}
+
+	for k, v := range r.Headers {
+		if ctx, err = l.iRequestWithHeader(ctx, service, k, v); err != nil {
+			return ctx, err
+		}
+	}
+
+	if r.Body == nil {
+		return ctx, nil
+	}
+
+	return l.iRequestWithBody(ctx, service, string(r.Body))
+}
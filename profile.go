@@ -0,0 +1,165 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// ResponseProfile is a named bundle of response expectations, e.g. an envelope mandated by API style guide.
+//
+// Variables are replaced in header values and schema when profile is applied.
+type ResponseProfile struct {
+	// Status is an expected status phrase or code, optional.
+	Status string
+
+	// Headers are expected response headers, optional.
+	Headers map[string]string
+
+	// Schema is a JSON schema of response body, optional.
+	Schema json.RawMessage
+}
+
+// AddResponseProfile registers named response profile.
+func (l *LocalClient) AddResponseProfile(name string, profile ResponseProfile) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.profiles == nil {
+		l.profiles = make(map[string]ResponseProfile)
+	}
+
+	l.profiles[name] = profile
+}
+
+// LoadResponseProfiles registers response profiles from YAML or JSON file.
+//
+// File contains a map of profiles by name, schema can be defined as YAML or as a string with JSON.
+//
+//	standard-error:
+//	  status: Bad Request
+//	  headers:
+//	    Content-Type: application/json
+//	  schema:
+//	    type: object
+//	    required: [error]
+func (l *LocalClient) LoadResponseProfiles(filePath string) error {
+	data, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return err
+	}
+
+	var profiles map[string]struct {
+		Status  string            `yaml:"status"`
+		Headers map[string]string `yaml:"headers"`
+		Schema  interface{}       `yaml:"schema"`
+	}
+
+	if err := yaml.Unmarshal(data, &profiles); err != nil {
+		return fmt.Errorf("failed to load response profiles from %s: %w", filePath, err)
+	}
+
+	for name, p := range profiles {
+		profile := ResponseProfile{
+			Status:  p.Status,
+			Headers: p.Headers,
+		}
+
+		switch s := p.Schema.(type) {
+		case nil:
+		case string:
+			profile.Schema = json.RawMessage(s)
+		default:
+			if profile.Schema, err = json.Marshal(s); err != nil {
+				return fmt.Errorf("failed to load schema of response profile %s: %w", name, err)
+			}
+		}
+
+		l.AddResponseProfile(name, profile)
+	}
+
+	return nil
+}
+
+func (l *LocalClient) iShouldHaveProfileResponse(ctx context.Context, name, service string) (context.Context, error) {
+	l.mu.RLock()
+	profile, found := l.profiles[name]
+	l.mu.RUnlock()
+
+	if !found {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+
+	ctx, err := l.expectProfile(ctx, service, profile)
+	if err != nil {
+		return ctx, fmt.Errorf("%s response: %w", name, err)
+	}
+
+	return ctx, nil
+}
+
+func (l *LocalClient) expectProfile(ctx context.Context, service string, profile ResponseProfile) (context.Context, error) {
+	var err error
+
+	if profile.Status != "" {
+		if ctx, err = l.iShouldHaveResponseWithStatus(ctx, service, profile.Status); err != nil {
+			return ctx, err
+		}
+	}
+
+	keys := make([]string, 0, len(profile.Headers))
+	for k := range profile.Headers {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ctx, value, err := l.VS.Replace(ctx, []byte(profile.Headers[k]))
+		if err != nil {
+			return ctx, fmt.Errorf("failed to replace vars in header %s: %w", k, err)
+		}
+
+		if ctx, err = l.iShouldHaveResponseWithHeader(ctx, service, k, string(value)); err != nil {
+			return ctx, err
+		}
+	}
+
+	if len(profile.Schema) == 0 {
+		return ctx, nil
+	}
+
+	ctx, schema, err := l.VS.Replace(ctx, profile.Schema)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to replace vars in schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("profile.json", bytes.NewReader(schema)); err != nil {
+		return ctx, fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	sch, err := compiler.Compile("profile.json")
+	if err != nil {
+		return ctx, fmt.Errorf("failed to compile schema: %w", err)
+	}
+
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		var v interface{}
+
+		d := json.NewDecoder(bytes.NewReader(received))
+		d.UseNumber()
+
+		if err := d.Decode(&v); err != nil {
+			return augmentBodyErr(ctx, fmt.Errorf("failed to unmarshal received body: %w", err))
+		}
+
+		return augmentBodyErr(ctx, sch.Validate(v))
+	})
+}
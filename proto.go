@@ -0,0 +1,137 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufContentType is a content type of request bodies encoded as protobuf.
+const ProtobufContentType = "application/x-protobuf"
+
+// AddDescriptorSet registers protobuf message types to encode request and decode response bodies,
+// descriptor set can be produced with `protoc --include_imports --descriptor_set_out`.
+func (l *LocalClient) AddDescriptorSet(fds *descriptorpb.FileDescriptorSet) error {
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return fmt.Errorf("failed to load descriptor set: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.protoFiles == nil {
+		l.protoFiles = &protoregistry.Files{}
+	}
+
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if _, e := l.protoFiles.FindFileByPath(fd.Path()); e == nil {
+			return true
+		}
+
+		err = l.protoFiles.RegisterFile(fd)
+
+		return err == nil
+	})
+
+	return err
+}
+
+// AddDescriptorSetFile registers protobuf message types from a binary descriptor set file.
+func (l *LocalClient) AddDescriptorSetFile(filePath string) error {
+	data, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return err
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, fds); err != nil {
+		return fmt.Errorf("failed to decode descriptor set %s: %w", filePath, err)
+	}
+
+	return l.AddDescriptorSet(fds)
+}
+
+// protoMessage creates an empty message of a registered type.
+func (l *LocalClient) protoMessage(messageType string) (*dynamicpb.Message, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.protoFiles != nil {
+		d, err := l.protoFiles.FindDescriptorByName(protoreflect.FullName(messageType))
+		if md, ok := d.(protoreflect.MessageDescriptor); err == nil && ok {
+			return dynamicpb.NewMessage(md), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownMessageType, messageType)
+}
+
+func (l *LocalClient) iRequestWithProtobufBody(ctx context.Context, service, messageType, bodyDoc string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m, err := l.protoMessage(messageType)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, body, err := replaceVars(ctx, l.VS, []byte(bodyDoc))
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := protojson.Unmarshal(body, m); err != nil {
+		return ctx, fmt.Errorf("%w: %s body: %s", ErrInvalidValue, messageType, err.Error())
+	}
+
+	if body, err = proto.Marshal(m); err != nil {
+		return ctx, err
+	}
+
+	c.WithBody(body)
+	c.WithContentType(ProtobufContentType)
+
+	return ctx, nil
+}
+
+// assertProtobuf decodes received body and compares it as JSON.
+func (l *LocalClient) assertProtobuf(ctx context.Context, messageType, bodyDoc string, received []byte) error {
+	m, err := l.protoMessage(messageType)
+	if err != nil {
+		return err
+	}
+
+	if err := proto.Unmarshal(received, m); err != nil {
+		return augmentBodyErr(ctx, fmt.Errorf("failed to decode %s: %w", messageType, err))
+	}
+
+	rcv, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return augmentBodyErr(l.assertJSON(ctx, []byte(bodyDoc), rcv, false))
+}
+
+func (l *LocalClient) iShouldHaveResponseWithProtobufBody(ctx context.Context, service, messageType, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.assertProtobuf(ctx, messageType, bodyDoc, received)
+	})
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithProtobufBody(ctx context.Context, service, messageType, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.assertProtobuf(ctx, messageType, bodyDoc, received)
+	})
+}
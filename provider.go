@@ -0,0 +1,66 @@
+package httpsteps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// BodyProvider supplies expected body fragment, e.g. from database state.
+//
+// Value is marshaled to JSON unless it is []byte or json.RawMessage.
+type BodyProvider func(ctx context.Context) (interface{}, error)
+
+// AddBodyProvider registers named provider of expected body fragments.
+//
+// Received body is expected to include provided JSON, as with `body including JSON` step.
+func (l *LocalClient) AddBodyProvider(name string, provider BodyProvider) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.providers == nil {
+		l.providers = make(map[string]BodyProvider)
+	}
+
+	l.providers[name] = provider
+}
+
+func (l *LocalClient) iShouldHaveResponseWithBodyMatchingProvider(ctx context.Context, service, name string) (context.Context, error) {
+	return l.expectBodyFromProvider(ctx, service, false, name)
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithBodyMatchingProvider(ctx context.Context, service, name string) (context.Context, error) {
+	return l.expectBodyFromProvider(ctx, service, true, name)
+}
+
+func (l *LocalClient) expectBodyFromProvider(ctx context.Context, service string, other bool, name string) (context.Context, error) {
+	l.mu.RLock()
+	provider, found := l.providers[name]
+	l.mu.RUnlock()
+
+	if !found {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+
+	return l.expectBody(ctx, service, other, func(ctx context.Context, received []byte) error {
+		v, err := provider(ctx)
+		if err != nil {
+			return fmt.Errorf("provider %s failed: %w", name, err)
+		}
+
+		var expected []byte
+
+		switch b := v.(type) {
+		case []byte:
+			expected = b
+		case json.RawMessage:
+			expected = b
+		default:
+			if expected, err = json.Marshal(v); err != nil {
+				return fmt.Errorf("failed to marshal value of provider %s: %w", name, err)
+			}
+		}
+
+		return l.includesJSON(string(expected))(ctx, received)
+	})
+}
@@ -0,0 +1,283 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bool64/httpmock"
+)
+
+// ramp sends waves of concurrent requests with increasing concurrency before the final wave.
+type ramp struct {
+	levels []int
+
+	once  sync.Once
+	waves []rampWave
+	err   error
+
+	mu    sync.Mutex
+	final *rampWave
+}
+
+// rampWave has counts of response statuses of a wave, zero status counts transport errors.
+type rampWave struct {
+	level    int
+	statuses map[int]int
+}
+
+// newRamp creates a ramp from start to end concurrency in a number of waves.
+func newRamp(start, end, steps int) (*ramp, error) {
+	if start < 1 || end < start || steps < 1 {
+		return nil, fmt.Errorf("%w: ramp from %d to %d requests in %d steps", ErrInvalidValue, start, end, steps)
+	}
+
+	r := &ramp{}
+
+	if steps == 1 {
+		r.levels = []int{end}
+
+		return r, nil
+	}
+
+	for i := 0; i < steps; i++ {
+		r.levels = append(r.levels, start+(end-start)*i/(steps-1))
+	}
+
+	return r, nil
+}
+
+// warmUp sends all waves but the final one with copies of request, it is called before sending the final wave.
+func (r *ramp) warmUp(next http.RoundTripper, req *http.Request) error {
+	r.once.Do(func() {
+		var body []byte
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if body, r.err = io.ReadAll(req.Body); r.err != nil {
+				return
+			}
+
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		for _, level := range r.levels[:len(r.levels)-1] {
+			r.waves = append(r.waves, sendWave(next, req, body, level))
+		}
+	})
+
+	return r.err
+}
+
+func sendWave(next http.RoundTripper, req *http.Request, body []byte, level int) rampWave {
+	w := rampWave{level: level, statuses: make(map[int]int)}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < level; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r := req.Clone(req.Context())
+			if body != nil {
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			status := 0
+
+			if resp, err := next.RoundTrip(r); err == nil {
+				status = resp.StatusCode
+				_, _ = io.Copy(io.Discard, resp.Body) //nolint:errcheck // Body is only drained to reuse connection.
+				_ = resp.Body.Close()                 //nolint:errcheck // Close failure does not affect status.
+			}
+
+			mu.Lock()
+			w.statuses[status]++
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return w
+}
+
+// String describes response statuses of a wave, e.g. "35: 200 x30, 429 x5".
+func (w rampWave) String() string {
+	codes := make([]int, 0, len(w.statuses))
+	for code := range w.statuses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	counts := make([]string, 0, len(codes))
+
+	for _, code := range codes {
+		name := strconv.Itoa(code)
+		if code == 0 {
+			name = "error"
+		}
+
+		counts = append(counts, fmt.Sprintf("%s x%d", name, w.statuses[code]))
+	}
+
+	return strconv.Itoa(w.level) + ": " + strings.Join(counts, ", ")
+}
+
+func (l *LocalClient) iRequestWithConcurrencyRamp(ctx context.Context, service string, start, end, steps int) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	r, err := newRamp(start, end, steps)
+	if err != nil {
+		return ctx, err
+	}
+
+	concurrently(c, end)
+
+	rt := recorderOf(c)
+	rt.setRamp(r)
+	rt.beforeRequest(func(req *http.Request) error {
+		return r.warmUp(rt.next, req)
+	})
+
+	return ctx, nil
+}
+
+// rampWaves sends ramp requests if necessary and returns statuses of all waves including the final one.
+func (l *LocalClient) rampWaves(ctx context.Context, service string) (context.Context, []rampWave, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	rt := recorderOf(c)
+
+	r := rt.getRamp()
+	if r == nil {
+		return ctx, nil, fmt.Errorf("%w: missing `I concurrently request idempotent HTTP endpoint with ramp` step",
+			ErrInvalidValue)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.final == nil {
+		// Final wave is sent by a no-op expectation, responses with different statuses fail idempotency check,
+		// but are still recorded.
+		ctx, err = l.checkResponse(ctx, service, func(c *httpmock.Client) error {
+			return c.ExpectResponseBodyCallback(func(received []byte) error { return nil })
+		})
+
+		last := rt.last()
+		if err != nil && len(last) == 0 {
+			return ctx, nil, err
+		}
+
+		if r.err != nil {
+			return ctx, nil, r.err
+		}
+
+		level := r.levels[len(r.levels)-1]
+		r.final = &rampWave{level: level, statuses: make(map[int]int)}
+
+		for _, e := range last {
+			r.final.statuses[e.resp.StatusCode]++
+		}
+
+		if len(last) < level {
+			r.final.statuses[0] = level - len(last)
+		}
+	}
+
+	return ctx, append(append([]rampWave(nil), r.waves...), *r.final), nil
+}
+
+// rampSummary describes statuses of all waves.
+func rampSummary(waves []rampWave) string {
+	s := make([]string, 0, len(waves))
+	for _, w := range waves {
+		s = append(s, w.String())
+	}
+
+	return strings.Join(s, "; ")
+}
+
+func (l *LocalClient) iShouldHaveRampResponsesWithStatusUpTo(ctx context.Context, service, statusOrCode string, upTo int) (context.Context, error) {
+	code, err := statusCode(statusOrCode)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, waves, err := l.rampWaves(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	for _, w := range waves {
+		if w.level > upTo {
+			break
+		}
+
+		if len(w.statuses) != 1 || w.statuses[code] != w.level {
+			return l.assertion(ctx, fmt.Errorf("%w at %d concurrent requests, expected: %d, received: %s",
+				ErrUnexpectedStatus, w.level, code, rampSummary(waves)))
+		}
+	}
+
+	return ctx, nil
+}
+
+func (l *LocalClient) iShouldHaveFirstFailedRampResponseWithStatus(ctx context.Context, service, statusOrCode string, atLeast int) (context.Context, error) {
+	code, err := statusCode(statusOrCode)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, waves, err := l.rampWaves(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	for _, w := range waves {
+		failed := 0
+
+		for status := range w.statuses {
+			if status == 0 || status >= http.StatusBadRequest {
+				failed++
+			}
+		}
+
+		if failed == 0 {
+			continue
+		}
+
+		if w.level < atLeast {
+			return l.assertion(ctx, fmt.Errorf("%w: first failure at %d concurrent requests, expected at %d or more: %s",
+				ErrUnexpectedStatus, w.level, atLeast, rampSummary(waves)))
+		}
+
+		if failed != 1 || w.statuses[code] == 0 {
+			return l.assertion(ctx, fmt.Errorf("%w of first failure at %d concurrent requests, expected: %d, received: %s",
+				ErrUnexpectedStatus, w.level, code, rampSummary(waves)))
+		}
+
+		return ctx, nil
+	}
+
+	return l.assertion(ctx, fmt.Errorf("%w: no failed responses, expected status %d at %d or more concurrent requests: %s",
+		ErrUnexpectedStatus, code, atLeast, rampSummary(waves)))
+}
@@ -0,0 +1,91 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// countReceived returns number of received requests that match target, e.g. "GET /things" or "/things".
+//
+// Request URI of target can be a full URI, a path (to match any query) or a pattern with wildcard segments.
+func (m *mock) countReceived(target string) int {
+	method, uri, found := strings.Cut(target, " ")
+	if !found {
+		method, uri = "", target
+	}
+
+	uri = strings.TrimSpace(uri)
+	cnt := 0
+
+	for _, r := range m.history() {
+		if method != "" && r.method != method {
+			continue
+		}
+
+		if r.matches(uri) {
+			cnt++
+
+			continue
+		}
+
+		if !isURIPattern(uri) {
+			continue
+		}
+
+		requestURI := r.requestURI
+		if !strings.Contains(uri, "?") {
+			requestURI, _, _ = strings.Cut(requestURI, "?")
+		}
+
+		if _, ok := matchURIPattern(uri, requestURI); ok {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+func (e *ExternalServer) serviceShouldHaveReceivedRequests(ctx context.Context, service string, n int, target string) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	if cnt := m.countReceived(target); cnt != n {
+		return ctx, fmt.Errorf("%w: %s received %d requests to %s, expected %d",
+			ErrUnexpectedRequests, service, cnt, target, n)
+	}
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivedRequestBodyFieldStoredAs(ctx context.Context, service, path, name string) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	// Value is taken from the last received request that has a body.
+	var body []byte
+
+	for _, r := range m.history() {
+		if len(r.body) > 0 {
+			body = r.body
+		}
+	}
+
+	if body == nil {
+		return ctx, fmt.Errorf("%w with body: %s", ErrMissingRequest, service)
+	}
+
+	val, err := jsonPathValue(body, path)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, v := e.VS.Vars(ctx)
+	v.Set(name, val)
+
+	return ctx, nil
+}
@@ -0,0 +1,165 @@
+package httpsteps
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secret values in curl commands and in attachments.
+const redacted = "REDACTED"
+
+// secretNames are parts of names of headers, query parameters and body fields with secret values.
+var secretNames = []string{"authorization", "cookie", "password", "passwd", "secret", "token", "apikey", "api-key", "api_key"}
+
+// isSecret checks if name of header, query parameter or body field suggests a secret value.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, s := range secretNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactValues redacts secret parameters of urlencoded query or form.
+func redactValues(encoded string) string {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return encoded
+	}
+
+	found := false
+
+	for k, v := range values {
+		if isSecret(k) {
+			found = true
+
+			for i := range v {
+				v[i] = redacted
+			}
+		}
+	}
+
+	if !found {
+		return encoded
+	}
+
+	return values.Encode()
+}
+
+// redactBody redacts secret fields of JSON or urlencoded form body.
+func redactBody(contentType string, body []byte) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && mt == "application/x-www-form-urlencoded" {
+		return redactValues(string(body))
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil || !redactJSON(v) {
+		return string(body)
+	}
+
+	redactedBody, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redactedBody)
+}
+
+// redactJSON replaces values of secret fields in decoded JSON, result is true if any field is redacted.
+func redactJSON(v interface{}) bool {
+	found := false
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if isSecret(k) {
+				v[k] = redacted
+				found = true
+
+				continue
+			}
+
+			if redactJSON(val) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if redactJSON(val) {
+				found = true
+			}
+		}
+	}
+
+	return found
+}
+
+// redactHeader returns a copy of header with secret values redacted.
+func redactHeader(h http.Header) http.Header {
+	res := h.Clone()
+
+	for name, values := range res {
+		if isSecret(name) {
+			for i := range values {
+				values[i] = redacted
+			}
+		}
+	}
+
+	return res
+}
+
+// prettyBody redacts secrets of JSON or urlencoded form body and indents JSON.
+func prettyBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	res := []byte(redactBody(contentType, body))
+
+	var buf bytes.Buffer
+	if json.Valid(res) && json.Indent(&buf, res, "", "  ") == nil {
+		return buf.Bytes()
+	}
+
+	return res
+}
+
+// dumpRequest returns request with redacted secrets and pretty-printed body.
+func dumpRequest(req *http.Request, body []byte) ([]byte, error) {
+	u := *req.URL
+	u.RawQuery = redactValues(u.RawQuery)
+
+	r := req.Clone(req.Context())
+	r.URL = &u
+	r.Header = redactHeader(req.Header)
+
+	body = prettyBody(req.Header.Get("Content-Type"), body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+
+	return httputil.DumpRequest(r, true)
+}
+
+// dumpResponse returns response with redacted secrets and pretty-printed body.
+func dumpResponse(resp *http.Response, body []byte) ([]byte, error) {
+	r := *resp
+	r.Header = redactHeader(resp.Header)
+	r.Header.Del("Content-Length")
+
+	body = prettyBody(resp.Header.Get("Content-Type"), body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+
+	return httputil.DumpResponse(&r, true)
+}
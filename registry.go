@@ -0,0 +1,51 @@
+package httpsteps
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cucumber/godog"
+)
+
+// registry tracks scenario contexts with registered steps to make registration idempotent.
+type registry struct {
+	mu       sync.Mutex
+	contexts map[*godog.ScenarioContext]struct{}
+}
+
+// add returns false if steps are already registered in scenario context.
+//
+// Scenario context is forgotten after scenario.
+func (r *registry) add(s *godog.ScenarioContext) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.contexts[s]; ok {
+		return false
+	}
+
+	if r.contexts == nil {
+		r.contexts = make(map[*godog.ScenarioContext]struct{})
+	}
+
+	r.contexts[s] = struct{}{}
+
+	s.After(func(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		delete(r.contexts, s)
+
+		return ctx, nil
+	})
+
+	return true
+}
+
+// reset forgets scenario contexts, e.g. of a suite that did not run its scenarios.
+func (r *registry) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.contexts = nil
+}
@@ -0,0 +1,237 @@
+package httpsteps
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// NewRoundTrip creates an instance of steps that combine application client and mocked external services.
+//
+// Both LocalClient and ExternalServer steps have to be registered too.
+func NewRoundTrip(local *LocalClient, external *ExternalServer) *RoundTrip {
+	return &RoundTrip{
+		local:    local,
+		external: external,
+	}
+}
+
+// RoundTrip is a collection of steps to describe application that proxies requests to external services.
+//
+// Please use NewRoundTrip() to create an instance.
+type RoundTrip struct {
+	local    *LocalClient
+	external *ExternalServer
+
+	// StepPrefix is prepended to expressions of all steps, same as LocalClient.StepPrefix.
+	StepPrefix string
+
+	// StepPatterns replaces default expressions of steps, e.g. with translations.
+	StepPatterns StepPatterns
+
+	registered registry
+}
+
+// RegisterSteps adds round trip steps to godog scenario context.
+//
+// Single step configures external service mock, sends request to application
+// and checks that application responded with same status and body as the external service.
+//
+//	When the app proxies "GET" "/template/hello" to "template-service" responding "OK" with body
+//	"""
+//	{"key":"value"}
+//	"""
+//
+// Body can be omitted.
+//
+//	When the app proxies "DELETE" "/template/hello" to "template-service" responding "No Content"
+//
+// Application can be a named service of LocalClient.
+//
+//	When the "gateway" app proxies "GET" "/template/hello" to "template-service" responding "OK"
+//
+// Caching of external service responses can be checked end to end. Request is sent to application twice,
+// external service responds with ETag to the first request and expects If-None-Match in the second one
+// to respond with "Not Modified". Application has to respond with same body to both requests.
+//
+//	When the app revalidates cached "GET" "/template/hello" from "template-service" with body
+//	"""
+//	{"key":"value"}
+//	"""
+func (r *RoundTrip) RegisterSteps(s *godog.ScenarioContext) {
+	if err := r.RegisterStepsWithPatterns(s, nil); err != nil {
+		failScenarios(s, err)
+	}
+}
+
+// RegisterStepsWithPatterns adds round trip steps to godog scenario context with custom expressions
+// that take precedence over StepPatterns.
+//
+// Steps are registered once per scenario context, so RegisterSteps has no effect after RegisterStepsWithPatterns.
+// Steps are not registered and error is returned if StepPatterns or custom expressions are invalid.
+func (r *RoundTrip) RegisterStepsWithPatterns(s *godog.ScenarioContext, custom StepPatterns) error {
+	if err := validatePatterns(r.StepPatterns, custom); err != nil {
+		return err
+	}
+
+	if !r.registered.add(s) {
+		return nil
+	}
+
+	s.StepContext().Before(beforeStep)
+	r.steps(withPatterns(s, custom))
+
+	return nil
+}
+
+// StepDefinitions returns expressions and handlers of steps in order of registration.
+func (r *RoundTrip) StepDefinitions() []StepDefinition {
+	var c stepCollector
+
+	r.steps(&c)
+
+	return c
+}
+
+func (r *RoundTrip) steps(s stepContext) {
+	r.step(s, `^the(.*) app proxies "([^"]*)" "([^"]*)" to "([^"]*)" responding "([^"]*)"$`,
+		func(ctx context.Context, app, method, uri, service, statusOrCode string) (context.Context, error) {
+			return r.appProxies(ctx, app, method, uri, service, statusOrCode, nil)
+		}, roundTripArgs)
+	r.step(s, `^the(.*) app proxies "([^"]*)" "([^"]*)" to "([^"]*)" responding "([^"]*)" with body$`,
+		func(ctx context.Context, app, method, uri, service, statusOrCode, bodyDoc string) (context.Context, error) {
+			return r.appProxies(ctx, app, method, uri, service, statusOrCode, &bodyDoc)
+		}, roundTripArgs)
+	r.step(s, `^the(.*) app revalidates cached "([^"]*)" "([^"]*)" from "([^"]*)" with body$`,
+		r.appRevalidatesCached, roundTripArgs[:4])
+}
+
+// roundTripArgs are kinds of captured values of round trip steps: application, method, URI,
+// external service and status.
+var roundTripArgs = []dryRunArg{dryRunService, dryRunText, dryRunText, dryRunExternalService, dryRunStatus}
+
+func (r *RoundTrip) appProxies(ctx context.Context, app, method, uri, service, statusOrCode string, bodyDoc *string) (context.Context, error) {
+	ctx, err := r.external.serviceReceivesRequest(ctx, service, method, uri)
+	if err != nil {
+		return ctx, err
+	}
+
+	if bodyDoc == nil {
+		ctx, err = r.external.serviceRespondsWithStatusAndPreparedBody(ctx, service, statusOrCode, nil)
+	} else {
+		ctx, err = r.external.serviceRespondsWithStatusAndBody(ctx, service, statusOrCode, *bodyDoc)
+	}
+
+	if err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.local.iRequestWithMethodAndURI(ctx, app, method, uri); err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.local.iShouldHaveResponseWithStatus(ctx, app, statusOrCode); err != nil {
+		return ctx, err
+	}
+
+	if bodyDoc == nil {
+		return ctx, nil
+	}
+
+	return r.local.iShouldHaveResponseWithBody(ctx, app, *bodyDoc)
+}
+
+func (r *RoundTrip) appRevalidatesCached(ctx context.Context, app, method, uri, service, bodyDoc string) (context.Context, error) {
+	ctx, body, err := replaceVars(ctx, r.external.VS, []byte(bodyDoc))
+	if err != nil {
+		return ctx, err
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	// First request is served with ETag.
+	if ctx, err = r.external.serviceReceivesRequest(ctx, service, method, uri); err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.external.serviceResponseIncludesHeader(ctx, service, "ETag", etag); err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.external.serviceRespondsWithStatusAndPreparedBody(ctx, service, "OK", body); err != nil {
+		return ctx, err
+	}
+
+	// Second request is expected to be conditional.
+	if ctx, err = r.external.serviceReceivesRequest(ctx, service, method, uri); err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.external.serviceRequestIncludesHeader(ctx, service, "If-None-Match", etag); err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.external.serviceRespondsWithStatusAndPreparedBody(ctx, service, "Not Modified", nil); err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.appResponds(ctx, app, method, uri, bodyDoc); err != nil {
+		return ctx, fmt.Errorf("first request: %w", err)
+	}
+
+	ctx, m, err := r.external.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	served := len(m.history())
+
+	ctx, err = r.appResponds(ctx, app, method, uri, bodyDoc)
+
+	// Missing revalidation explains failure of application response better.
+	if rerr := revalidated(m.history()[served:], method, uri, etag); rerr != nil {
+		return ctx, rerr
+	}
+
+	if err != nil {
+		return ctx, fmt.Errorf("second request: %w", err)
+	}
+
+	return ctx, nil
+}
+
+// appResponds sends request to application and checks successful response.
+func (r *RoundTrip) appResponds(ctx context.Context, app, method, uri, bodyDoc string) (context.Context, error) {
+	ctx, err := r.local.iRequestWithMethodAndURI(ctx, app, method, uri)
+	if err != nil {
+		return ctx, err
+	}
+
+	if ctx, err = r.local.iShouldHaveResponseWithStatus(ctx, app, "OK"); err != nil {
+		return ctx, err
+	}
+
+	return r.local.iShouldHaveResponseWithBody(ctx, app, bodyDoc)
+}
+
+// revalidated checks that external service received conditional request.
+func revalidated(received []receivedRequest, method, uri, etag string) error {
+	for _, req := range received {
+		if req.method != method || req.requestURI != uri {
+			continue
+		}
+
+		if inm := req.header.Get("If-None-Match"); !strings.Contains(inm, etag) {
+			return fmt.Errorf("%w, expected If-None-Match: %s, received: %q", ErrNotRevalidated, etag, inm)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w, %s %s was not requested from external service", ErrNotRevalidated, method, uri)
+}
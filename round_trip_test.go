@@ -0,0 +1,303 @@
+package httpsteps_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/godogx/httpsteps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip_RegisterSteps(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	templateService := external.Add("template-service")
+
+	u, err := url.Parse(templateService)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("gateway", srv.URL)
+
+	rt := httpsteps.NewRoundTrip(local, external)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			rt.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Format: "pretty",
+			Strict: true,
+			Paths:  []string{"_testdata/RoundTrip.feature"},
+		},
+	}
+
+	require.Equal(t, 0, suite.Run())
+}
+
+func TestRoundTrip_RegisterSteps_concurrentSuites(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	templateService := external.Add("template-service")
+
+	u, err := url.Parse(templateService)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("gateway", srv.URL)
+
+	rt := httpsteps.NewRoundTrip(local, external)
+
+	run := func() int {
+		suite := godog.TestSuite{
+			ScenarioInitializer: func(s *godog.ScenarioContext) {
+				local.RegisterSteps(s)
+				external.RegisterSteps(s)
+				rt.RegisterSteps(s)
+
+				// Repeated registration is ignored.
+				local.RegisterSteps(s)
+				external.RegisterSteps(s)
+				rt.RegisterSteps(s)
+			},
+			Options: &godog.Options{
+				Format: "pretty",
+				Output: io.Discard,
+				Strict: true,
+				Paths:  []string{"_testdata/RoundTrip.feature"},
+			},
+		}
+
+		return suite.Run()
+	}
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.Equal(t, 0, run())
+		}()
+	}
+
+	wg.Wait()
+
+	local.Reset()
+	external.Reset()
+
+	require.Equal(t, 0, run())
+}
+
+func TestRoundTrip_RegisterStepsWithPatterns(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	templateService := external.Add("template-service")
+
+	u, err := url.Parse(templateService)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(httputil.NewSingleHostReverseProxy(u))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	rt := httpsteps.NewRoundTrip(local, external)
+
+	var proxiesHandler interface{}
+
+	for _, d := range rt.StepDefinitions() {
+		if d.Expression == `^the(.*) app proxies "([^"]*)" "([^"]*)" to "([^"]*)" responding "([^"]*)"$` {
+			proxiesHandler = d.Handler
+		}
+	}
+
+	require.NotNil(t, proxiesHandler)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			assert.NoError(t, rt.RegisterStepsWithPatterns(s, httpsteps.StepPatterns{
+				`^the(.*) app proxies "([^"]*)" "([^"]*)" to "([^"]*)" responding "([^"]*)"$`: `^the(.*) app forwards "([^"]*)" "([^"]*)" to "([^"]*)" with "([^"]*)"$`,
+			}))
+
+			// Repeated registration is ignored.
+			rt.RegisterSteps(s)
+
+			// Alias keeps default wording of the step available.
+			s.Step(`^the(.*) app proxy of "([^"]*)" "([^"]*)" to "([^"]*)" responds "([^"]*)"$`, proxiesHandler)
+		},
+		Options: &godog.Options{
+			Format:   "pretty",
+			Output:   out,
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RoundTripPatterns.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	err = rt.RegisterStepsWithPatterns(nil, httpsteps.StepPatterns{
+		`^the(.*) app proxies "([^"]*)" "([^"]*)" to "([^"]*)" responding "([^"]*)"$`: `^the app forwards$`,
+	})
+	assert.ErrorIs(t, err, httpsteps.ErrInvalidStepPattern)
+}
+
+func TestRoundTrip_RegisterSteps_dryRun(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	external.Add("template-service")
+	external.DryRun = true
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.RequestURI)
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.AddService("gateway", srv.URL)
+	local.DryRun = true
+
+	rt := httpsteps.NewRoundTrip(local, external)
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+			external.RegisterSteps(s)
+			rt.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Format:   "pretty",
+			Output:   out,
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/RoundTrip.feature", "_testdata/RoundTripCache.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	out.Reset()
+
+	suite.Options.Paths = []string{"_testdata/RoundTripDryRunFail.feature"}
+
+	assert.Equal(t, 1, suite.Run())
+	assert.Contains(t, out.String(), `unknown http status: "Fine"`)
+	assert.Contains(t, out.String(), "unknown service: billing")
+	assert.Contains(t, out.String(), "3 scenarios (3 failed)")
+}
+
+func TestRoundTrip_RegisterSteps_revalidatesCached(t *testing.T) {
+	external := httpsteps.NewExternalServer()
+	templateService := external.Add("template-service")
+
+	u, err := url.Parse(templateService)
+	require.NoError(t, err)
+
+	run := func(app http.Handler) (int, string) {
+		srv := httptest.NewServer(app)
+		defer srv.Close()
+
+		local := httpsteps.NewLocalClient(srv.URL)
+		rt := httpsteps.NewRoundTrip(local, external)
+		out := bytes.NewBuffer(nil)
+
+		suite := godog.TestSuite{
+			ScenarioInitializer: func(s *godog.ScenarioContext) {
+				local.RegisterSteps(s)
+				external.RegisterSteps(s)
+				rt.RegisterSteps(s)
+			},
+			Options: &godog.Options{
+				Format:   "pretty",
+				Output:   out,
+				NoColors: true,
+				Strict:   true,
+				Paths:    []string{"_testdata/RoundTripCache.feature"},
+			},
+		}
+
+		return suite.Run(), out.String()
+	}
+
+	status, out := run(cachingProxy(t, templateService))
+	assert.Equal(t, 0, status, out)
+
+	status, out = run(httputil.NewSingleHostReverseProxy(u))
+	assert.Equal(t, 1, status)
+	assert.Contains(t, out, `cached response was not revalidated, expected If-None-Match: "`)
+}
+
+// cachingProxy serves requests with backend and revalidates cached responses with ETag.
+func cachingProxy(t *testing.T, backend string) http.Handler {
+	t.Helper()
+
+	type entry struct {
+		etag string
+		body []byte
+	}
+
+	var (
+		mu    sync.Mutex
+		cache = map[string]entry{}
+	)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), r.Method, backend+r.RequestURI, nil)
+		require.NoError(t, err)
+
+		mu.Lock()
+		cached, found := cache[r.RequestURI]
+		mu.Unlock()
+
+		if found {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, resp.Body.Close())
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		if found && resp.StatusCode == http.StatusNotModified {
+			_, err = rw.Write(cached.body)
+			assert.NoError(t, err)
+
+			return
+		}
+
+		if etag := resp.Header.Get("ETag"); etag != "" && resp.StatusCode == http.StatusOK {
+			mu.Lock()
+			cache[r.RequestURI] = entry{etag: etag, body: body}
+			mu.Unlock()
+		}
+
+		rw.WriteHeader(resp.StatusCode)
+
+		_, err = rw.Write(body)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,150 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin/go/v26"
+	"github.com/cucumber/godog"
+)
+
+// RunResult describes execution of a feature snippet.
+type RunResult struct {
+	// Scenarios are results of scenarios in order of execution.
+	Scenarios []ScenarioResult
+}
+
+// ScenarioResult describes execution of a scenario.
+type ScenarioResult struct {
+	Name  string
+	Steps []StepResult
+
+	// Err is a failure of scenario, nil if scenario passed.
+	Err error
+}
+
+// StepResult describes execution of a step.
+type StepResult struct {
+	Text string
+
+	// Status is one of "passed", "failed", "skipped", "undefined", "ambiguous" or "pending".
+	Status string
+
+	Err error
+}
+
+// Passed is true if all scenarios passed.
+func (r RunResult) Passed() bool {
+	for _, sc := range r.Scenarios {
+		if sc.Err != nil {
+			return false
+		}
+
+		for _, st := range sc.Steps {
+			if st.Status != godog.StepPassed.String() {
+				return false
+			}
+		}
+	}
+
+	return len(r.Scenarios) > 0
+}
+
+// RunFeatureString runs a snippet of Gherkin with steps of clients outside of a test suite,
+// for example to debug a request or to run a smoke check.
+//
+// Snippet may omit Feature and Scenario headers, then it is treated as a single scenario.
+//
+//	res, err := httpsteps.RunFeatureString(ctx, `
+//		When I request HTTP endpoint with method "GET" and URI "/health"
+//		Then I should have response with status "OK"
+//	`, local)
+func RunFeatureString(ctx context.Context, featureText string, clients ...StepRegistrar) (RunResult, error) {
+	var (
+		res RunResult
+		cur *ScenarioResult
+		id  int
+	)
+
+	featureText = completeFeature(featureText)
+
+	// Parser errors are reported by godog to stderr, so feature is checked in advance.
+	if _, err := gherkin.ParseGherkinDocument(strings.NewReader(featureText), func() string {
+		id++
+
+		return strconv.Itoa(id)
+	}); err != nil {
+		return res, fmt.Errorf("%w: %s", ErrInvalidFeature, err.Error())
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		Name: "httpsteps",
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			// Result of scenario is added before hooks of clients, so that it is available if they fail.
+			s.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+				res.Scenarios = append(res.Scenarios, ScenarioResult{Name: sc.Name})
+				cur = &res.Scenarios[len(res.Scenarios)-1]
+
+				return ctx, nil
+			})
+
+			for _, c := range clients {
+				c.RegisterSteps(s)
+			}
+
+			s.StepContext().After(func(ctx context.Context, st *godog.Step, status godog.StepResultStatus, err error) (context.Context, error) {
+				cur.Steps = append(cur.Steps, StepResult{Text: st.Text, Status: status.String(), Err: err})
+
+				return ctx, nil
+			})
+
+			s.After(func(ctx context.Context, _ *godog.Scenario, err error) (context.Context, error) {
+				cur.Err = err
+
+				return ctx, nil
+			})
+		},
+		Options: &godog.Options{
+			Output:          out,
+			Format:          "progress",
+			NoColors:        true,
+			Strict:          true,
+			DefaultContext:  ctx,
+			FeatureContents: []godog.Feature{{Name: "snippet.feature", Contents: []byte(featureText)}},
+		},
+	}
+
+	if status := suite.Run(); status > 1 {
+		return res, fmt.Errorf("%w: %s", ErrInvalidFeature, strings.TrimSpace(out.String()))
+	}
+
+	return res, nil
+}
+
+// completeFeature adds Feature and Scenario headers to a snippet if they are missing.
+func completeFeature(text string) string {
+	hasScenario := false
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "Feature:") {
+			return text
+		}
+
+		if strings.HasPrefix(line, "Scenario") || strings.HasPrefix(line, "Background:") {
+			hasScenario = true
+		}
+	}
+
+	if !hasScenario {
+		text = "Scenario: snippet\n" + text
+	}
+
+	return "Feature: snippet\n" + text
+}
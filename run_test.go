@@ -0,0 +1,69 @@
+package httpsteps_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/godogx/httpsteps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFeatureString(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+
+	res, err := httpsteps.RunFeatureString(context.Background(), `
+		When I request HTTP endpoint with method "GET" and URI "/health"
+		Then I should have response with status "OK"
+	`, local)
+	require.NoError(t, err)
+	assert.True(t, res.Passed())
+	require.Len(t, res.Scenarios, 1)
+	assert.Equal(t, "snippet", res.Scenarios[0].Name)
+	assert.Equal(t, []httpsteps.StepResult{
+		{Text: `I request HTTP endpoint with method "GET" and URI "/health"`, Status: "passed"},
+		{Text: `I should have response with status "OK"`, Status: "passed"},
+	}, res.Scenarios[0].Steps)
+
+	res, err = httpsteps.RunFeatureString(context.Background(), `
+Feature: Smoke
+
+  Scenario: Missing page
+    When I request HTTP endpoint with method "GET" and URI "/missing"
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {"status":"ok"}
+    """
+
+  Scenario: Unknown step
+    When I do something
+	`, local)
+	require.NoError(t, err)
+	assert.False(t, res.Passed())
+	require.Len(t, res.Scenarios, 2)
+
+	sc := res.Scenarios[0]
+	assert.Equal(t, "Missing page", sc.Name)
+	require.Error(t, sc.Err)
+	require.Len(t, sc.Steps, 3)
+	assert.Equal(t, "failed", sc.Steps[1].Status)
+	assert.Contains(t, sc.Steps[1].Err.Error(), "unexpected response status, expected: 200 (OK), received: 404 (Not Found)")
+	assert.Equal(t, "skipped", sc.Steps[2].Status)
+
+	assert.Equal(t, "undefined", res.Scenarios[1].Steps[0].Status)
+
+	_, err = httpsteps.RunFeatureString(context.Background(), "Feature: Broken\n  Scenario: Broken\n    Given x\n    \"\"\"\n", local)
+	assert.ErrorIs(t, err, httpsteps.ErrInvalidFeature)
+}
@@ -0,0 +1,55 @@
+package httpsteps
+
+import (
+	"context"
+)
+
+// next returns expectation of the same request without response, to serve the next response of a sequence.
+//
+// Signatures are not copied, they are already registered by previous expectation.
+func (e exp) next() *exp {
+	n := &exp{
+		async:     e.async,
+		include:   e.include,
+		jsonPaths: e.jsonPaths,
+		form:      e.form,
+		matchJSON: e.matchJSON,
+		uriRegex:  e.uriRegex,
+	}
+
+	n.Method = e.Method
+	n.RequestURI = e.RequestURI
+	n.RequestBody = e.RequestBody
+
+	if e.RequestHeader != nil {
+		n.RequestHeader = make(map[string]string, len(e.RequestHeader))
+		for k, v := range e.RequestHeader {
+			n.RequestHeader[k] = v
+		}
+	}
+
+	if e.RequestCookie != nil {
+		n.RequestCookie = make(map[string]string, len(e.RequestCookie))
+		for k, v := range e.RequestCookie {
+			n.RequestCookie[k] = v
+		}
+	}
+
+	return n
+}
+
+// responding returns mock with pending expectation to configure response, if there is no pending expectation
+// a new one is started for the request of the last expectation, so that repeated requests are served
+// with a sequence of responses.
+func (e *ExternalServer) responding(ctx context.Context, service string) (context.Context, *mock, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	if m.exp == nil && m.last != nil {
+		m.exp = m.last.next()
+	}
+
+	return e.pending(ctx, service)
+}
@@ -0,0 +1,159 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bool64/httpmock"
+)
+
+// ServiceConfig defines default behavior of requests to a named service.
+//
+// Defaults are applied to every new request and can be overridden by steps,
+// for example with "I retry HTTP request up to 5 times".
+type ServiceConfig struct {
+	// Retry is a default retry limit in the format of retry step, e.g. "5 times" or "10s".
+	// Empty value disables retries.
+	Retry string
+
+	// Timeout limits duration of a single request attempt, zero value means no timeout.
+	Timeout time.Duration
+
+	// FollowRedirects enables following of redirects.
+	FollowRedirects bool
+}
+
+// WithRetry sets default retry limit of a service, e.g. "5 times" or "10s".
+func WithRetry(limit string) func(cfg *ServiceConfig) {
+	return func(cfg *ServiceConfig) {
+		cfg.Retry = limit
+	}
+}
+
+// WithTimeout sets default timeout of a single request attempt to a service.
+func WithTimeout(timeout time.Duration) func(cfg *ServiceConfig) {
+	return func(cfg *ServiceConfig) {
+		cfg.Timeout = timeout
+	}
+}
+
+// WithFollowRedirects enables following of redirects by default.
+func WithFollowRedirects() func(cfg *ServiceConfig) {
+	return func(cfg *ServiceConfig) {
+		cfg.FollowRedirects = true
+	}
+}
+
+// apply configures new request of a client.
+func (cfg ServiceConfig) apply(ctx context.Context, l *LocalClient, c *httpmock.Client) (context.Context, error) {
+	if cfg.Timeout > 0 {
+		recorderOf(c).setTimeout(cfg.Timeout)
+	}
+
+	if cfg.FollowRedirects {
+		c.FollowRedirects()
+	}
+
+	if cfg.Retry != "" {
+		ctx, b, err := l.retryBackOff(ctx, cfg.Retry)
+		if err != nil {
+			return ctx, fmt.Errorf("default retry: %w", err)
+		}
+
+		c.AllowRetries(b)
+
+		return ctx, nil
+	}
+
+	return ctx, nil
+}
+
+// TransportTimeouts limits phases of connection to a service, zero value keeps limit of transport.
+type TransportTimeouts struct {
+	// Dial limits time to establish TCP connection.
+	Dial time.Duration
+
+	// TLSHandshake limits time of TLS handshake.
+	TLSHandshake time.Duration
+
+	// ResponseHeader limits time to wait for response headers after request is sent.
+	ResponseHeader time.Duration
+}
+
+// DialTimeout sets timeout of TCP connection to a service.
+func DialTimeout(timeout time.Duration) func(t *TransportTimeouts) {
+	return func(t *TransportTimeouts) {
+		t.Dial = timeout
+	}
+}
+
+// TLSHandshakeTimeout sets timeout of TLS handshake with a service.
+func TLSHandshakeTimeout(timeout time.Duration) func(t *TransportTimeouts) {
+	return func(t *TransportTimeouts) {
+		t.TLSHandshake = timeout
+	}
+}
+
+// ResponseHeaderTimeout sets timeout of waiting for response headers of a service.
+func ResponseHeaderTimeout(timeout time.Duration) func(t *TransportTimeouts) {
+	return func(t *TransportTimeouts) {
+		t.ResponseHeader = timeout
+	}
+}
+
+// WithTimeouts configures transport timeouts of a registered service.
+//
+// Transport of service client is replaced with a copy of http.DefaultTransport or of client's *http.Transport,
+// it fails for other implementations of http.RoundTripper.
+func (l *LocalClient) WithTimeouts(service string, options ...func(t *TransportTimeouts)) error {
+	service = serviceName(service)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, found := l.services[service]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	var timeouts TransportTimeouts
+
+	for _, o := range options {
+		o(&timeouts)
+	}
+
+	var tr *http.Transport
+
+	switch t := c.Transport.(type) {
+	case nil:
+		dt, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("%w: http.DefaultTransport is %T, *http.Transport expected", ErrInvalidValue, http.DefaultTransport)
+		}
+
+		tr = dt.Clone()
+	case *http.Transport:
+		tr = t.Clone()
+	default:
+		return fmt.Errorf("%w: transport of %s is %T, *http.Transport expected", ErrInvalidValue, service, c.Transport)
+	}
+
+	if timeouts.Dial > 0 {
+		tr.DialContext = (&net.Dialer{Timeout: timeouts.Dial, KeepAlive: 30 * time.Second}).DialContext
+	}
+
+	if timeouts.TLSHandshake > 0 {
+		tr.TLSHandshakeTimeout = timeouts.TLSHandshake
+	}
+
+	if timeouts.ResponseHeader > 0 {
+		tr.ResponseHeaderTimeout = timeouts.ResponseHeader
+	}
+
+	c.Transport = tr
+
+	return nil
+}
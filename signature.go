@@ -0,0 +1,139 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// signHMACSHA256 returns a function to set hex-encoded HMAC-SHA256 signature of request body in a header.
+func signHMACSHA256(key []byte, header string) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		var body []byte
+
+		if req.Body != nil {
+			b, err := io.ReadAll(req.Body)
+			if err != nil {
+				return err
+			}
+
+			if err := req.Body.Close(); err != nil {
+				return err
+			}
+
+			body = b
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		req.Header.Set(header, hmacSHA256(key, body))
+
+		return nil
+	}
+}
+
+// hmacSHA256 returns hex-encoded HMAC-SHA256 of body.
+func hmacSHA256(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body) //nolint:errcheck // Hash never returns error.
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SigningKey defines verification of signature of requests received by ExternalServer.
+//
+// Signature is a hex-encoded HMAC-SHA256 of request body.
+type SigningKey struct {
+	// Secret is a key of HMAC.
+	Secret []byte
+
+	// Header is a name of header with signature, "X-Signature" by default.
+	Header string
+
+	// Prefix is a prefix of signature in header value, e.g. "sha256=", optional.
+	Prefix string
+}
+
+// requestSignature is a signature expected in requests with method and URI.
+type requestSignature struct {
+	method     string
+	requestURI string
+	name       string
+	key        SigningKey
+}
+
+// verifiedSignature replaces valid signature in request header to match expectation.
+func verifiedSignature(name string) string {
+	return "<valid signature of " + name + ">"
+}
+
+// AddSigningKey registers named key to verify signatures of requests.
+func (e *ExternalServer) AddSigningKey(name string, key SigningKey) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if key.Header == "" {
+		key.Header = "X-Signature"
+	}
+
+	if e.signingKeys == nil {
+		e.signingKeys = make(map[string]SigningKey)
+	}
+
+	e.signingKeys[name] = key
+}
+
+func (e *ExternalServer) serviceRequestIsSignedWithKey(ctx context.Context, service, name string) (context.Context, error) {
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	e.mu.RLock()
+	key, found := e.signingKeys[name]
+	e.mu.RUnlock()
+
+	if !found {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownSigningKey, name)
+	}
+
+	if m.exp.RequestHeader == nil {
+		m.exp.RequestHeader = make(map[string]string)
+	}
+
+	m.exp.RequestHeader[key.Header] = verifiedSignature(name)
+	m.exp.signatures = append(m.exp.signatures, requestSignature{
+		method:     m.exp.Method,
+		requestURI: m.exp.RequestURI,
+		name:       name,
+		key:        key,
+	})
+
+	return ctx, nil
+}
+
+// verifySignatures replaces valid signatures with a mark of verification.
+func (m *mock) verifySignatures(req *http.Request, body []byte) {
+	for _, s := range m.signatures {
+		if (s.method != "" && s.method != req.Method) || s.requestURI != req.RequestURI {
+			continue
+		}
+
+		signature := req.Header.Get(s.key.Header)
+
+		if !strings.HasPrefix(signature, s.key.Prefix) {
+			continue
+		}
+
+		expected := hmacSHA256(s.key.Secret, body)
+
+		if hmac.Equal([]byte(strings.TrimPrefix(signature, s.key.Prefix)), []byte(expected)) {
+			req.Header.Set(s.key.Header, verifiedSignature(s.name))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cucumber/godog"
+)
+
+// UpdateSnapshotsEnv is an environment variable to record missing response snapshots and to overwrite
+// existing ones with received bodies, value is parsed with strconv.ParseBool, e.g.
+//
+//	HTTPSTEPS_UPDATE_SNAPSHOTS=1 go test ./...
+const UpdateSnapshotsEnv = "HTTPSTEPS_UPDATE_SNAPSHOTS"
+
+// snapshotsDirName is a name of default snapshots directory next to feature file.
+const snapshotsDirName = "__snapshots__"
+
+type featureDirCtxKey struct{}
+
+// featureDir puts directory of feature file in context.
+func featureDir(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+	return context.WithValue(ctx, featureDirCtxKey{}, filepath.Dir(sc.Uri)), nil
+}
+
+// snapshotPaths returns paths of JSON and text snapshot files.
+func (l *LocalClient) snapshotPaths(ctx context.Context, name string) (jsonPath, textPath string, err error) {
+	if name == "" || filepath.Base(name) != name {
+		return "", "", fmt.Errorf("%w: snapshot name %q, name without directories expected", ErrInvalidValue, name)
+	}
+
+	dir := l.SnapshotsDir
+	if dir == "" {
+		fd, _ := ctx.Value(featureDirCtxKey{}).(string) //nolint:errcheck // Empty value is current directory.
+		dir = filepath.Join(fd, snapshotsDirName)
+	}
+
+	base := filepath.Join(dir, name)
+
+	return base + ".json", base + ".txt", nil
+}
+
+// writeSnapshot stores received body as indented JSON or as is.
+func writeSnapshot(jsonPath, textPath string, received []byte) (string, error) {
+	filePath, body := textPath, received
+
+	if json.Valid(received) {
+		buf := bytes.NewBuffer(nil)
+		if err := json.Indent(buf, received, "", "  "); err != nil {
+			return "", err
+		}
+
+		buf.WriteByte('\n')
+
+		filePath, body = jsonPath, buf.Bytes()
+	}
+
+	// Snapshot of another format is removed to avoid ambiguity.
+	for _, p := range []string{jsonPath, textPath} {
+		if p != filePath {
+			if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, body, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// updateSnapshots is true if UpdateSnapshotsEnv has a true value.
+func updateSnapshots() (bool, error) {
+	v := os.Getenv(UpdateSnapshotsEnv)
+	if v == "" {
+		return false, nil
+	}
+
+	update, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s=%q", ErrInvalidValue, UpdateSnapshotsEnv, v)
+	}
+
+	return update, nil
+}
+
+// iShouldHaveResponseMatchingSnapshot compares body with a stored snapshot, snapshot is recorded
+// if it is missing or if UpdateSnapshotsEnv is enabled.
+func (l *LocalClient) iShouldHaveResponseMatchingSnapshot(ctx context.Context, service, name string) (context.Context, error) {
+	jsonPath, textPath, err := l.snapshotPaths(ctx, name)
+	if err != nil {
+		return ctx, err
+	}
+
+	update, err := updateSnapshots()
+	if err != nil {
+		return ctx, err
+	}
+
+	var recorded string
+
+	ctx, err = l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		if !update {
+			for _, p := range []string{jsonPath, textPath} {
+				if _, err := os.Stat(p); err == nil {
+					return l.assertFile(ctx, p, received, false, false)
+				}
+			}
+		}
+
+		// Concurrent responses are checked with the same snapshot, it is only written once.
+		if recorded != "" {
+			return l.assertFile(ctx, recorded, received, false, false)
+		}
+
+		p, err := writeSnapshot(jsonPath, textPath, received)
+		recorded = p
+
+		return err
+	})
+
+	if recorded != "" {
+		ctx = godog.Attach(ctx, godog.Attachment{
+			FileName:  "snapshot",
+			Body:      []byte("recorded " + recorded),
+			MediaType: "text/plain",
+		})
+	}
+
+	return ctx, err
+}
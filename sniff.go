@@ -0,0 +1,113 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bool64/httpmock"
+)
+
+// sniffLimit is a length of body prefix that is shown in content type mismatch.
+const sniffLimit = 100
+
+// checkContentType detects body that does not match declared content type, e.g. HTML error page served as JSON.
+//
+// Empty body matches any content type.
+func checkContentType(contentType string, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if contentType == "" {
+		return fmt.Errorf("%w: missing Content-Type, body looks like %s", ErrContentTypeMismatch, sniff(body))
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: invalid Content-Type %q: %s", ErrContentTypeMismatch, contentType, err.Error())
+	}
+
+	var reason string
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if !json.Valid(body) {
+			reason = "invalid JSON"
+		}
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		if looksLikeHTML(body) {
+			reason = "HTML document"
+		} else if err := validXML(body); err != nil {
+			reason = "invalid XML: " + err.Error()
+		}
+	case mediaType == "text/html":
+		if !looksLikeHTML(body) {
+			reason = "not an HTML document"
+		}
+	case strings.HasPrefix(mediaType, "text/"):
+		if !utf8.Valid(body) {
+			reason = "not a text"
+		}
+	case mediaType == "application/octet-stream":
+		// Any binary content is allowed.
+	default:
+		detected, _, _ := mime.ParseMediaType(http.DetectContentType(body)) //nolint:errcheck // Detected type is valid.
+		if detected != mediaType && detected != "application/octet-stream" && !strings.HasPrefix(detected, "text/") {
+			reason = "detected " + detected
+		}
+	}
+
+	if reason == "" {
+		return nil
+	}
+
+	return fmt.Errorf("%w %s: %s, body looks like %s", ErrContentTypeMismatch, mediaType, reason, sniff(body))
+}
+
+// looksLikeHTML is true for body that is detected as HTML document.
+func looksLikeHTML(body []byte) bool {
+	return strings.HasPrefix(http.DetectContentType(body), "text/html")
+}
+
+// validXML checks that body is a well-formed XML document.
+func validXML(body []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(body))
+
+	for {
+		_, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sniff describes detected content type and beginning of body.
+func sniff(body []byte) string {
+	prefix := body
+	if len(prefix) > sniffLimit {
+		prefix = prefix[:sniffLimit]
+	}
+
+	return fmt.Sprintf("%s: %q", http.DetectContentType(body), string(prefix))
+}
+
+func (l *LocalClient) iShouldHaveResponseWhoseBodyMatchesContentType(ctx context.Context, service string) (context.Context, error) {
+	ctx, err := l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		return checkContentType(d.Resp.Header.Get("Content-Type"), d.RespBody)
+	})
+
+	return l.assertion(ctx, err)
+}
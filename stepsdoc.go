@@ -0,0 +1,169 @@
+package httpsteps
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// StepsDoc returns Markdown documentation of step expressions with examples, e.g. for a cheat sheet of QA engineers.
+//
+// Steps of default LocalClient and ExternalServer are documented if definers are not provided.
+// Expressions are taken from StepDefinitions, so that documentation is always up to date.
+//
+//	_ = os.WriteFile("STEPS.md", []byte(httpsteps.StepsDoc(local, external)), 0o600)
+func StepsDoc(definers ...StepDefiner) string {
+	if len(definers) == 0 {
+		definers = []StepDefiner{NewLocalClient(""), NewExternalServer()}
+	}
+
+	doc := strings.Builder{}
+	doc.WriteString("# HTTP Steps\n")
+
+	for _, d := range definers {
+		_, external := d.(*ExternalServer)
+
+		title := fmt.Sprintf("%T", d)
+
+		switch d.(type) {
+		case *LocalClient:
+			title = "Local Client"
+		case *ExternalServer:
+			title = "External Server"
+		case *RoundTrip:
+			title = "Round Trip"
+		}
+
+		doc.WriteString("\n## " + title + "\n\n")
+
+		for _, def := range d.StepDefinitions() {
+			doc.WriteString("* `" + def.Expression + "`\n")
+
+			if example := stepExample(def.Expression, external); example != "" {
+				doc.WriteString("  ```gherkin\n  " + example + "\n  ```\n")
+			}
+		}
+	}
+
+	return doc.String()
+}
+
+// stepExample returns step text that matches expression, empty if expression is not supported.
+func stepExample(expr string, external bool) string {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return ""
+	}
+
+	e := example{args: dryRunArgs(expr, external)}
+	text := e.generate(re)
+
+	if !regexp.MustCompile(expr).MatchString(text) {
+		return ""
+	}
+
+	return text
+}
+
+// example generates text that matches regular expression.
+type example struct {
+	args []dryRunArg
+	text strings.Builder
+}
+
+func (e *example) generate(re *syntax.Regexp) string {
+	e.write(re)
+
+	return e.text.String()
+}
+
+func (e *example) write(re *syntax.Regexp) {
+	switch re.Op { //nolint:exhaustive // Other operations are empty.
+	case syntax.OpLiteral:
+		e.text.WriteString(string(re.Rune))
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			e.write(sub)
+		}
+	case syntax.OpAlternate, syntax.OpPlus:
+		e.write(re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < re.Min; i++ {
+			e.write(re.Sub[0])
+		}
+	case syntax.OpCharClass:
+		e.text.WriteRune(classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		e.text.WriteString("a")
+	case syntax.OpCapture:
+		e.capture(re)
+	}
+}
+
+// capture writes a value of captured argument.
+func (e *example) capture(re *syntax.Regexp) {
+	kind := dryRunText
+	if re.Cap-1 < len(e.args) {
+		kind = e.args[re.Cap-1]
+	}
+
+	before := e.text.String()
+	quoted := strings.Count(before, `"`)%2 == 1
+	sub := re.Sub[0]
+
+	switch kind {
+	case dryRunService:
+		if quoted {
+			e.text.WriteString("some-service")
+		}
+	case dryRunStatus:
+		e.text.WriteString("OK")
+	case dryRunDuration:
+		e.text.WriteString("1s")
+	case dryRunText:
+		switch {
+		case strings.HasSuffix(before, `method "`), strings.HasSuffix(before, `" receives "`):
+			e.text.WriteString("GET")
+		case strings.HasSuffix(before, `URI "`), strings.HasSuffix(before, `" request "`):
+			e.text.WriteString("/path")
+		case strings.HasSuffix(before, `URI `):
+			e.text.WriteString(`"/path"`)
+		case sub.Op == syntax.OpPlus && sub.Sub[0].Op == syntax.OpCharClass && inClass(sub.Sub[0].Rune, '$'):
+			// Variable name.
+			e.text.WriteString("$value")
+		case sub.Op != syntax.OpStar:
+			e.write(sub)
+		case quoted || sub.Sub[0].Op == syntax.OpCharClass && !inClass(sub.Sub[0].Rune, '"'):
+			e.text.WriteString("value")
+		default:
+			e.text.WriteString(`"value"`)
+		}
+	}
+}
+
+// inClass is true if rune is in ranges of character class.
+func inClass(ranges []rune, r rune) bool {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] <= r && r <= ranges[i+1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// classRune returns a readable rune of character class.
+func classRune(ranges []rune) rune {
+	for _, r := range []rune{'a', '1'} {
+		if inClass(ranges, r) {
+			return r
+		}
+	}
+
+	if len(ranges) == 0 {
+		return 'a'
+	}
+
+	return ranges[0]
+}
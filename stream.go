@@ -0,0 +1,233 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// BodyStream creates a reader of request body, it is called for every attempt of request.
+//
+// Body is sent with chunked transfer encoding without reading it into memory.
+type BodyStream func(ctx context.Context) (io.Reader, error)
+
+// AddBodyStream registers named stream of request body, e.g. a large generated upload.
+func (l *LocalClient) AddBodyStream(name string, stream BodyStream) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.streams == nil {
+		l.streams = make(map[string]BodyStream)
+	}
+
+	l.streams[name] = stream
+}
+
+// GeneratedBody returns a stream of repeated latin letters of a given size, data is not kept in memory.
+func GeneratedBody(size int64) BodyStream {
+	return func(_ context.Context) (io.Reader, error) {
+		return io.LimitReader(&letters{}, size), nil
+	}
+}
+
+// letters is an infinite reader of repeated latin letters.
+type letters struct {
+	pos int
+}
+
+func (l *letters) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte('a' + (l.pos+i)%26)
+	}
+
+	l.pos = (l.pos + len(p)) % 26
+
+	return len(p), nil
+}
+
+func (l *LocalClient) iRequestWithBodyStreamedFrom(ctx context.Context, service, name string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	l.mu.RLock()
+	stream, found := l.streams[name]
+	l.mu.RUnlock()
+
+	if !found {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownBodyStream, name)
+	}
+
+	c.WithBody(nil)
+	recorderOf(c).beforeRequest(streamBody(stream))
+
+	return ctx, nil
+}
+
+// streamBody returns a function to send request body from a stream.
+func streamBody(stream BodyStream) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		req.GetBody = func() (io.ReadCloser, error) {
+			r, err := stream(req.Context())
+			if err != nil {
+				return nil, err
+			}
+
+			if rc, ok := r.(io.ReadCloser); ok {
+				return rc, nil
+			}
+
+			return io.NopCloser(r), nil
+		}
+
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+
+		req.Body = body
+		req.ContentLength = -1
+
+		return nil
+	}
+}
+
+// progressInterval is a minimal interval between samples of request body progress.
+const progressInterval = 10 * time.Millisecond
+
+// BodyProgress is a number of request body bytes received by the time elapsed since request started.
+type BodyProgress struct {
+	Elapsed time.Duration
+	Bytes   int64
+}
+
+// progressReader samples number of bytes read over time.
+type progressReader struct {
+	r        io.Reader
+	start    time.Time
+	bytes    int64
+	progress []BodyProgress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.bytes += int64(n)
+
+	elapsed := time.Since(p.start)
+	last := len(p.progress) - 1
+
+	switch {
+	case n == 0:
+	case last < 0 || elapsed-p.progress[last].Elapsed >= progressInterval:
+		p.progress = append(p.progress, BodyProgress{Elapsed: elapsed, Bytes: p.bytes})
+	default:
+		// Samples are merged within interval, so that fast stream does not produce excessive samples.
+		p.progress[last].Bytes = p.bytes
+	}
+
+	return n, err
+}
+
+// RequestBodyProgress returns progress of request body of the latest request received by a service.
+func (e *ExternalServer) RequestBodyProgress(service string) ([]BodyProgress, error) {
+	e.mu.RLock()
+	m, found := e.mocks[service]
+	e.mu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownService, service)
+	}
+
+	return m.bodyProgress(service)
+}
+
+// bodyProgress returns progress of request body of the latest request.
+func (m *mock) bodyProgress(service string) ([]BodyProgress, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.received) == 0 {
+		return nil, fmt.Errorf("%w: no requests to %s", ErrMissingRequest, service)
+	}
+
+	return append([]BodyProgress(nil), m.received[len(m.received)-1].progress...), nil
+}
+
+func (e *ExternalServer) serviceReceivesRequestBodyAsStream(ctx context.Context, service string) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.streamBodies = true
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivesRequestBodyOfBytes(ctx context.Context, service string, n int64) (context.Context, error) {
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	progress, err := m.bodyProgress(service)
+	if err != nil {
+		return ctx, err
+	}
+
+	received := int64(0)
+	if len(progress) > 0 {
+		received = progress[len(progress)-1].Bytes
+	}
+
+	if received != n {
+		return ctx, fmt.Errorf("%w: %s received request body of %d bytes, expected %d",
+			ErrUnexpectedBody, service, received, n)
+	}
+
+	return ctx, nil
+}
+
+func (e *ExternalServer) serviceReceivesBytesOfRequestBodyWithin(ctx context.Context, service string, n int64, within string) (context.Context, error) {
+	d, err := time.ParseDuration(within)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %q", ErrInvalidValue, within)
+	}
+
+	ctx, m, err := e.mock(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	progress, err := m.bodyProgress(service)
+	if err != nil {
+		return ctx, err
+	}
+
+	for _, p := range progress {
+		if p.Bytes < n {
+			continue
+		}
+
+		if p.Elapsed > d {
+			return ctx, fmt.Errorf("%w: %s received %d bytes of request body in %s, expected within %s",
+				ErrDurationExceeded, service, n, p.Elapsed.Truncate(time.Millisecond), d)
+		}
+
+		return ctx, nil
+	}
+
+	received := int64(0)
+	if len(progress) > 0 {
+		received = progress[len(progress)-1].Bytes
+	}
+
+	return ctx, fmt.Errorf("%w: %s received request body of %d bytes, expected at least %d",
+		ErrUnexpectedBody, service, received, n)
+}
@@ -0,0 +1,88 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// serviceQualifier matches text captured before the rest of step with a quoted service name.
+var serviceQualifier = regexp.MustCompile(`^ "([^"]+)"$`)
+
+// strictServicesStep returns a handler that checks service qualifiers before running the step.
+func (l *LocalClient) strictServicesStep(expr string, handler interface{}) interface{} {
+	var services []int
+
+	for i, kind := range dryRunArgs(expr, false) {
+		if kind == dryRunService {
+			services = append(services, i)
+		}
+	}
+
+	if len(services) == 0 {
+		return handler
+	}
+
+	h := reflect.ValueOf(handler)
+
+	return reflect.MakeFunc(h.Type(), func(args []reflect.Value) []reflect.Value {
+		ctx, ok := args[0].Interface().(context.Context)
+		if !ok {
+			return h.Call(args)
+		}
+
+		for _, i := range services {
+			// Captured values follow context argument.
+			if err := l.checkServiceQualifier(ctx, args[i+1].String()); err != nil {
+				return stepResults(h.Type(), ctx, err)
+			}
+		}
+
+		return h.Call(args)
+	}).Interface()
+}
+
+// checkServiceQualifier fails if captured text is not empty and is not a quoted name of registered service.
+func (l *LocalClient) checkServiceQualifier(ctx context.Context, qualifier string) error {
+	if qualifier == "" {
+		return nil
+	}
+
+	m := serviceQualifier.FindStringSubmatch(qualifier)
+	if m == nil {
+		return fmt.Errorf("%w: %q, expected a quoted service name, registered services: %s",
+			ErrMalformedService, strings.TrimSpace(qualifier), l.registeredServices(ctx))
+	}
+
+	if !l.knownService(ctx, m[1]) {
+		return fmt.Errorf("%w: %s, registered services: %s", ErrUnknownService, m[1], l.registeredServices(ctx))
+	}
+
+	return nil
+}
+
+// registeredServices returns sorted list of quoted names of services, including services of current scenario.
+func (l *LocalClient) registeredServices(ctx context.Context) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	names := make([]string, 0, len(l.services))
+
+	for name := range l.services {
+		names = append(names, strconv.Quote(name))
+	}
+
+	for name := range scenarioServices(ctx, l) {
+		if _, found := l.services[name]; !found {
+			names = append(names, strconv.Quote(name))
+		}
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,66 @@
+package httpsteps
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/bool64/httpmock"
+)
+
+// Stub is a default response of service, it is served any number of times to requests
+// that do not match expectations of scenario.
+type Stub struct {
+	httpmock.Expectation
+
+	// URIRegex matches request URI instead of RequestURI of expectation, optional.
+	URIRegex *regexp.Regexp
+}
+
+// WithStubs is an option of Add to serve default responses to requests that do not match
+// expectations of scenario, stubs are matched in order, first matching stub is served.
+//
+// Requests served by stubs are not accounted, stubs take precedence over WithPassthrough.
+func (e *ExternalServer) WithStubs(stubs ...Stub) func(mock *httpmock.Server) {
+	return func(*httpmock.Server) {
+		e.configure(func(m *mock) {
+			m.stubs = append(m.stubs, stubs...)
+		})
+	}
+}
+
+// stub returns handler of the first stub that matches request.
+func (m *mock) stub(req *http.Request, body []byte) *requestHandler {
+	for _, s := range m.stubs {
+		e := s.Expectation
+
+		if s.URIRegex != nil {
+			if !s.URIRegex.MatchString(req.RequestURI) {
+				continue
+			}
+
+			e.RequestURI = ""
+		}
+
+		if m.matches(req, body, e) {
+			return stubHandler(s.Expectation)
+		}
+	}
+
+	return nil
+}
+
+// stubHandler creates a handler that writes response of stub.
+func stubHandler(e httpmock.Expectation) *requestHandler {
+	return &requestHandler{handler: func(rw http.ResponseWriter, _ *http.Request) {
+		for k, v := range e.ResponseHeader {
+			rw.Header().Set(k, v)
+		}
+
+		if e.Status == 0 {
+			e.Status = http.StatusOK
+		}
+
+		rw.WriteHeader(e.Status)
+		_, _ = rw.Write(e.ResponseBody) //nolint:errcheck // Client may be gone.
+	}}
+}
@@ -0,0 +1,138 @@
+package httpsteps
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/cucumber/godog"
+	"github.com/godogx/resource"
+	"github.com/godogx/vars"
+)
+
+// StepRegistrar adds step definitions to godog scenario context.
+//
+// It is implemented by LocalClient, ExternalServer and step managers of other godogx packages,
+// for example dbsteps and grpcsteps.
+type StepRegistrar interface {
+	RegisterSteps(s *godog.ScenarioContext)
+}
+
+// StepDefiner provides step expressions with handlers, it is implemented by LocalClient, ExternalServer and RoundTrip.
+type StepDefiner interface {
+	StepDefinitions() []StepDefinition
+}
+
+// Suite combines step packages in one godog suite with shared variables and resource locks.
+//
+// Vars steps and resource lock hooks are registered once per scenario, so that packages do not fork variables
+// or block each other with separate locks. ExternalServer receives Lock of the Suite when it is added, other packages
+// should be configured with VS and Lock of the Suite before they are added.
+//
+// Please use NewSuite() to create an instance.
+type Suite struct {
+	// VS is shared by all packages of the suite.
+	VS *vars.Steps
+
+	// Lock synchronizes concurrent scenarios that use a shared resource, e.g. a database table.
+	// Lock created by NewSuite checks expectations of services of added ExternalServer instances on release.
+	Lock *resource.Lock
+
+	packages   []StepRegistrar
+	servers    []*ExternalServer
+	registered registry
+}
+
+// NewSuite creates a Suite with new variables and resource lock.
+func NewSuite() *Suite {
+	s := &Suite{
+		VS: &vars.Steps{},
+	}
+	s.Lock = resource.NewLock(s.release)
+
+	return s
+}
+
+// release checks expectations of external service when its lock is released after scenario.
+func (s *Suite) release(name string) error {
+	for _, e := range s.servers {
+		if e.knownService(name) {
+			return e.release(name)
+		}
+	}
+
+	return nil
+}
+
+// Add adds step packages to the suite.
+//
+// LocalClient and ExternalServer receive VS of the suite if they have none, an error is returned if they
+// have a different instance of variables. ExternalServer acquires services with Lock of the suite.
+func (s *Suite) Add(packages ...StepRegistrar) error {
+	for _, p := range packages {
+		var pvs **vars.Steps
+
+		switch p := p.(type) {
+		case *LocalClient:
+			pvs = &p.VS
+		case *ExternalServer:
+			pvs = &p.VS
+			p.lock = s.Lock
+			p.sharedLock = true
+			s.servers = append(s.servers, p)
+		}
+
+		if pvs != nil {
+			if *pvs == nil {
+				*pvs = s.VS
+			}
+
+			if *pvs != s.VS {
+				return fmt.Errorf("%w: %T has own variables", ErrForkedVars, p)
+			}
+		}
+
+		s.packages = append(s.packages, p)
+	}
+
+	return nil
+}
+
+// RegisterSteps adds steps of vars, resource lock and all packages to godog scenario context.
+func (s *Suite) RegisterSteps(sc *godog.ScenarioContext) {
+	if !s.registered.add(sc) {
+		return
+	}
+
+	s.VS.Register(sc)
+	s.Lock.Register(sc)
+
+	for _, p := range s.packages {
+		p.RegisterSteps(sc)
+	}
+}
+
+// Matching returns expressions of HTTP steps of the suite packages that match step text.
+//
+// It helps to check that steps of other packages do not overlap with HTTP steps, godog fails ambiguous steps
+// in strict mode.
+func (s *Suite) Matching(text string) []string {
+	var res []string
+
+	for _, p := range s.packages {
+		d, ok := p.(StepDefiner)
+		if !ok {
+			continue
+		}
+
+		for _, def := range d.StepDefinitions() {
+			if regexp.MustCompile(def.Expression).MatchString(text) {
+				res = append(res, def.Expression)
+			}
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
@@ -0,0 +1,173 @@
+package httpsteps_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/godogx/httpsteps"
+	"github.com/godogx/resource"
+	"github.com/godogx/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dbSteps imitates steps of a database package that uses variables and resource locks of a suite.
+type dbSteps struct {
+	vs   *vars.Steps
+	lock *resource.Lock
+}
+
+func (d dbSteps) RegisterSteps(s *godog.ScenarioContext) {
+	for _, def := range d.StepDefinitions() {
+		s.Step(def.Expression, def.Handler)
+	}
+}
+
+func (d dbSteps) StepDefinitions() []httpsteps.StepDefinition {
+	return []httpsteps.StepDefinition{{
+		Expression: `^there is a row with id "([^"]*)" in table "([^"]*)"$`,
+		Handler: func(ctx context.Context, id, table string) (context.Context, error) {
+			if _, err := d.lock.Acquire(ctx, "table:"+table); err != nil {
+				return ctx, err
+			}
+
+			var v interface{}
+			if err := json.Unmarshal([]byte(id), &v); err != nil {
+				return ctx, err
+			}
+
+			ctx, vv := d.vs.Vars(ctx)
+			vv.Set("$id", v)
+
+			return ctx, nil
+		},
+	}}
+}
+
+func TestSuite(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	cacheURL := es.Add("users-cache")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := http.Get(cacheURL + r.URL.Path) //nolint:noctx
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.NoError(t, resp.Body.Close())
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"path": r.URL.Path}))
+	}))
+	defer srv.Close()
+
+	s := httpsteps.NewSuite()
+
+	local := httpsteps.NewLocalClient(srv.URL)
+
+	require.NoError(t, s.Add(local, es, dbSteps{vs: s.VS, lock: s.Lock}))
+	assert.Same(t, s.VS, local.VS)
+	assert.Same(t, s.VS, es.VS)
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: s.RegisterSteps,
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Suite.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run(), out.String())
+
+	// Steps of database and gRPC packages do not overlap with HTTP steps.
+	for _, text := range []string{
+		`there are no rows in table "users" of database "default"`,
+		`these rows are stored in table "users" of database "default"`,
+		`I request a gRPC method "/app.Service/Method" with payload`,
+		`I should have a gRPC response with payload`,
+		`"users-service" receives a gRPC request "/app.Service/Method" with payload`,
+	} {
+		assert.Empty(t, s.Matching(text), text)
+	}
+
+	assert.Equal(t, []string{`^I request(.*) HTTP endpoint with body$`}, s.Matching("I request HTTP endpoint with body"))
+}
+
+func TestSuite_Add_sharedLock(t *testing.T) {
+	es := httpsteps.NewExternalServer()
+	es.Add("users-cache")
+
+	s := httpsteps.NewSuite()
+	require.NoError(t, s.Add(es))
+
+	out := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: s.RegisterSteps,
+		Options: &godog.Options{
+			Output:   out,
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/SuiteUnmet.feature"},
+		},
+	}
+
+	// Expectations are checked when service lock of the suite is released.
+	assert.Equal(t, 1, suite.Run(), out.String())
+	assert.Contains(t, out.String(), "expectations were not met for users-cache")
+
+	// Steps of other instances are not reported.
+	assert.Empty(t, s.Matching("I request HTTP endpoint with body"))
+	assert.Equal(t, []string{`^"([^"]*)" request is async$`}, s.Matching(`"users-cache" request is async`))
+}
+
+func TestSuite_Add_forkedVars(t *testing.T) {
+	local := httpsteps.NewLocalClient("")
+	local.VS = &vars.Steps{}
+
+	err := httpsteps.NewSuite().Add(local)
+	assert.ErrorIs(t, err, httpsteps.ErrForkedVars)
+}
+
+func TestStepsDoc(t *testing.T) {
+	doc := httpsteps.StepsDoc()
+
+	assert.Contains(t, doc, "## Local Client\n\n* `^I request(.*) HTTP endpoint with method \"([^\"]*)\" and URI (.*)$`\n"+
+		"  ```gherkin\n  I request HTTP endpoint with method \"GET\" and URI \"/path\"\n  ```\n")
+	assert.Contains(t, doc, "## External Server\n")
+	assert.Contains(t, doc, "\"some-service\" responds with status \"OK\" and body\n")
+
+	// Every example matches its step.
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		if line != "  ```gherkin" {
+			continue
+		}
+
+		expr := strings.Trim(strings.TrimPrefix(lines[i-1], "* "), "`")
+		example := strings.TrimSpace(lines[i+1])
+
+		assert.Regexp(t, expr, example)
+	}
+
+	local := httpsteps.NewLocalClient("")
+	local.StepPrefix = "api # "
+
+	doc = httpsteps.StepsDoc(local, httpsteps.NewRoundTrip(local, httpsteps.NewExternalServer()))
+	assert.Contains(t, doc, "* `^api # I request(.*) HTTP endpoint with method \"([^\"]*)\" and URI (.*)$`\n")
+	assert.Contains(t, doc, "## Round Trip\n\n* `^the(.*) app proxies")
+
+	doc = httpsteps.StepsDoc(dbSteps{})
+	assert.Contains(t, doc, "## httpsteps_test.dbSteps\n\n* `^there is a row with id \"([^\"]*)\" in table \"([^\"]*)\"$`\n"+
+		"  ```gherkin\n  there is a row with id \"value\" in table \"value\"\n  ```\n")
+}
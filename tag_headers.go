@@ -0,0 +1,61 @@
+package httpsteps
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// tagHeadersCtxKey keeps headers defined by scenario tags for an owner of tag mapping.
+type tagHeadersCtxKey struct {
+	owner interface{}
+}
+
+// withTagHeaders returns a before scenario hook to put headers defined by scenario tags in context.
+//
+// Mapping defines header name for a tag name, e.g. {"variant": "X-Experiment"} turns @variant(B) into
+// X-Experiment: B. Scenario tags take precedence over inherited feature tags.
+func withTagHeaders(owner interface{}, mapping func() map[string]string) func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+	return func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		m := mapping()
+		if len(m) == 0 {
+			return ctx, nil
+		}
+
+		var headers map[string]string
+
+		for _, tag := range sc.Tags {
+			name := strings.TrimPrefix(tag.Name, "@")
+
+			pos := strings.Index(name, "(")
+			if pos <= 0 || !strings.HasSuffix(name, ")") {
+				continue
+			}
+
+			header, ok := m[name[:pos]]
+			if !ok {
+				continue
+			}
+
+			if headers == nil {
+				headers = make(map[string]string)
+			}
+
+			headers[header] = name[pos+1 : len(name)-1]
+		}
+
+		if headers == nil {
+			return ctx, nil
+		}
+
+		return context.WithValue(ctx, tagHeadersCtxKey{owner: owner}, headers), nil
+	}
+}
+
+// tagHeaders returns headers defined by scenario tags.
+func tagHeaders(ctx context.Context, owner interface{}) map[string]string {
+	headers, _ := ctx.Value(tagHeadersCtxKey{owner: owner}).(map[string]string)
+
+	return headers
+}
@@ -0,0 +1,67 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cucumber/godog"
+)
+
+type tempDirCtxKey struct{}
+
+// iHaveTemporaryFile creates a file in a temporary directory of scenario and stores its path in a variable
+// named after the file, e.g. $data.csv.
+func (l *LocalClient) iHaveTemporaryFile(ctx context.Context, name, content string) (context.Context, error) {
+	if name == "" || filepath.Base(name) != name {
+		return ctx, fmt.Errorf("%w: temporary file name %q, name without directories expected", ErrInvalidValue, name)
+	}
+
+	dir, ok := ctx.Value(tempDirCtxKey{}).(string)
+	if !ok {
+		d, err := os.MkdirTemp("", "httpsteps-")
+		if err != nil {
+			return ctx, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+
+		dir = d
+		ctx = context.WithValue(ctx, tempDirCtxKey{}, dir)
+	}
+
+	ctx, body, err := replaceVars(ctx, l.VS, []byte(content))
+	if err != nil {
+		return ctx, err
+	}
+
+	filePath := filepath.Join(dir, name)
+
+	if err := os.WriteFile(filePath, body, 0o600); err != nil {
+		return ctx, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	ctx, v := l.VS.Vars(ctx)
+
+	prefix := v.VarPrefix
+	if prefix == "" {
+		prefix = "$"
+	}
+
+	v.Set(prefix+name, filePath)
+
+	return ctx, nil
+}
+
+// removeTemporaryFiles removes temporary directory of scenario.
+func removeTemporaryFiles(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
+	dir, ok := ctx.Value(tempDirCtxKey{}).(string)
+	if !ok {
+		return ctx, nil
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return ctx, fmt.Errorf("failed to remove temporary files: %w", err)
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,88 @@
+package httpsteps
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/bool64/shared"
+)
+
+// requestTemplate finds references to incoming request in response body, e.g. "{{request.query.id}}".
+var requestTemplate = regexp.MustCompile(`{{request\.([^{}]+)}}`)
+
+// requestValue returns value of a reference to incoming request.
+//
+// Supported references are method, uri, path, query.<name>, header.<name>, params.<name> (values of
+// wildcard segments or submatches of request URI) and body.<JSON path>.
+func requestValue(ref string, req *http.Request, body []byte, params map[string]string) (interface{}, bool) {
+	kind, name, _ := strings.Cut(ref, ".")
+
+	switch kind {
+	case "method":
+		return req.Method, true
+	case "uri":
+		return req.URL.RequestURI(), true
+	case "path":
+		return req.URL.Path, true
+	case "query":
+		if v, ok := req.URL.Query()[name]; ok && len(v) > 0 {
+			return v[0], true
+		}
+	case "header":
+		if v := req.Header.Values(name); len(v) > 0 {
+			return v[0], true
+		}
+	case "params":
+		v, ok := params[name]
+
+		return v, ok
+	case "body":
+		if v, err := jsonPathValue(body, name); err == nil {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// requestVars returns variables to prepare response body for the incoming request.
+//
+// Initial variables are extended with URI parameters (e.g. "$id") and values of request references
+// that are used in response bodies of candidate expectations (e.g. "{{request.query.id}}").
+func (m *mock) requestVars(req *http.Request, body []byte, params map[string]string) *shared.Vars {
+	refs := map[string]interface{}{}
+
+	for _, e := range m.candidates() {
+		for _, match := range requestTemplate.FindAllSubmatch(e.ResponseBody, -1) {
+			if v, ok := requestValue(string(match[1]), req, body, params); ok {
+				refs[string(match[0])] = v
+			}
+		}
+	}
+
+	if len(params) == 0 && len(refs) == 0 {
+		return m.vars
+	}
+
+	v := &shared.Vars{}
+
+	if m.vars != nil {
+		v.VarPrefix = m.vars.VarPrefix
+
+		for k, val := range m.vars.GetAll() {
+			v.Set(k, val)
+		}
+	}
+
+	for k, val := range params {
+		v.Set("$"+k, val)
+	}
+
+	// Whole JSON string values that are references are replaced with values of request.
+	for k, val := range refs {
+		v.Set(k, val)
+	}
+
+	return v
+}
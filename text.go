@@ -0,0 +1,82 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+)
+
+// normalizeText converts line endings to LF and removes trailing whitespace of lines and body.
+func normalizeText(body []byte) []byte {
+	body = bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n"))
+	body = bytes.ReplaceAll(body, []byte("\r"), []byte("\n"))
+
+	lines := bytes.Split(body, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimRight(line, " \t")
+	}
+
+	return bytes.TrimRight(bytes.Join(lines, []byte("\n")), "\n")
+}
+
+// normalizesText is true if received body should be compared as normalized text.
+//
+// JSON bodies are never normalized, they are compared structurally anyway.
+func (l *LocalClient) normalizesText(normalize bool, received []byte) bool {
+	return (normalize || l.NormalizeTextBodies) && !json.Valid(received)
+}
+
+// assertText compares body with expected value, text bodies are normalized if enabled by step or client.
+func (l *LocalClient) assertText(ctx context.Context, expected, received []byte, normalize bool) error {
+	if l.normalizesText(normalize, received) {
+		expected, received = normalizeText(expected), normalizeText(received)
+	}
+
+	return augmentBodyErr(l.assertJSON(ctx, expected, received, false))
+}
+
+// assertFile compares body with file contents, text bodies are normalized if enabled by step or client.
+func (l *LocalClient) assertFile(ctx context.Context, filePath string, received []byte, ignoreAddedJSONFields, normalize bool) error {
+	if !l.normalizesText(normalize, received) {
+		return augmentBodyErr(l.assertJSONFile(ctx, filePath, received, ignoreAddedJSONFields))
+	}
+
+	expected, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return err
+	}
+
+	return l.assertText(ctx, expected, received, true)
+}
+
+func (l *LocalClient) iShouldHaveResponseWithNormalizedBody(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, false, func(ctx context.Context, received []byte) error {
+		return l.assertText(ctx, []byte(bodyDoc), received, true)
+	})
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithNormalizedBody(ctx context.Context, service, bodyDoc string) (context.Context, error) {
+	return l.expectBody(ctx, service, true, func(ctx context.Context, received []byte) error {
+		return l.assertText(ctx, []byte(bodyDoc), received, true)
+	})
+}
+
+func (l *LocalClient) iShouldHaveResponseWithNormalizedBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+	return l.expectNormalizedBodyFromFile(ctx, service, false, filePath)
+}
+
+func (l *LocalClient) iShouldHaveOtherResponsesWithNormalizedBodyFromFile(ctx context.Context, service, filePath string) (context.Context, error) {
+	return l.expectNormalizedBodyFromFile(ctx, service, true, filePath)
+}
+
+func (l *LocalClient) expectNormalizedBodyFromFile(ctx context.Context, service string, other bool, filePath string) (context.Context, error) {
+	ctx, filePath, err := resolveFilePath(ctx, l.VS, filePath)
+	if err != nil {
+		return ctx, err
+	}
+
+	return l.expectBody(ctx, service, other, func(ctx context.Context, received []byte) error {
+		return l.assertFile(ctx, filePath, received, false, true)
+	})
+}
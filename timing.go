@@ -0,0 +1,159 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bool64/httpmock"
+)
+
+// serverTiming is a metric of Server-Timing header.
+type serverTiming struct {
+	name string
+	dur  *time.Duration
+	desc string
+}
+
+// parseServerTiming parses metrics of all Server-Timing headers, e.g. `db;dur=53.2;desc="Database", cache;dur=1`.
+func parseServerTiming(h http.Header) []serverTiming {
+	var res []serverTiming
+
+	for _, v := range h.Values("Server-Timing") {
+		for _, metric := range splitQuoted(v, ',') {
+			params := splitQuoted(metric, ';')
+
+			st := serverTiming{name: strings.TrimSpace(params[0])}
+			if st.name == "" {
+				continue
+			}
+
+			for _, p := range params[1:] {
+				k, val, _ := strings.Cut(p, "=")
+				val = strings.Trim(strings.TrimSpace(val), `"`)
+
+				switch strings.ToLower(strings.TrimSpace(k)) {
+				case "dur":
+					if ms, err := strconv.ParseFloat(val, 64); err == nil {
+						d := time.Duration(ms * float64(time.Millisecond))
+						st.dur = &d
+					}
+				case "desc":
+					st.desc = val
+				}
+			}
+
+			res = append(res, st)
+		}
+	}
+
+	return res
+}
+
+// splitQuoted splits string by separator that is not enclosed in double quotes.
+func splitQuoted(s string, sep rune) []string {
+	var (
+		res    []string
+		quoted bool
+		start  int
+	)
+
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			res = append(res, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(res, s[start:])
+}
+
+// parseDiagnosticDuration parses header value as duration, plain number is a number of milliseconds.
+func parseDiagnosticDuration(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+
+	if ms, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+
+	return time.ParseDuration(v)
+}
+
+func (l *LocalClient) iShouldHaveServerTiming(ctx context.Context, service, name string) (context.Context, error) {
+	return l.expectServerTiming(ctx, service, name, func(_ serverTiming) error {
+		return nil
+	})
+}
+
+func (l *LocalClient) iShouldHaveServerTimingUnder(ctx context.Context, service, name, limit string) (context.Context, error) {
+	lim, err := time.ParseDuration(limit)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %s", ErrInvalidValue, err.Error())
+	}
+
+	return l.expectServerTiming(ctx, service, name, func(st serverTiming) error {
+		if st.dur == nil {
+			return fmt.Errorf("%w: server timing %s has no duration", ErrMissingServerTiming, name)
+		}
+
+		if *st.dur >= lim {
+			return fmt.Errorf("%w: server timing %s is %s, expected under %s", ErrDurationExceeded, name, *st.dur, lim)
+		}
+
+		return nil
+	})
+}
+
+func (l *LocalClient) expectServerTiming(ctx context.Context, service, name string, check func(st serverTiming) error) (context.Context, error) {
+	ctx, err := l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		timings := parseServerTiming(d.Resp.Header)
+
+		for _, st := range timings {
+			if st.name == name {
+				return check(st)
+			}
+		}
+
+		received := make([]string, 0, len(timings))
+		for _, st := range timings {
+			received = append(received, st.name)
+		}
+
+		return fmt.Errorf("%w: %s, received: [%s]", ErrMissingServerTiming, name, strings.Join(received, ", "))
+	})
+
+	return l.assertion(ctx, err)
+}
+
+func (l *LocalClient) iShouldHaveResponseWithHeaderUnder(ctx context.Context, service, key, limit string) (context.Context, error) {
+	lim, err := time.ParseDuration(limit)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %s", ErrInvalidValue, err.Error())
+	}
+
+	ctx, err = l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		v := d.Resp.Header.Get(key)
+		if v == "" {
+			return fmt.Errorf("%w: %s", ErrMissingHeader, key)
+		}
+
+		dur, err := parseDiagnosticDuration(v)
+		if err != nil {
+			return fmt.Errorf("%w in header %s: %s", ErrInvalidValue, key, err.Error())
+		}
+
+		if dur >= lim {
+			return fmt.Errorf("%w: header %s is %s, expected under %s", ErrDurationExceeded, key, dur, lim)
+		}
+
+		return nil
+	})
+
+	return l.assertion(ctx, err)
+}
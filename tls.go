@@ -0,0 +1,149 @@
+package httpsteps
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"time"
+
+	"github.com/bool64/httpmock"
+)
+
+// WithTLS is an option of Add to serve HTTPS instead of plain HTTP.
+//
+// Server certificate is valid for localhost and is signed by a CA generated for ExternalServer,
+// use CACertificate to make application under test trust it.
+func (e *ExternalServer) WithTLS() func(mock *httpmock.Server) {
+	return func(*httpmock.Server) {
+		e.configure(func(m *mock) { m.tls = true })
+	}
+}
+
+// authority is a generated certificate authority with a server certificate for localhost.
+type authority struct {
+	certPEM []byte
+	pool    *x509.CertPool
+	server  tls.Certificate
+}
+
+// CACertificate returns PEM-encoded certificate of CA that signs certificates of services added WithTLS,
+// nil if CA can not be generated.
+func (e *ExternalServer) CACertificate() []byte {
+	ca, err := e.authority()
+	if err != nil {
+		return nil
+	}
+
+	return ca.certPEM
+}
+
+// CertPool returns a pool with CA certificate of services added WithTLS, it can be used as tls.Config RootCAs,
+// nil if CA can not be generated.
+func (e *ExternalServer) CertPool() *x509.CertPool {
+	ca, err := e.authority()
+	if err != nil {
+		return nil
+	}
+
+	return ca.pool
+}
+
+func (e *ExternalServer) authority() (*authority, error) {
+	e.caOnce.Do(func() {
+		e.ca, e.caErr = newAuthority()
+		if e.caErr != nil {
+			e.caErr = fmt.Errorf("failed to generate CA: %w", e.caErr)
+		}
+	})
+
+	return e.ca, e.caErr
+}
+
+// serveTLS starts HTTPS server for the mock.
+//
+// Failure to generate CA is reported by steps of the service, server has a certificate of httptest then.
+func (e *ExternalServer) serveTLS(m *mock) *httptest.Server {
+	srv := httptest.NewUnstartedServer(m)
+	srv.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if ca, err := e.authority(); err != nil {
+		m.err = err
+	} else {
+		srv.TLS.Certificates = []tls.Certificate{ca.server}
+	}
+
+	srv.StartTLS()
+
+	return srv
+}
+
+func newAuthority() (*authority, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(10 * 365 * 24 * time.Hour)
+
+	caTemplate := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"httpsteps"}, CommonName: "httpsteps CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{Organization: []string{"httpsteps"}, CommonName: "localhost"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	return &authority{
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pool:    pool,
+		server: tls.Certificate{
+			Certificate: [][]byte{der, caDER},
+			PrivateKey:  key,
+		},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bool64/httpmock"
+)
+
+// withTrailer returns a function to set request trailer, request body is sent with chunked transfer encoding.
+func withTrailer(key, value string) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		if req.Trailer == nil {
+			req.Trailer = make(http.Header)
+		}
+
+		req.Trailer.Set(key, value)
+
+		if req.Body == nil || req.Body == http.NoBody {
+			req.Body = io.NopCloser(strings.NewReader(""))
+		}
+
+		// Trailers are only sent with chunked body, empty body of GET request would not be chunked otherwise.
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+
+		return nil
+	}
+}
+
+func (l *LocalClient) iRequestWithTrailer(ctx context.Context, service, key, value string) (context.Context, error) {
+	c, ctx, err := l.Service(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, rv, err := replaceVars(ctx, l.VS, []byte(value))
+	if err != nil {
+		return ctx, fmt.Errorf("failed to replace vars in trailer %s: %w", key, err)
+	}
+
+	recorderOf(c).beforeRequest(withTrailer(key, string(rv)))
+
+	return ctx, nil
+}
+
+func (l *LocalClient) iShouldHaveResponseWithTrailer(ctx context.Context, service, key, value string) (context.Context, error) {
+	ctx, err := l.expectResponseDetails(ctx, service, func(d httpmock.HTTPValue) error {
+		if received := d.Resp.Trailer.Get(key); received != value {
+			return fmt.Errorf("%w %s, expected: %q, received: %q", ErrUnexpectedTrailer, key, value, received)
+		}
+
+		return nil
+	})
+
+	return l.assertion(ctx, err)
+}
@@ -0,0 +1,325 @@
+package httpsteps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bool64/httpmock"
+)
+
+// defaultConcurrencyLevel is a number of concurrent requests used by httpmock.Client if not configured.
+const defaultConcurrencyLevel = 10
+
+// exchange is a recorded HTTP transaction.
+type exchange struct {
+	req      *http.Request
+	resp     *http.Response
+	respBody []byte
+
+	// duration is an elapsed time from sending request to receiving response body.
+	duration time.Duration
+}
+
+// recordingTransport keeps responses received by a forked client and prepares outgoing requests.
+type recordingTransport struct {
+	next http.RoundTripper
+
+	mu          sync.Mutex
+	concurrency int
+	timeout     time.Duration
+	exchanges   []exchange
+	previous    *exchange
+	prepare     []func(req *http.Request) error
+
+	// retryReset enables one retry of GET and HEAD requests failed with connection reset.
+	retryReset bool
+	resets     []string
+
+	// ramp sends waves of concurrent requests before the final one.
+	ramp *ramp
+
+	// service is a name of service, observers receive every sent request with response.
+	service   string
+	observers []func(req RequestInfo, resp ResponseInfo)
+}
+
+func newRecordingTransport(next http.RoundTripper) *recordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &recordingTransport{next: next, concurrency: 1}
+}
+
+// recorderOf returns recording transport of a forked client.
+func recorderOf(c *httpmock.Client) *recordingTransport {
+	if rt, ok := c.Transport.(*recordingTransport); ok {
+		return rt
+	}
+
+	return nil
+}
+
+// RoundTrip sends request and records response.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	prepare := t.prepare
+	timeout := t.timeout
+	t.mu.Unlock()
+
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+
+		req = req.WithContext(ctx)
+	}
+
+	for _, p := range prepare {
+		if err := p(req); err != nil {
+			return nil, err
+		}
+	}
+
+	var info RequestInfo
+
+	if len(t.observers) > 0 {
+		info = RequestInfo{
+			Service: t.service, Method: req.Method, URL: req.URL.String(),
+			Header: req.Header.Clone(), Body: requestBody(req),
+		}
+	}
+
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil && t.canRetry(req, err) {
+		t.mu.Lock()
+		t.resets = append(t.resets, fmt.Sprintf("%s %s: %s, retried", req.Method, req.URL.String(), err.Error()))
+		t.mu.Unlock()
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err = t.next.RoundTrip(req)
+	}
+
+	if err != nil {
+		observe(t.observers, info, ResponseInfo{Duration: time.Since(start), Err: err})
+
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resp.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	duration := time.Since(start)
+
+	// Compressed body is decoded for assertions, Content-Encoding header is kept to check encoding.
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "" {
+		if body, err = decodeBody(encoding, body); err != nil {
+			return nil, err
+		}
+
+		resp.ContentLength = int64(len(body))
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	observe(t.observers, info, ResponseInfo{
+		Status: resp.StatusCode, Header: resp.Header, Body: body, Duration: duration,
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Redirect response is replaced with the response of followed request.
+	if req.Response != nil {
+		for i, e := range t.exchanges {
+			if e.resp == req.Response {
+				t.exchanges = append(t.exchanges[:i], t.exchanges[i+1:]...)
+
+				break
+			}
+		}
+	}
+
+	t.exchanges = append(t.exchanges, exchange{req: req, resp: resp, respBody: body, duration: duration})
+
+	return resp, nil
+}
+
+// reset discards recorded exchanges before a new request.
+func (t *recordingTransport) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n := len(t.exchanges); n > 0 {
+		prev := t.exchanges[n-1]
+		t.previous = &prev
+	}
+
+	t.concurrency = 1
+	t.timeout = 0
+	t.exchanges = nil
+	t.prepare = nil
+	t.retryReset = false
+	t.resets = nil
+	t.ramp = nil
+}
+
+// canRetry checks if failed request is idempotent and was not sent due to connection reset.
+func (t *recordingTransport) canRetry(req *http.Request, err error) bool {
+	t.mu.Lock()
+	retry := t.retryReset
+	t.mu.Unlock()
+
+	if !retry || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryConnectionReset enables one retry of GET and HEAD requests failed with connection reset.
+func (t *recordingTransport) retryConnectionReset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.retryReset = true
+}
+
+// connectionResets returns descriptions of retried connection resets.
+func (t *recordingTransport) connectionResets() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]string(nil), t.resets...)
+}
+
+func (t *recordingTransport) setRamp(r *ramp) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ramp = r
+}
+
+func (t *recordingTransport) getRamp() *ramp {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.ramp
+}
+
+// beforeRequest adds a function to prepare request before sending, e.g. to sign final body.
+func (t *recordingTransport) beforeRequest(f func(req *http.Request) error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prepare = append(t.prepare, f)
+}
+
+// setTimeout limits duration of a single request attempt including reading of response body.
+func (t *recordingTransport) setTimeout(timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timeout = timeout
+}
+
+func (t *recordingTransport) setConcurrency(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n <= 0 {
+		n = defaultConcurrencyLevel
+	}
+
+	t.concurrency = n
+}
+
+// concurrent is true if request is sent concurrently.
+func (t *recordingTransport) concurrent() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.concurrency > 1
+}
+
+// last returns exchanges of the latest attempt.
+func (t *recordingTransport) last() []exchange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := t.concurrency
+	if n > len(t.exchanges) {
+		n = len(t.exchanges)
+	}
+
+	return append([]exchange(nil), t.exchanges[len(t.exchanges)-n:]...)
+}
+
+// latest returns the latest received exchange, including exchanges of previous requests.
+func (t *recordingTransport) latest() (exchange, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n := len(t.exchanges); n > 0 {
+		return t.exchanges[n-1], true
+	}
+
+	if t.previous != nil {
+		return *t.previous, true
+	}
+
+	return exchange{}, false
+}
+
+// concurrentBodies returns bodies of all concurrent responses with same status as the given response.
+//
+// Bodies are sorted to have deterministic order. Nil is returned if request was not concurrent.
+func concurrentBodies(c *httpmock.Client, resp *http.Response) [][]byte {
+	rt := recorderOf(c)
+	if rt == nil || resp == nil {
+		return nil
+	}
+
+	last := rt.last()
+	if len(last) < 2 {
+		return nil
+	}
+
+	var bodies [][]byte
+
+	for _, e := range last {
+		if e.resp.StatusCode == resp.StatusCode {
+			bodies = append(bodies, e.respBody)
+		}
+	}
+
+	sort.Slice(bodies, func(i, j int) bool {
+		return bytes.Compare(bodies[i], bodies[j]) < 0
+	})
+
+	return bodies
+}
@@ -0,0 +1,204 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// isURIPattern is true if path of request URI has wildcard segments, e.g. "/users/{id}/orders" or "/users/*/orders".
+func isURIPattern(requestURI string) bool {
+	path, _, _ := strings.Cut(requestURI, "?")
+
+	return strings.Contains(path, "{") || strings.Contains(path, "*")
+}
+
+// matchURIPattern checks request URI against a pattern and returns values of wildcard segments,
+// named segments are keyed by name, "*" segments are keyed by position (1, 2, ...).
+//
+// Query of request URI must have same parameters as query of pattern.
+func matchURIPattern(pattern, requestURI string) (map[string]string, bool) {
+	pp, pq, _ := strings.Cut(pattern, "?")
+	rp, rq, _ := strings.Cut(requestURI, "?")
+
+	if !queryEqual(pq, rq) {
+		return nil, false
+	}
+
+	ps, rs := strings.Split(pp, "/"), strings.Split(rp, "/")
+	if len(ps) != len(rs) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	n := 0
+
+	for i, seg := range ps {
+		name := ""
+
+		switch {
+		case seg == "*":
+			n++
+			name = strconv.Itoa(n)
+		case len(seg) > 2 && strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			name = seg[1 : len(seg)-1]
+		case seg != rs[i]:
+			return nil, false
+		default:
+			continue
+		}
+
+		if rs[i] == "" {
+			return nil, false
+		}
+
+		val, err := url.PathUnescape(rs[i])
+		if err != nil {
+			val = rs[i]
+		}
+
+		params[name] = val
+	}
+
+	return params, true
+}
+
+// queryEqual compares queries as sets of parameters, so that order of parameters does not matter.
+func queryEqual(expected, received string) bool {
+	if expected == received {
+		return true
+	}
+
+	exp, err := url.ParseQuery(expected)
+	if err != nil {
+		return false
+	}
+
+	rcv, err := url.ParseQuery(received)
+	if err != nil || len(exp) != len(rcv) {
+		return false
+	}
+
+	for k, ev := range exp {
+		rv := rcv[k]
+		if len(rv) != len(ev) {
+			return false
+		}
+
+		ev, rv = append([]string(nil), ev...), append([]string(nil), rv...)
+		sort.Strings(ev)
+		sort.Strings(rv)
+
+		for i := range ev {
+			if ev[i] != rv[i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchURIRegex checks request URI against regular expression and returns values of submatches,
+// named groups are keyed by name, all groups are keyed by position (1, 2, ...).
+func matchURIRegex(re *regexp.Regexp, requestURI string) (map[string]string, bool) {
+	sm := re.FindStringSubmatch(requestURI)
+	if sm == nil {
+		return nil, false
+	}
+
+	params := make(map[string]string, len(sm)-1)
+
+	for i, name := range re.SubexpNames() {
+		if i == 0 {
+			continue
+		}
+
+		params[strconv.Itoa(i)] = sm[i]
+
+		if name != "" {
+			params[name] = sm[i]
+		}
+	}
+
+	return params, true
+}
+
+// routeRequestURI rewrites request URI to URI of the next matching expectation if it only differs in order of
+// query parameters, or if expectation has a pattern with wildcard segments or a regular expression.
+//
+// Values of wildcard segments and submatches are returned to be available as variables (e.g. "$id" or "$1")
+// in JSON response body.
+func (m *mock) routeRequestURI(req *http.Request) map[string]string {
+	for _, e := range m.candidates() {
+		if e.Method != "" && e.Method != req.Method {
+			continue
+		}
+
+		if e.RequestURI == "" || e.RequestURI == req.RequestURI {
+			return nil
+		}
+
+		var (
+			params map[string]string
+			ok     bool
+		)
+
+		switch {
+		case e.uriRegex != nil:
+			params, ok = matchURIRegex(e.uriRegex, req.RequestURI)
+		case isURIPattern(e.RequestURI):
+			params, ok = matchURIPattern(e.RequestURI, req.RequestURI)
+		default:
+			ep, eq, _ := strings.Cut(e.RequestURI, "?")
+			rp, rq, _ := strings.Cut(req.RequestURI, "?")
+			ok = ep == rp && queryEqual(eq, rq)
+		}
+
+		if !ok {
+			continue
+		}
+
+		req.RequestURI = e.RequestURI
+
+		return params
+	}
+
+	return nil
+}
+
+// candidates returns expectations that can serve the next request, in same order of matching as in httpmock.Server.
+func (m *mock) candidates() []PendingExpectation {
+	candidates := m.async
+	if len(m.expectations) > 0 {
+		candidates = append(candidates[:len(candidates):len(candidates)], m.expectations[0])
+	}
+
+	return candidates
+}
+
+func (e *ExternalServer) serviceReceivesRequestMatching(ctx context.Context, service, method, expr string) (context.Context, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: regular expression %q: %s", ErrInvalidValue, expr, err.Error())
+	}
+
+	ctx, err = e.serviceReceivesRequest(ctx, service, method, expr)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx, m, err := e.pending(ctx, service)
+	if err != nil {
+		return ctx, err
+	}
+
+	m.exp.uriRegex = re
+
+	return ctx, nil
+}
@@ -0,0 +1,210 @@
+package httpsteps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/cucumber/godog"
+)
+
+// StepUsage collects statistics of step usage in a test suite to guide maintenance of features,
+// e.g. migrations off deprecated steps.
+//
+// Same instance can be shared by LocalClient and ExternalServer.
+//
+//	usage := &httpsteps.StepUsage{}
+//	local.StepUsage = usage
+//
+//	suite := godog.TestSuite{
+//		TestSuiteInitializer: func(s *godog.TestSuiteContext) {
+//			s.AfterSuite(func() { _ = usage.Report(os.Stdout) })
+//		},
+//		// ...
+//	}
+type StepUsage struct {
+	mu         sync.Mutex
+	uses       map[string]*StepStats
+	deprecated map[string]string
+	registered map[string]struct{}
+}
+
+// StepStats describes usage of a step definition.
+type StepStats struct {
+	// Expression is a regular expression of step definition.
+	Expression string
+
+	// Uses is a number of step invocations.
+	Uses int
+
+	// Features is a sorted list of feature files that use the step.
+	Features []string
+
+	// Deprecation is a migration note of a deprecated step, empty if step is not deprecated.
+	Deprecation string
+
+	features map[string]struct{}
+}
+
+// Deprecate marks step definition as deprecated, note should describe a replacement.
+func (u *StepUsage) Deprecate(expression, note string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.deprecated == nil {
+		u.deprecated = make(map[string]string)
+	}
+
+	u.deprecated[expression] = note
+}
+
+// Stats returns usage of all registered step definitions, most used steps go first.
+func (u *StepUsage) Stats() []StepStats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var res []StepStats
+
+	for expr := range u.registered {
+		st := StepStats{Expression: expr, Deprecation: u.deprecated[expr]}
+
+		if use, ok := u.uses[expr]; ok {
+			st.Uses = use.Uses
+
+			for f := range use.features {
+				st.Features = append(st.Features, f)
+			}
+
+			sort.Strings(st.Features)
+		}
+
+		res = append(res, st)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Uses != res[j].Uses {
+			return res[i].Uses > res[j].Uses
+		}
+
+		return res[i].Expression < res[j].Expression
+	})
+
+	return res
+}
+
+// Report writes number of uses of every step definition and features that use deprecated steps.
+func (u *StepUsage) Report(w io.Writer) error {
+	stats := u.Stats()
+
+	if _, err := fmt.Fprintln(w, "Step usage:"); err != nil {
+		return err
+	}
+
+	for _, st := range stats {
+		if _, err := fmt.Fprintf(w, "%6d %s\n", st.Uses, st.Expression); err != nil {
+			return err
+		}
+	}
+
+	header := false
+
+	for _, st := range stats {
+		if st.Deprecation == "" || st.Uses == 0 {
+			continue
+		}
+
+		if !header {
+			header = true
+
+			if _, err := fmt.Fprintln(w, "\nDeprecated steps in use:"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s (%s)\n", st.Expression, st.Deprecation); err != nil {
+			return err
+		}
+
+		for _, f := range st.Features {
+			if _, err := fmt.Fprintf(w, "    %s\n", f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// register adds step definition to statistics.
+func (u *StepUsage) register(expr string) {
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.registered == nil {
+		u.registered = make(map[string]struct{})
+	}
+
+	u.registered[expr] = struct{}{}
+}
+
+// reset discards statistics and registered steps, deprecation notes are kept.
+func (u *StepUsage) reset() {
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.uses = nil
+	u.registered = nil
+}
+
+type stepUsageCtxKey struct{}
+
+// stepUsageScope is a step usage collector of a running scenario.
+type stepUsageScope struct {
+	usage   *StepUsage
+	feature string
+}
+
+// beforeScenario puts step usage collector in context.
+func (u *StepUsage) beforeScenario(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+	if u == nil {
+		return ctx, nil
+	}
+
+	return context.WithValue(ctx, stepUsageCtxKey{}, stepUsageScope{usage: u, feature: sc.Uri}), nil
+}
+
+// stepUsed records invocation of step definition if step usage is collected.
+func stepUsed(ctx context.Context, expr string) {
+	scope, ok := ctx.Value(stepUsageCtxKey{}).(stepUsageScope)
+	if !ok {
+		return
+	}
+
+	u := scope.usage
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.uses == nil {
+		u.uses = make(map[string]*StepStats)
+	}
+
+	use, ok := u.uses[expr]
+	if !ok {
+		use = &StepStats{features: make(map[string]struct{})}
+		u.uses[expr] = use
+	}
+
+	use.Uses++
+	use.features[scope.feature] = struct{}{}
+}
@@ -0,0 +1,61 @@
+package httpsteps_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/godogx/httpsteps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("ok"))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	usage := &httpsteps.StepUsage{}
+	usage.Deprecate(`^I should have(.*) response with body$`, "use response profiles")
+
+	local := httpsteps.NewLocalClient(srv.URL)
+	local.StepUsage = usage
+
+	report := bytes.NewBuffer(nil)
+
+	suite := godog.TestSuite{
+		TestSuiteInitializer: func(s *godog.TestSuiteContext) {
+			s.AfterSuite(func() {
+				require.NoError(t, usage.Report(report))
+			})
+		},
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			local.RegisterSteps(s)
+		},
+		Options: &godog.Options{
+			Output:   bytes.NewBuffer(nil),
+			Format:   "pretty",
+			NoColors: true,
+			Strict:   true,
+			Paths:    []string{"_testdata/Usage.feature"},
+		},
+	}
+
+	assert.Equal(t, 0, suite.Run())
+
+	stats := usage.Stats()
+	require.NotEmpty(t, stats)
+	assert.Equal(t, `^I request(.*) HTTP endpoint with method "([^"]*)" and URI (.*)$`, stats[0].Expression)
+	assert.Equal(t, 2, stats[0].Uses)
+	assert.Equal(t, []string{"_testdata/Usage.feature"}, stats[0].Features)
+
+	assert.Contains(t, report.String(), `     2 ^I should have(.*) response with status "([^"]*)"$`)
+	assert.Contains(t, report.String(), `     0 ^I should have(.*) response with trailer "([^"]*): ([^"]*)"$`)
+	assert.Contains(t, report.String(), "Deprecated steps in use:\n"+
+		"^I should have(.*) response with body$ (use response profiles)\n"+
+		"    _testdata/Usage.feature\n")
+}
@@ -0,0 +1,50 @@
+package httpsteps
+
+import (
+	"context"
+
+	"github.com/cucumber/godog"
+)
+
+// WarningsTag is a scenario or feature tag to report failed response assertions as warnings.
+const WarningsTag = "@warnings"
+
+// warningsCtxKey marks failed response assertions as warnings.
+type warningsCtxKey struct{}
+
+func (l *LocalClient) warningsTag(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+	for _, tag := range sc.Tags {
+		if tag.Name == WarningsTag {
+			return context.WithValue(ctx, warningsCtxKey{}, true), nil
+		}
+	}
+
+	return ctx, nil
+}
+
+func (l *LocalClient) failedResponseAssertionsAre(ctx context.Context, severity string) context.Context {
+	return context.WithValue(ctx, warningsCtxKey{}, severity == "warnings")
+}
+
+// assertion reports failed response assertion as a warning if warnings are enabled for the scenario.
+//
+// Warning is attached to the scenario as godog.Attachment and passed to OnWarning.
+func (l *LocalClient) assertion(ctx context.Context, err error) (context.Context, error) {
+	if err == nil {
+		return ctx, nil
+	}
+
+	if warn, _ := ctx.Value(warningsCtxKey{}).(bool); !warn {
+		return ctx, err
+	}
+
+	if l.OnWarning != nil {
+		l.OnWarning(ctx, err)
+	}
+
+	return godog.Attach(ctx, godog.Attachment{
+		Body:      []byte(err.Error()),
+		FileName:  "warning",
+		MediaType: "text/plain",
+	}), nil
+}
@@ -0,0 +1,336 @@
+package httpsteps
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bool64/httpmock"
+)
+
+// wireMockDefaultPriority is a priority of WireMock mapping without explicit priority.
+const wireMockDefaultPriority = 5
+
+// wireMockMapping is a stub mapping of WireMock.
+type wireMockMapping struct {
+	Priority int              `json:"priority,omitempty"`
+	Request  wireMockRequest  `json:"request"`
+	Response wireMockResponse `json:"response"`
+}
+
+type wireMockRequest struct {
+	Method         string                     `json:"method,omitempty"`
+	URL            string                     `json:"url,omitempty"`
+	URLPath        string                     `json:"urlPath,omitempty"`
+	URLPattern     string                     `json:"urlPattern,omitempty"`
+	URLPathPattern string                     `json:"urlPathPattern,omitempty"`
+	Headers        map[string]wireMockMatcher `json:"headers,omitempty"`
+	Cookies        map[string]wireMockMatcher `json:"cookies,omitempty"`
+	BodyPatterns   []wireMockMatcher          `json:"bodyPatterns,omitempty"`
+
+	QueryParameters map[string]json.RawMessage `json:"queryParameters,omitempty"`
+}
+
+type wireMockResponse struct {
+	Status       int               `json:"status,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Body         string            `json:"body,omitempty"`
+	JSONBody     json.RawMessage   `json:"jsonBody,omitempty"`
+	Base64Body   string            `json:"base64Body,omitempty"`
+	BodyFileName string            `json:"bodyFileName,omitempty"`
+
+	FixedDelayMilliseconds int64  `json:"fixedDelayMilliseconds,omitempty"`
+	Fault                  string `json:"fault,omitempty"`
+}
+
+// wireMockMatcher is a value matcher of WireMock, only equality matchers are supported by LoadWireMockStubs.
+type wireMockMatcher struct {
+	EqualTo             *string           `json:"equalTo,omitempty"`
+	EqualToJSON         json.RawMessage   `json:"equalToJson,omitempty"`
+	IgnoreExtraElements bool              `json:"ignoreExtraElements,omitempty"`
+	MatchesJSONPath     *wireMockJSONPath `json:"matchesJsonPath,omitempty"`
+}
+
+// wireMockJSONPath matches value of JSON path in body.
+type wireMockJSONPath struct {
+	Expression  string          `json:"expression"`
+	EqualTo     *string         `json:"equalTo,omitempty"`
+	EqualToJSON json.RawMessage `json:"equalToJson,omitempty"`
+}
+
+// UnmarshalJSON fails on matchers that can not be converted to expectation.
+func (m *wireMockMatcher) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	for k, v := range raw {
+		switch k {
+		case "equalTo":
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+
+			m.EqualTo = &s
+		case "equalToJson":
+			m.EqualToJSON = v
+		case "ignoreExtraElements":
+			if string(v) != "false" {
+				return fmt.Errorf("%w: ignoreExtraElements", ErrUnsupportedMapping)
+			}
+		default:
+			return fmt.Errorf("%w: matcher %s", ErrUnsupportedMapping, k)
+		}
+	}
+
+	return nil
+}
+
+// LoadWireMockStubs reads WireMock stub mappings from JSON files or directories with JSON files and converts them
+// to stubs for ExternalServer.WithStubs option.
+//
+// A file may contain a single mapping or a list of mappings in "mappings" property. Response body referred by
+// "bodyFileName" is loaded from "__files" directory next to mappings directory, as WireMock does.
+// Requests are matched with "url", "urlPath", "urlPattern" or "urlPathPattern", by method, by headers and cookies
+// with "equalTo" and by body with "equalTo" or "equalToJson", other matchers are not supported.
+func LoadWireMockStubs(paths ...string) ([]Stub, error) {
+	type prioritized struct {
+		priority int
+		stub     Stub
+	}
+
+	var res []prioritized
+
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		// Files directory is a sibling of mappings directory.
+		filesDir := filepath.Join(filepath.Dir(p), "__files")
+		if !fi.IsDir() {
+			filesDir = filepath.Join(filepath.Dir(filepath.Dir(p)), "__files")
+		}
+
+		err = filepath.WalkDir(p, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() || (filePath != p && filepath.Ext(filePath) != ".json") {
+				return nil
+			}
+
+			mappings, err := loadWireMockMappings(filePath)
+			if err != nil {
+				return err
+			}
+
+			for _, mp := range mappings {
+				s, err := mp.stub(filesDir)
+				if err != nil {
+					return fmt.Errorf("failed to convert mapping %s %s in %s: %w",
+						mp.Request.Method, mp.Request.url(), filePath, err)
+				}
+
+				priority := mp.Priority
+				if priority == 0 {
+					priority = wireMockDefaultPriority
+				}
+
+				res = append(res, prioritized{priority: priority, stub: s})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Lower number means higher priority in WireMock.
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].priority < res[j].priority
+	})
+
+	stubs := make([]Stub, 0, len(res))
+	for _, r := range res {
+		stubs = append(stubs, r.stub)
+	}
+
+	return stubs, nil
+}
+
+// loadWireMockMappings reads a file with a single mapping or with a list of mappings.
+func loadWireMockMappings(filePath string) ([]wireMockMapping, error) {
+	data, err := os.ReadFile(filePath) //nolint:gosec // File inclusion via variable during tests.
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		raw      map[string]json.RawMessage
+		mappings []wireMockMapping
+	)
+
+	if json.Unmarshal(data, &raw) == nil && raw["mappings"] != nil {
+		err = json.Unmarshal(raw["mappings"], &mappings)
+	} else {
+		mappings = make([]wireMockMapping, 1)
+		err = json.Unmarshal(data, &mappings[0])
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load WireMock mappings %s: %w", filePath, err)
+	}
+
+	return mappings, nil
+}
+
+// url returns URL matcher of request.
+func (r wireMockRequest) url() string {
+	for _, u := range []string{r.URL, r.URLPath, r.URLPattern, r.URLPathPattern} {
+		if u != "" {
+			return u
+		}
+	}
+
+	return ""
+}
+
+// stub converts mapping, filesDir is a directory of response body files.
+func (mp wireMockMapping) stub(filesDir string) (Stub, error) {
+	var (
+		s   Stub
+		err error
+		req = mp.Request
+	)
+
+	if len(req.QueryParameters) > 0 {
+		return s, fmt.Errorf("%w: queryParameters, use query in url instead", ErrUnsupportedMapping)
+	}
+
+	if req.Method != "ANY" {
+		s.Method = req.Method
+	}
+
+	// Path matchers allow any query.
+	switch {
+	case req.URL != "":
+		s.RequestURI = req.URL
+	case req.URLPath != "":
+		s.URIRegex, err = regexp.Compile(`^` + regexp.QuoteMeta(req.URLPath) + `(\?.*)?$`)
+	case req.URLPattern != "":
+		s.URIRegex, err = regexp.Compile(`^(?:` + req.URLPattern + `)$`)
+	case req.URLPathPattern != "":
+		s.URIRegex, err = regexp.Compile(`^(?:` + req.URLPathPattern + `)(\?.*)?$`)
+	}
+
+	if err != nil {
+		return s, fmt.Errorf("%w: url pattern: %s", ErrInvalidValue, err.Error())
+	}
+
+	if s.RequestHeader, err = equalToValues("header", req.Headers); err != nil {
+		return s, err
+	}
+
+	if s.RequestCookie, err = equalToValues("cookie", req.Cookies); err != nil {
+		return s, err
+	}
+
+	switch {
+	case len(req.BodyPatterns) > 1:
+		return s, fmt.Errorf("%w: multiple bodyPatterns", ErrUnsupportedMapping)
+	case len(req.BodyPatterns) == 1:
+		if s.RequestBody, err = req.BodyPatterns[0].body(); err != nil {
+			return s, err
+		}
+	}
+
+	return s, mp.Response.expect(&s.Expectation, filesDir)
+}
+
+// equalToValues converts "equalTo" matchers to values.
+func equalToValues(kind string, matchers map[string]wireMockMatcher) (map[string]string, error) {
+	if len(matchers) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]string, len(matchers))
+
+	for name, m := range matchers {
+		if m.EqualTo == nil {
+			return nil, fmt.Errorf("%w: %s %s without equalTo", ErrUnsupportedMapping, kind, name)
+		}
+
+		res[name] = *m.EqualTo
+	}
+
+	return res, nil
+}
+
+// body returns expected request body.
+func (m wireMockMatcher) body() ([]byte, error) {
+	if m.EqualTo != nil {
+		return []byte(*m.EqualTo), nil
+	}
+
+	if len(m.EqualToJSON) == 0 {
+		return nil, fmt.Errorf("%w: body pattern without equalTo or equalToJson", ErrUnsupportedMapping)
+	}
+
+	// JSON can be defined as a string.
+	var s string
+	if err := json.Unmarshal(m.EqualToJSON, &s); err == nil {
+		return []byte(s), nil
+	}
+
+	return m.EqualToJSON, nil
+}
+
+// expect sets response of expectation.
+func (r wireMockResponse) expect(e *httpmock.Expectation, filesDir string) error {
+	if r.Fault != "" {
+		return fmt.Errorf("%w: fault %s", ErrUnsupportedMapping, r.Fault)
+	}
+
+	e.Status = r.Status
+	if e.Status == 0 {
+		e.Status = http.StatusOK
+	}
+
+	e.ResponseHeader = r.Headers
+
+	switch {
+	case r.Body != "":
+		e.ResponseBody = []byte(r.Body)
+	case len(r.JSONBody) > 0:
+		e.ResponseBody = r.JSONBody
+	case r.Base64Body != "":
+		body, err := base64.StdEncoding.DecodeString(r.Base64Body)
+		if err != nil {
+			return fmt.Errorf("%w: base64Body: %s", ErrInvalidValue, err.Error())
+		}
+
+		e.ResponseBody = body
+	case r.BodyFileName != "":
+		body, err := os.ReadFile(filepath.Join(filesDir, filepath.FromSlash(strings.TrimPrefix(r.BodyFileName, "/"))))
+		if err != nil {
+			return err
+		}
+
+		e.ResponseBody = body
+	}
+
+	return nil
+}
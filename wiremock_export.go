@@ -0,0 +1,272 @@
+package httpsteps
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// WireMockExport collects expectations of external services that are defined with steps to export them
+// as WireMock stub mappings, e.g. for reuse by test suites of other projects.
+//
+// Conditional responses are exported as mappings with higher priority, URI patterns and regular expressions
+// are exported as "urlPattern". Expectations served with registered handlers and expectations with faults
+// other than connection reset are skipped.
+//
+//	export := &httpsteps.WireMockExport{}
+//	external.WireMockExport = export
+//
+//	suite := godog.TestSuite{
+//		TestSuiteInitializer: func(s *godog.TestSuiteContext) {
+//			s.AfterSuite(func() { _ = export.WriteFiles("wiremock/mappings") })
+//		},
+//		// ...
+//	}
+type WireMockExport struct {
+	mu       sync.Mutex
+	mappings map[string][]wireMockMapping
+	seen     map[string]struct{}
+}
+
+// Services returns names of services with collected expectations.
+func (x *WireMockExport) Services() []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	res := make([]string, 0, len(x.mappings))
+	for service := range x.mappings {
+		res = append(res, service)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// Mappings returns collected expectations of a service as WireMock JSON with a list of mappings.
+func (x *WireMockExport) Mappings(service string) ([]byte, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	mappings := x.mappings[service]
+	if mappings == nil {
+		mappings = []wireMockMapping{}
+	}
+
+	return json.MarshalIndent(struct {
+		Mappings []wireMockMapping `json:"mappings"`
+	}{Mappings: mappings}, "", "  ")
+}
+
+// WriteFiles saves mappings of every service to <service>.json file in a directory.
+func (x *WireMockExport) WriteFiles(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	for _, service := range x.Services() {
+		data, err := x.Mappings(service)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, service+".json"), data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collect adds mappings of expectation, identical mappings are only added once.
+func (x *WireMockExport) collect(service string, e exp) {
+	if e.handler != nil || (e.fault != nil && e.fault.kind != faultReset) {
+		return
+	}
+
+	mappings := append(wireMockBranches(e), wireMockExpectation(e))
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.mappings == nil {
+		x.mappings = make(map[string][]wireMockMapping)
+		x.seen = make(map[string]struct{})
+	}
+
+	for _, mp := range mappings {
+		data, err := json.Marshal(mp)
+		if err != nil {
+			continue
+		}
+
+		key := service + " " + string(data)
+		if _, ok := x.seen[key]; ok {
+			continue
+		}
+
+		x.seen[key] = struct{}{}
+		x.mappings[service] = append(x.mappings[service], mp)
+	}
+}
+
+// wireMockExpectation converts expectation to mapping.
+func wireMockExpectation(e exp) wireMockMapping {
+	mp := wireMockMapping{Request: wireMockRequestOf(e)}
+
+	for k, v := range e.RequestHeader {
+		mp.Request.Headers[k] = wireMockEqualTo(v)
+	}
+
+	for k, v := range e.RequestCookie {
+		mp.Request.Cookies[k] = wireMockEqualTo(v)
+	}
+
+	mp.Request.BodyPatterns = wireMockBodyPatterns(e)
+
+	mp.Response = wireMockResponseOf(e.Status, e.ResponseHeader, e.ResponseBody)
+	mp.Response.FixedDelayMilliseconds = e.delay.Milliseconds()
+
+	if e.fault != nil {
+		mp.Response = wireMockResponse{Fault: "CONNECTION_RESET_BY_PEER"}
+	}
+
+	return mp
+}
+
+// wireMockBranches converts conditional responses to mappings, they have priority over regular response.
+func wireMockBranches(e exp) []wireMockMapping {
+	res := make([]wireMockMapping, 0, len(e.branches))
+
+	for _, b := range e.branches {
+		mp := wireMockMapping{Priority: 1, Request: wireMockRequestOf(e)}
+
+		if b.header != "" {
+			mp.Request.Headers[b.header] = wireMockEqualTo(b.value)
+		} else {
+			v := b.value
+			mp.Request.BodyPatterns = []wireMockMatcher{{
+				MatchesJSONPath: &wireMockJSONPath{Expression: b.jsonPath, EqualTo: &v},
+			}}
+		}
+
+		mp.Response = wireMockResponseOf(b.status, nil, b.body)
+
+		res = append(res, mp)
+	}
+
+	return res
+}
+
+// wireMockRequestOf converts method and request URI of expectation.
+func wireMockRequestOf(e exp) wireMockRequest {
+	r := wireMockRequest{
+		Method:  e.Method,
+		Headers: map[string]wireMockMatcher{},
+		Cookies: map[string]wireMockMatcher{},
+	}
+
+	if r.Method == "" {
+		r.Method = "ANY"
+	}
+
+	switch {
+	case e.uriRegex != nil:
+		// WireMock matches whole URL with pattern.
+		re := e.uriRegex.String()
+		if !strings.HasPrefix(re, "^") || !strings.HasSuffix(re, "$") {
+			re = ".*(?:" + re + ").*"
+		}
+
+		r.URLPattern = re
+	case isURIPattern(e.RequestURI):
+		r.URLPattern = wireMockURIPattern(e.RequestURI)
+	default:
+		r.URL = e.RequestURI
+	}
+
+	return r
+}
+
+// wireMockURIPattern converts URI pattern with wildcard segments to regular expression.
+func wireMockURIPattern(pattern string) string {
+	path, query, hasQuery := strings.Cut(pattern, "?")
+	segments := strings.Split(path, "/")
+
+	for i, seg := range segments {
+		if seg == "*" || (len(seg) > 2 && strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")) {
+			segments[i] = "[^/]+"
+		} else {
+			segments[i] = regexp.QuoteMeta(seg)
+		}
+	}
+
+	re := strings.Join(segments, "/")
+	if hasQuery {
+		re += `\?` + regexp.QuoteMeta(query)
+	}
+
+	return re
+}
+
+// wireMockBodyPatterns converts expected request body.
+func wireMockBodyPatterns(e exp) []wireMockMatcher {
+	if e.RequestBody == nil {
+		return nil
+	}
+
+	if e.jsonPaths {
+		var paths map[string]json.RawMessage
+		if err := json.Unmarshal(e.RequestBody, &paths); err != nil {
+			return nil
+		}
+
+		res := make([]wireMockMatcher, 0, len(paths))
+		for path, val := range paths {
+			res = append(res, wireMockMatcher{MatchesJSONPath: &wireMockJSONPath{Expression: path, EqualToJSON: val}})
+		}
+
+		sort.Slice(res, func(i, j int) bool {
+			return res[i].MatchesJSONPath.Expression < res[j].MatchesJSONPath.Expression
+		})
+
+		return res
+	}
+
+	if !e.form && json.Valid(e.RequestBody) {
+		return []wireMockMatcher{{EqualToJSON: e.RequestBody, IgnoreExtraElements: e.include || e.matchJSON}}
+	}
+
+	return []wireMockMatcher{wireMockEqualTo(string(e.RequestBody))}
+}
+
+// wireMockResponseOf converts response, JSON body is kept as JSON and binary body is encoded with base64.
+func wireMockResponseOf(status int, header map[string]string, body []byte) wireMockResponse {
+	r := wireMockResponse{Status: status, Headers: header}
+
+	if len(header) == 0 {
+		r.Headers = nil
+	}
+
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		r.JSONBody = body
+	case utf8.Valid(body):
+		r.Body = string(body)
+	default:
+		r.Base64Body = base64.StdEncoding.EncodeToString(body)
+	}
+
+	return r
+}
+
+func wireMockEqualTo(value string) wireMockMatcher {
+	return wireMockMatcher{EqualTo: &value}
+}